@@ -0,0 +1,127 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CommitStatusState defines the state reported for a commit.
+type CommitStatusState string
+
+var (
+	// CommitStatusStatePending reports that the validation is still in progress.
+	CommitStatusStatePending CommitStatusState = "pending"
+	// CommitStatusStateSuccess reports that the validation passed.
+	CommitStatusStateSuccess CommitStatusState = "success"
+	// CommitStatusStateFailure reports that the validation failed.
+	CommitStatusStateFailure CommitStatusState = "failure"
+	// CommitStatusStateError reports that the validation could not be performed.
+	CommitStatusStateError CommitStatusState = "error"
+)
+
+// CommitStatusSpec defines the desired state of CommitStatus.
+type CommitStatusSpec struct {
+	// SyncRef points to the Sync which created the pull request the status is reported for.
+	//+required
+	SyncRef v1.LocalObjectReference `json:"syncRef"`
+	//+required
+	//+kubebuilder:validation:Enum=pending;success;failure;error
+	State CommitStatusState `json:"state"`
+	// Context is the name of the check. It defaults to the check used for branch protection.
+	//+optional
+	//+kubebuilder:default:=mpas/validation-check
+	Context string `json:"context,omitempty"`
+	//+optional
+	Description string `json:"description,omitempty"`
+	// TargetURL links to the details of the validation.
+	//+optional
+	TargetURL string `json:"targetURL,omitempty"`
+}
+
+// CommitStatusStatus defines the observed state of CommitStatus.
+type CommitStatusStatus struct {
+	// ObservedGeneration is the last reconciled generation.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// PullRequestID is the pull request the state was last reported for.
+	// +optional
+	PullRequestID int `json:"pullRequestID,omitempty"`
+
+	// ReportedState is the state which was last reported to the provider.
+	// +optional
+	ReportedState CommitStatusState `json:"reportedState,omitempty"`
+
+	// ReportedCommit is the commit of the Sync the state was last reported for.
+	// +optional
+	ReportedCommit string `json:"reportedCommit,omitempty"`
+}
+
+// GetContext returns the name of the check defaulting to StatusCheckName.
+func (in CommitStatus) GetContext() string {
+	if in.Spec.Context == "" {
+		return StatusCheckName
+	}
+
+	return in.Spec.Context
+}
+
+func (in *CommitStatus) GetVID() map[string]string {
+	metadata := make(map[string]string)
+	metadata[GroupVersion.Group+"/commitstatus"] = fmt.Sprintf("%d:%s", in.Status.PullRequestID, in.Status.ReportedState)
+
+	return metadata
+}
+
+func (in *CommitStatus) SetObservedGeneration(v int64) {
+	in.Status.ObservedGeneration = v
+}
+
+// GetConditions returns the conditions of the CommitStatus.
+func (in *CommitStatus) GetConditions() []metav1.Condition {
+	return in.Status.Conditions
+}
+
+// SetConditions sets the conditions of the CommitStatus.
+func (in *CommitStatus) SetConditions(conditions []metav1.Condition) {
+	in.Status.Conditions = conditions
+}
+
+// GetRequeueAfter returns the duration after which the CommitStatus must be
+// reconciled again. Commit statuses are only reported on change.
+func (in CommitStatus) GetRequeueAfter() time.Duration {
+	return 0
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description=""
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].message",description=""
+
+// CommitStatus is the Schema for the commitstatuses API.
+type CommitStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CommitStatusSpec   `json:"spec,omitempty"`
+	Status CommitStatusStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CommitStatusList contains a list of CommitStatus.
+type CommitStatusList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CommitStatus `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CommitStatus{}, &CommitStatusList{})
+}
@@ -15,4 +15,16 @@ const (
 
 	// CreatePullRequestFailedReason is used when creating a pull request failed.
 	CreatePullRequestFailedReason = "CreatePullRequestFailed"
+
+	// SyncGetFailedReason is used when the referenced sync does not exist.
+	SyncGetFailedReason = "SyncGetFailed"
+
+	// PullRequestMissingReason is used when the referenced sync has not created a pull request yet.
+	PullRequestMissingReason = "PullRequestMissing"
+
+	// CommitStatusUpdateFailedReason is used when setting the status of a commit failed.
+	CommitStatusUpdateFailedReason = "CommitStatusUpdateFailed"
+
+	// AccessDeniedReason is used when the referenced repository doesn't allow access from the Sync's namespace.
+	AccessDeniedReason = "AccessDenied"
 )
@@ -8,3 +8,15 @@ const (
 const (
 	LevelDebug = 4
 )
+
+// Keys of the metadata attached to the events of a Sync. GetVID prefixes them with the API group, because
+// notification-controller only forwards metadata prefixed with the group of the involved object.
+const (
+	CommitMetadataKey           = "commit"
+	CommitURLMetadataKey        = "commit_url"
+	BranchMetadataKey           = "branch"
+	PullRequestMetadataKey      = "pull_request"
+	PullRequestURLMetadataKey   = "pull_request_url"
+	ComponentMetadataKey        = "component"
+	ComponentVersionMetadataKey = "component_version"
+)
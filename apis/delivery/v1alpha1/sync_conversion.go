@@ -0,0 +1,4 @@
+package v1alpha1
+
+// Hub marks v1alpha1 as the version other Sync versions are converted to and from.
+func (*Sync) Hub() {}
@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/fluxcd/pkg/apis/meta"
@@ -29,14 +30,54 @@ type PullRequestTemplate struct {
 	Base        string `json:"base,omitempty"`
 }
 
+// Kinds of sources a Sync can push.
+const (
+	SnapshotKind      = "Snapshot"
+	OCIRepositoryKind = "OCIRepository"
+	BucketKind        = "Bucket"
+	GitRepositoryKind = "GitRepository"
+	ConfigMapKind     = "ConfigMap"
+)
+
+// SourceReference points to an object in the Sync's namespace providing the content to push. Flux sources are
+// pushed with the content of their latest artifact, ConfigMaps with one file per key.
+type SourceReference struct {
+	//+kubebuilder:validation:Enum=Snapshot;OCIRepository;Bucket;GitRepository;ConfigMap
+	//+required
+	Kind string `json:"kind"`
+	//+required
+	Name string `json:"name"`
+}
+
+// SnapshotPath places the content of a snapshot or of another source at a path of the repository. Either
+// snapshotRef or sourceRef must be set.
+type SnapshotPath struct {
+	//+optional
+	SnapshotRef v1.LocalObjectReference `json:"snapshotRef,omitempty"`
+	//+optional
+	SourceRef *SourceReference `json:"sourceRef,omitempty"`
+	//+required
+	SubPath string `json:"subPath"`
+}
+
 // SyncSpec defines the desired state of Sync.
 type SyncSpec struct {
-	SnapshotRef    v1.LocalObjectReference        `json:"snapshotRef"`
+	//+optional
+	SnapshotRef v1.LocalObjectReference `json:"snapshotRef,omitempty"`
+	// SourceRef pushes the content of a Flux source or a ConfigMap instead of a snapshot.
+	//+optional
+	SourceRef      *SourceReference               `json:"sourceRef,omitempty"`
 	RepositoryRef  meta.NamespacedObjectReference `json:"repositoryRef"`
 	Interval       metav1.Duration                `json:"interval"`
 	CommitTemplate CommitTemplate                 `json:"commitTemplate"`
-	SubPath        string                         `json:"subPath"`
-	Prune          bool                           `json:"prune,omitempty"`
+	//+optional
+	SubPath string `json:"subPath,omitempty"`
+	Prune   bool   `json:"prune,omitempty"`
+
+	// Snapshots extracts several snapshots or sources into one commit, each into its own path. Can't be combined
+	// with snapshotRef, sourceRef and subPath.
+	//+optional
+	Snapshots []SnapshotPath `json:"snapshots,omitempty"`
 
 	//+optional
 	AutomaticPullRequestCreation bool `json:"automaticPullRequestCreation,omitempty"`
@@ -44,10 +85,26 @@ type SyncSpec struct {
 	PullRequestTemplate PullRequestTemplate `json:"pullRequestTemplate,omitempty"`
 }
 
+// SnapshotDigest is the digest of a snapshot or source pushed by a Sync.
+type SnapshotDigest struct {
+	// Kind is the kind of the source. It is empty for snapshots.
+	// +optional
+	Kind    string `json:"kind,omitempty"`
+	Name    string `json:"name"`
+	SubPath string `json:"subPath"`
+	Digest  string `json:"digest"`
+}
+
 // SyncStatus defines the observed state of Sync.
 type SyncStatus struct {
+	// Digest is the digest of the pushed snapshot. If several snapshots are pushed, it is calculated from the
+	// digests of all of them.
 	Digest string `json:"digest,omitempty"`
 
+	// Snapshots lists the digest of every pushed snapshot.
+	// +optional
+	Snapshots []SnapshotDigest `json:"snapshots,omitempty"`
+
 	// ObservedGeneration is the last reconciled generation.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
@@ -58,13 +115,69 @@ type SyncStatus struct {
 
 	// +optional
 	PullRequestID int `json:"pullRequestID,omitempty"`
+
+	// PullRequestURL links to the pull request opened for the last pushed commit.
+	// +optional
+	PullRequestURL string `json:"pullRequestURL,omitempty"`
+
+	// Commit is the SHA of the last pushed commit.
+	// +optional
+	Commit string `json:"commit,omitempty"`
+
+	// CommitURL links to the last pushed commit on the web page of the repository.
+	// +optional
+	CommitURL string `json:"commitURL,omitempty"`
+
+	// Branch is the branch the last commit was pushed to.
+	// +optional
+	Branch string `json:"branch,omitempty"`
+
+	// ComponentName is the name of the component the pushed snapshot belongs to.
+	// +optional
+	ComponentName string `json:"componentName,omitempty"`
+
+	// ComponentVersion is the version of the component the pushed snapshot belongs to.
+	// +optional
+	ComponentVersion string `json:"componentVersion,omitempty"`
 }
 
+// GetSnapshots returns the snapshots and sources to push. A single snapshotRef or sourceRef is returned as a list
+// with one entry.
+func (in *Sync) GetSnapshots() []SnapshotPath {
+	if len(in.Spec.Snapshots) > 0 {
+		return in.Spec.Snapshots
+	}
+
+	return []SnapshotPath{{SnapshotRef: in.Spec.SnapshotRef, SourceRef: in.Spec.SourceRef, SubPath: in.Spec.SubPath}}
+}
+
+// GetVID identifies the last pushed change of the Sync. Besides the `<pullRequestID>:<digest>` identifier, the
+// metadata describes the commit, the pull request and the component, so alerts can link to the change. Empty values
+// are left out.
 func (in *Sync) GetVID() map[string]string {
 	vid := fmt.Sprintf("%d:%s", in.Status.PullRequestID, in.Status.Digest)
 	metadata := make(map[string]string)
 	metadata[GroupVersion.Group+"/sync"] = vid
 
+	values := map[string]string{
+		CommitMetadataKey:           in.Status.Commit,
+		CommitURLMetadataKey:        in.Status.CommitURL,
+		BranchMetadataKey:           in.Status.Branch,
+		PullRequestURLMetadataKey:   in.Status.PullRequestURL,
+		ComponentMetadataKey:        in.Status.ComponentName,
+		ComponentVersionMetadataKey: in.Status.ComponentVersion,
+	}
+
+	if in.Status.PullRequestID > 0 {
+		values[PullRequestMetadataKey] = strconv.Itoa(in.Status.PullRequestID)
+	}
+
+	for key, value := range values {
+		if value != "" {
+			metadata[GroupVersion.Group+"/"+key] = value
+		}
+	}
+
 	return metadata
 }
 
@@ -90,6 +203,7 @@ func (in Sync) GetRequeueAfter() time.Duration {
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:storageversion
 
 // Sync is the Schema for the syncs API.
 type Sync struct {
@@ -0,0 +1,36 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncGetVID(t *testing.T) {
+	obj := &Sync{
+		Status: SyncStatus{
+			Digest:           "sha256:abc",
+			PullRequestID:    7,
+			PullRequestURL:   "https://github.com/open-component-model/podinfo/pull/7",
+			Commit:           "2f0e0c1d",
+			CommitURL:        "https://github.com/open-component-model/podinfo/commit/2f0e0c1d",
+			Branch:           "branch-1700000000",
+			ComponentName:    "ocm.software/podinfo",
+			ComponentVersion: "v6.3.5",
+		},
+	}
+
+	assert.Equal(t, map[string]string{
+		"delivery.ocm.software/sync":              "7:sha256:abc",
+		"delivery.ocm.software/commit":            "2f0e0c1d",
+		"delivery.ocm.software/commit_url":        "https://github.com/open-component-model/podinfo/commit/2f0e0c1d",
+		"delivery.ocm.software/branch":            "branch-1700000000",
+		"delivery.ocm.software/pull_request":      "7",
+		"delivery.ocm.software/pull_request_url":  "https://github.com/open-component-model/podinfo/pull/7",
+		"delivery.ocm.software/component":         "ocm.software/podinfo",
+		"delivery.ocm.software/component_version": "v6.3.5",
+	}, obj.GetVID())
+
+	empty := &Sync{}
+	assert.Equal(t, map[string]string{"delivery.ocm.software/sync": "0:"}, empty.GetVID())
+}
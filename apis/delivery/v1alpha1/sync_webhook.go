@@ -0,0 +1,153 @@
+package v1alpha1
+
+import (
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// DefaultBaseBranch is the branch a Sync starts from if the commit template doesn't define one.
+const DefaultBaseBranch = "main"
+
+// SetupWebhookWithManager registers the defaulting and validating webhooks of Sync.
+func (in *Sync) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(in).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-delivery-ocm-software-v1alpha1-sync,mutating=true,failurePolicy=fail,sideEffects=None,groups=delivery.ocm.software,resources=syncs,verbs=create;update,versions=v1alpha1,name=msync.delivery.ocm.software,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &Sync{}
+
+// Default sets the base branch of the commit template.
+func (in *Sync) Default() {
+	if in.Spec.CommitTemplate.BaseBranch == "" {
+		in.Spec.CommitTemplate.BaseBranch = DefaultBaseBranch
+	}
+}
+
+//+kubebuilder:webhook:path=/validate-delivery-ocm-software-v1alpha1-sync,mutating=false,failurePolicy=fail,sideEffects=None,groups=delivery.ocm.software,resources=syncs,verbs=create;update,versions=v1alpha1,name=vsync.delivery.ocm.software,admissionReviewVersions=v1
+
+var _ webhook.Validator = &Sync{}
+
+// ValidateCreate validates the spec of a new Sync.
+func (in *Sync) ValidateCreate() error {
+	return in.validate()
+}
+
+// ValidateUpdate validates the spec of an updated Sync.
+func (in *Sync) ValidateUpdate(_ runtime.Object) error {
+	return in.validate()
+}
+
+// ValidateDelete allows every deletion.
+func (in *Sync) ValidateDelete() error {
+	return nil
+}
+
+func (in *Sync) validate() error {
+	var errs field.ErrorList
+
+	spec := field.NewPath("spec")
+
+	if !in.Spec.AutomaticPullRequestCreation && in.Spec.CommitTemplate.TargetBranch == "" {
+		errs = append(errs, field.Required(
+			spec.Child("commitTemplate", "targetBranch"),
+			"must be set if automatic pull request creation is not enabled",
+		))
+	}
+
+	errs = append(errs, in.validateSnapshots(spec)...)
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(GroupVersion.WithKind("Sync").GroupKind(), in.Name, errs)
+}
+
+// validateSnapshots requires either a single snapshotRef or sourceRef, or a list of snapshots with distinct paths.
+func (in *Sync) validateSnapshots(spec *field.Path) field.ErrorList {
+	if len(in.Spec.Snapshots) == 0 {
+		errs := validateSource(spec, in.Spec.SnapshotRef, in.Spec.SourceRef)
+
+		if err := validateSubPath(spec.Child("subPath"), in.Spec.SubPath); err != nil {
+			errs = append(errs, err)
+		}
+
+		return errs
+	}
+
+	var errs field.ErrorList
+
+	if in.Spec.SnapshotRef.Name != "" {
+		errs = append(errs, field.Forbidden(spec.Child("snapshotRef"), "must not be set together with snapshots"))
+	}
+
+	if in.Spec.SourceRef != nil {
+		errs = append(errs, field.Forbidden(spec.Child("sourceRef"), "must not be set together with snapshots"))
+	}
+
+	if in.Spec.SubPath != "" {
+		errs = append(errs, field.Forbidden(spec.Child("subPath"), "must not be set together with snapshots"))
+	}
+
+	subPaths := map[string]bool{}
+
+	for i, snapshot := range in.Spec.Snapshots {
+		fldPath := spec.Child("snapshots").Index(i)
+
+		errs = append(errs, validateSource(fldPath, snapshot.SnapshotRef, snapshot.SourceRef)...)
+
+		if err := validateSubPath(fldPath.Child("subPath"), snapshot.SubPath); err != nil {
+			errs = append(errs, err)
+		}
+
+		subPath := path.Clean(strings.ReplaceAll(snapshot.SubPath, `\`, "/"))
+		if subPaths[subPath] {
+			errs = append(errs, field.Duplicate(fldPath.Child("subPath"), snapshot.SubPath))
+		}
+
+		subPaths[subPath] = true
+	}
+
+	return errs
+}
+
+// validateSource requires exactly one of snapshotRef and sourceRef.
+func validateSource(fldPath *field.Path, snapshotRef corev1.LocalObjectReference, sourceRef *SourceReference) field.ErrorList {
+	var errs field.ErrorList
+
+	switch {
+	case sourceRef == nil && snapshotRef.Name == "":
+		errs = append(errs, field.Required(fldPath.Child("snapshotRef", "name"), "either snapshotRef or sourceRef must be set"))
+	case sourceRef != nil && snapshotRef.Name != "":
+		errs = append(errs, field.Forbidden(fldPath.Child("sourceRef"), "must not be set together with snapshotRef"))
+	case sourceRef != nil && sourceRef.Name == "":
+		errs = append(errs, field.Required(fldPath.Child("sourceRef", "name"), ""))
+	}
+
+	return errs
+}
+
+// validateSubPath rejects paths which could write outside the repository root.
+func validateSubPath(fldPath *field.Path, subPath string) *field.Error {
+	if path.IsAbs(subPath) || strings.HasPrefix(subPath, `\`) {
+		return field.Invalid(fldPath, subPath, "must be relative to the repository root")
+	}
+
+	for _, element := range strings.FieldsFunc(subPath, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if element == ".." {
+			return field.Invalid(fldPath, subPath, "must not contain '..'")
+		}
+	}
+
+	return nil
+}
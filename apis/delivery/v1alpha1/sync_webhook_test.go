@@ -0,0 +1,195 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func TestSyncDefault(t *testing.T) {
+	obj := &Sync{}
+	obj.Default()
+	assert.Equal(t, DefaultBaseBranch, obj.Spec.CommitTemplate.BaseBranch)
+
+	obj = &Sync{Spec: SyncSpec{CommitTemplate: CommitTemplate{BaseBranch: "develop"}}}
+	obj.Default()
+	assert.Equal(t, "develop", obj.Spec.CommitTemplate.BaseBranch)
+}
+
+func TestSyncValidate(t *testing.T) {
+	snapshotRef := corev1.LocalObjectReference{Name: "snapshot"}
+
+	testCases := []struct {
+		name    string
+		spec    SyncSpec
+		wantErr string
+	}{
+		{
+			name: "pull request without target branch",
+			spec: SyncSpec{
+				SnapshotRef:                  snapshotRef,
+				AutomaticPullRequestCreation: true,
+				SubPath:                      "apps/podinfo",
+			},
+		},
+		{
+			name: "target branch without pull request",
+			spec: SyncSpec{
+				SnapshotRef:    snapshotRef,
+				CommitTemplate: CommitTemplate{TargetBranch: "main"},
+				SubPath:        "./apps",
+			},
+		},
+		{
+			name:    "no pull request and no target branch",
+			spec:    SyncSpec{SnapshotRef: snapshotRef, SubPath: "apps"},
+			wantErr: "spec.commitTemplate.targetBranch: Required value",
+		},
+		{
+			name: "absolute sub path",
+			spec: SyncSpec{
+				SnapshotRef:                  snapshotRef,
+				AutomaticPullRequestCreation: true,
+				SubPath:                      "/etc",
+			},
+			wantErr: "must be relative to the repository root",
+		},
+		{
+			name: "sub path escaping the repository",
+			spec: SyncSpec{
+				SnapshotRef:                  snapshotRef,
+				AutomaticPullRequestCreation: true,
+				SubPath:                      "apps/../../other",
+			},
+			wantErr: "must not contain '..'",
+		},
+		{
+			name: "dots inside a path element",
+			spec: SyncSpec{
+				SnapshotRef:                  snapshotRef,
+				AutomaticPullRequestCreation: true,
+				SubPath:                      "apps/..hidden",
+			},
+		},
+		{
+			name: "no snapshot",
+			spec: SyncSpec{
+				AutomaticPullRequestCreation: true,
+				SubPath:                      "apps",
+			},
+			wantErr: "spec.snapshotRef.name: Required value",
+		},
+		{
+			name: "several snapshots",
+			spec: SyncSpec{
+				AutomaticPullRequestCreation: true,
+				Snapshots: []SnapshotPath{
+					{SnapshotRef: snapshotRef, SubPath: "apps/frontend"},
+					{SnapshotRef: corev1.LocalObjectReference{Name: "backend"}, SubPath: "apps/backend"},
+				},
+			},
+		},
+		{
+			name: "snapshots together with snapshotRef",
+			spec: SyncSpec{
+				AutomaticPullRequestCreation: true,
+				SnapshotRef:                  snapshotRef,
+				SubPath:                      "apps",
+				Snapshots: []SnapshotPath{
+					{SnapshotRef: snapshotRef, SubPath: "apps/frontend"},
+				},
+			},
+			wantErr: "spec.snapshotRef: Forbidden: must not be set together with snapshots",
+		},
+		{
+			name: "snapshots with the same path",
+			spec: SyncSpec{
+				AutomaticPullRequestCreation: true,
+				Snapshots: []SnapshotPath{
+					{SnapshotRef: snapshotRef, SubPath: "apps/frontend"},
+					{SnapshotRef: corev1.LocalObjectReference{Name: "backend"}, SubPath: "./apps/frontend/"},
+				},
+			},
+			wantErr: "spec.snapshots[1].subPath: Duplicate value",
+		},
+		{
+			name: "snapshot escaping the repository",
+			spec: SyncSpec{
+				AutomaticPullRequestCreation: true,
+				Snapshots: []SnapshotPath{
+					{SnapshotRef: snapshotRef, SubPath: "../other"},
+				},
+			},
+			wantErr: "spec.snapshots[0].subPath: Invalid value",
+		},
+		{
+			name: "snapshot without name",
+			spec: SyncSpec{
+				AutomaticPullRequestCreation: true,
+				Snapshots: []SnapshotPath{
+					{SubPath: "apps"},
+				},
+			},
+			wantErr: "spec.snapshots[0].snapshotRef.name: Required value",
+		},
+		{
+			name: "config map source",
+			spec: SyncSpec{
+				AutomaticPullRequestCreation: true,
+				SourceRef:                    &SourceReference{Kind: ConfigMapKind, Name: "values"},
+				SubPath:                      "apps",
+			},
+		},
+		{
+			name: "source together with snapshot",
+			spec: SyncSpec{
+				AutomaticPullRequestCreation: true,
+				SnapshotRef:                  snapshotRef,
+				SourceRef:                    &SourceReference{Kind: GitRepositoryKind, Name: "podinfo"},
+				SubPath:                      "apps",
+			},
+			wantErr: "spec.sourceRef: Forbidden: must not be set together with snapshotRef",
+		},
+		{
+			name: "snapshots mixed with sources",
+			spec: SyncSpec{
+				AutomaticPullRequestCreation: true,
+				Snapshots: []SnapshotPath{
+					{SnapshotRef: snapshotRef, SubPath: "apps/frontend"},
+					{SourceRef: &SourceReference{Kind: OCIRepositoryKind, Name: "backend"}, SubPath: "apps/backend"},
+				},
+			},
+		},
+		{
+			name: "snapshot entry without source",
+			spec: SyncSpec{
+				AutomaticPullRequestCreation: true,
+				Snapshots: []SnapshotPath{
+					{SourceRef: &SourceReference{Kind: BucketKind}, SubPath: "apps"},
+				},
+			},
+			wantErr: "spec.snapshots[0].sourceRef.name: Required value",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &Sync{Spec: tc.spec}
+
+			err := obj.ValidateCreate()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			require.Error(t, err)
+			assert.True(t, apierrors.IsInvalid(err))
+			assert.Contains(t, err.Error(), tc.wantErr)
+			assert.Equal(t, err, obj.ValidateUpdate(&Sync{}))
+		})
+	}
+}
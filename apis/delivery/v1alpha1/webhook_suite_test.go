@@ -0,0 +1,109 @@
+package v1alpha1
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+)
+
+// TestSyncWebhook runs the webhooks against a real API server. It requires the envtest binaries, see
+// `make test`.
+func TestSyncWebhook(t *testing.T) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("KUBEBUILDER_ASSETS is not set")
+	}
+
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "..", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			Paths: []string{filepath.Join("..", "..", "..", "config", "webhook")},
+		},
+	}
+
+	cfg, err := testEnv.Start()
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, testEnv.Stop())
+	}()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, AddToScheme(scheme))
+
+	webhookOptions := &testEnv.WebhookInstallOptions
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:             scheme,
+		Host:               webhookOptions.LocalServingHost,
+		Port:               webhookOptions.LocalServingPort,
+		CertDir:            webhookOptions.LocalServingCertDir,
+		LeaderElection:     false,
+		MetricsBindAddress: "0",
+	})
+	require.NoError(t, err)
+	require.NoError(t, (&Sync{}).SetupWebhookWithManager(mgr))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		_ = mgr.Start(ctx)
+	}()
+
+	address := net.JoinHostPort(webhookOptions.LocalServingHost, fmt.Sprintf("%d", webhookOptions.LocalServingPort))
+	require.Eventually(t, func() bool {
+		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: time.Second}, "tcp", address, &tls.Config{
+			InsecureSkipVerify: true, //nolint:gosec // the serving certificate is self-signed
+		})
+		if err != nil {
+			return false
+		}
+
+		return conn.Close() == nil
+	}, 10*time.Second, 100*time.Millisecond)
+
+	k8sClient, err := client.New(cfg, client.Options{Scheme: scheme})
+	require.NoError(t, err)
+
+	obj := &Sync{
+		ObjectMeta: metav1.ObjectMeta{Name: "sync", Namespace: "default"},
+		Spec: SyncSpec{
+			SnapshotRef:    corev1.LocalObjectReference{Name: "snapshot"},
+			RepositoryRef:  meta.NamespacedObjectReference{Name: "repository"},
+			Interval:       metav1.Duration{Duration: time.Minute},
+			CommitTemplate: CommitTemplate{Name: "name", Email: "email", Message: "message", TargetBranch: "main"},
+			SubPath:        "apps",
+		},
+	}
+	require.NoError(t, k8sClient.Create(ctx, obj))
+
+	created := &Sync{}
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "sync", Namespace: "default"}, created))
+	assert.Equal(t, DefaultBaseBranch, created.Spec.CommitTemplate.BaseBranch)
+
+	invalid := obj.DeepCopy()
+	invalid.ObjectMeta = metav1.ObjectMeta{Name: "invalid", Namespace: "default"}
+	invalid.Spec.SubPath = "../other"
+
+	err = k8sClient.Create(ctx, invalid)
+	assert.True(t, apierrors.IsInvalid(err), "expected an invalid error, got %v", err)
+}
@@ -9,6 +9,103 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommitStatus) DeepCopyInto(out *CommitStatus) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommitStatus.
+func (in *CommitStatus) DeepCopy() *CommitStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CommitStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CommitStatus) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommitStatusList) DeepCopyInto(out *CommitStatusList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CommitStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommitStatusList.
+func (in *CommitStatusList) DeepCopy() *CommitStatusList {
+	if in == nil {
+		return nil
+	}
+	out := new(CommitStatusList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CommitStatusList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommitStatusSpec) DeepCopyInto(out *CommitStatusSpec) {
+	*out = *in
+	out.SyncRef = in.SyncRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommitStatusSpec.
+func (in *CommitStatusSpec) DeepCopy() *CommitStatusSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CommitStatusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommitStatusStatus) DeepCopyInto(out *CommitStatusStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommitStatusStatus.
+func (in *CommitStatusStatus) DeepCopy() *CommitStatusStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CommitStatusStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommitTemplate) DeepCopyInto(out *CommitTemplate) {
 	*out = *in
@@ -39,12 +136,63 @@ func (in *PullRequestTemplate) DeepCopy() *PullRequestTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotDigest) DeepCopyInto(out *SnapshotDigest) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotDigest.
+func (in *SnapshotDigest) DeepCopy() *SnapshotDigest {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotDigest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotPath) DeepCopyInto(out *SnapshotPath) {
+	*out = *in
+	out.SnapshotRef = in.SnapshotRef
+	if in.SourceRef != nil {
+		in, out := &in.SourceRef, &out.SourceRef
+		*out = new(SourceReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotPath.
+func (in *SnapshotPath) DeepCopy() *SnapshotPath {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotPath)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceReference) DeepCopyInto(out *SourceReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceReference.
+func (in *SourceReference) DeepCopy() *SourceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Sync) DeepCopyInto(out *Sync) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -102,9 +250,21 @@ func (in *SyncList) DeepCopyObject() runtime.Object {
 func (in *SyncSpec) DeepCopyInto(out *SyncSpec) {
 	*out = *in
 	out.SnapshotRef = in.SnapshotRef
+	if in.SourceRef != nil {
+		in, out := &in.SourceRef, &out.SourceRef
+		*out = new(SourceReference)
+		**out = **in
+	}
 	out.RepositoryRef = in.RepositoryRef
 	out.Interval = in.Interval
 	out.CommitTemplate = in.CommitTemplate
+	if in.Snapshots != nil {
+		in, out := &in.Snapshots, &out.Snapshots
+		*out = make([]SnapshotPath, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	out.PullRequestTemplate = in.PullRequestTemplate
 }
 
@@ -121,6 +281,11 @@ func (in *SyncSpec) DeepCopy() *SyncSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyncStatus) DeepCopyInto(out *SyncStatus) {
 	*out = *in
+	if in.Snapshots != nil {
+		in, out := &in.Snapshots, &out.Snapshots
+		*out = make([]SnapshotDigest, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
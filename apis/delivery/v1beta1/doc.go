@@ -0,0 +1,4 @@
+// Package v1beta1 contains API Schema definitions for the delivery v1beta1 API group
+// +kubebuilder:object:generate=true
+// +groupName=delivery.ocm.software
+package v1beta1
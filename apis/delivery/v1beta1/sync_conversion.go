@@ -0,0 +1,195 @@
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/open-component-model/git-controller/apis/delivery/v1alpha1"
+	mpasv1beta1 "github.com/open-component-model/git-controller/apis/mpas/v1beta1"
+)
+
+// PullRequestTemplateAnnotation keeps the pull request template of a v1alpha1 Sync without automatic pull request
+// creation, which v1beta1 can't represent, so it survives a round trip.
+const PullRequestTemplateAnnotation = "delivery.ocm.software/v1alpha1-pull-request-template"
+
+var _ conversion.Convertible = &Sync{}
+
+// ConvertTo converts this Sync to the v1alpha1 hub version.
+func (in *Sync) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.Sync)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 Sync but got %T", dstRaw)
+	}
+
+	dst.ObjectMeta = in.ObjectMeta
+	dst.Spec = v1alpha1.SyncSpec{
+		SnapshotRef:   in.Spec.SnapshotRef,
+		SourceRef:     (*v1alpha1.SourceReference)(in.Spec.SourceRef),
+		RepositoryRef: in.Spec.RepositoryRef,
+		Interval:      in.Spec.Interval,
+		CommitTemplate: v1alpha1.CommitTemplate{
+			Name:         in.Spec.Commit.Name,
+			Email:        in.Spec.Commit.Email,
+			Message:      in.Spec.Commit.Message,
+			TargetBranch: in.Spec.TargetBranch,
+			BaseBranch:   in.Spec.BaseBranch,
+		},
+		SubPath:   in.Spec.SubPath,
+		Prune:     in.Spec.Prune,
+		Snapshots: convertSlice(in.Spec.Snapshots, snapshotPathToHub),
+	}
+
+	if in.Spec.PullRequest != nil {
+		dst.Spec.AutomaticPullRequestCreation = true
+		dst.Spec.PullRequestTemplate = v1alpha1.PullRequestTemplate(*in.Spec.PullRequest)
+	} else if template, ok := in.Annotations[PullRequestTemplateAnnotation]; ok {
+		if err := json.Unmarshal([]byte(template), &dst.Spec.PullRequestTemplate); err != nil {
+			return fmt.Errorf("failed to unmarshal pull request template annotation: %w", err)
+		}
+	}
+
+	dst.Annotations = withoutAnnotation(in.Annotations, PullRequestTemplateAnnotation)
+
+	dst.Status = v1alpha1.SyncStatus{
+		Digest:             in.Status.Digest,
+		Snapshots:          convertSlice(in.Status.Snapshots, snapshotDigestToHub),
+		ObservedGeneration: in.Status.ObservedGeneration,
+		Conditions:         in.Status.Conditions,
+		PullRequestID:      in.Status.PullRequestID,
+		PullRequestURL:     in.Status.PullRequestURL,
+		Commit:             in.Status.Commit,
+		CommitURL:          in.Status.CommitURL,
+		Branch:             in.Status.Branch,
+		ComponentName:      in.Status.ComponentName,
+		ComponentVersion:   in.Status.ComponentVersion,
+	}
+
+	return nil
+}
+
+// ConvertFrom converts the v1alpha1 hub version to this Sync. A pull request template is kept in the
+// PullRequestTemplateAnnotation if automatic pull request creation is disabled.
+func (in *Sync) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.Sync)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 Sync but got %T", srcRaw)
+	}
+
+	in.ObjectMeta = src.ObjectMeta
+	in.Spec = SyncSpec{
+		SnapshotRef:   src.Spec.SnapshotRef,
+		SourceRef:     (*SourceReference)(src.Spec.SourceRef),
+		RepositoryRef: src.Spec.RepositoryRef,
+		Interval:      src.Spec.Interval,
+		Commit: mpasv1beta1.CommitTemplate{
+			Name:    src.Spec.CommitTemplate.Name,
+			Email:   src.Spec.CommitTemplate.Email,
+			Message: src.Spec.CommitTemplate.Message,
+		},
+		BaseBranch:   src.Spec.CommitTemplate.BaseBranch,
+		TargetBranch: src.Spec.CommitTemplate.TargetBranch,
+		SubPath:      src.Spec.SubPath,
+		Snapshots:    convertSlice(src.Spec.Snapshots, snapshotPathFromHub),
+		Prune:        src.Spec.Prune,
+	}
+
+	if src.Spec.AutomaticPullRequestCreation {
+		pullRequest := PullRequest(src.Spec.PullRequestTemplate)
+		in.Spec.PullRequest = &pullRequest
+	} else if src.Spec.PullRequestTemplate != (v1alpha1.PullRequestTemplate{}) {
+		template, err := json.Marshal(src.Spec.PullRequestTemplate)
+		if err != nil {
+			return fmt.Errorf("failed to marshal pull request template: %w", err)
+		}
+
+		in.Annotations = withAnnotation(src.Annotations, PullRequestTemplateAnnotation, string(template))
+	}
+
+	in.Status = SyncStatus{
+		Digest:             src.Status.Digest,
+		Snapshots:          convertSlice(src.Status.Snapshots, snapshotDigestFromHub),
+		ObservedGeneration: src.Status.ObservedGeneration,
+		Conditions:         src.Status.Conditions,
+		PullRequestID:      src.Status.PullRequestID,
+		PullRequestURL:     src.Status.PullRequestURL,
+		Commit:             src.Status.Commit,
+		CommitURL:          src.Status.CommitURL,
+		Branch:             src.Status.Branch,
+		ComponentName:      src.Status.ComponentName,
+		ComponentVersion:   src.Status.ComponentVersion,
+	}
+
+	return nil
+}
+
+func snapshotPathToHub(in SnapshotPath) v1alpha1.SnapshotPath {
+	return v1alpha1.SnapshotPath{
+		SnapshotRef: in.SnapshotRef,
+		SourceRef:   (*v1alpha1.SourceReference)(in.SourceRef),
+		SubPath:     in.SubPath,
+	}
+}
+
+func snapshotPathFromHub(in v1alpha1.SnapshotPath) SnapshotPath {
+	return SnapshotPath{
+		SnapshotRef: in.SnapshotRef,
+		SourceRef:   (*SourceReference)(in.SourceRef),
+		SubPath:     in.SubPath,
+	}
+}
+
+func snapshotDigestToHub(in SnapshotDigest) v1alpha1.SnapshotDigest {
+	return v1alpha1.SnapshotDigest(in)
+}
+
+func snapshotDigestFromHub(in v1alpha1.SnapshotDigest) SnapshotDigest {
+	return SnapshotDigest(in)
+}
+
+// withAnnotation returns a copy of the annotations with key set to value.
+func withAnnotation(annotations map[string]string, key, value string) map[string]string {
+	out := make(map[string]string, len(annotations)+1)
+	for k, v := range annotations {
+		out[k] = v
+	}
+
+	out[key] = value
+
+	return out
+}
+
+// withoutAnnotation returns the annotations without key. The annotations are copied only if they contain key.
+func withoutAnnotation(annotations map[string]string, key string) map[string]string {
+	if _, ok := annotations[key]; !ok {
+		return annotations
+	}
+
+	out := make(map[string]string, len(annotations))
+	for k, v := range annotations {
+		if k != key {
+			out[k] = v
+		}
+	}
+
+	if len(out) == 0 {
+		return nil
+	}
+
+	return out
+}
+
+// convertSlice converts every element of a slice and keeps nil slices nil.
+func convertSlice[S, D any](in []S, convert func(S) D) []D {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]D, 0, len(in))
+	for _, item := range in {
+		out = append(out, convert(item))
+	}
+
+	return out
+}
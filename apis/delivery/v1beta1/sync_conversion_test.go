@@ -0,0 +1,162 @@
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	"github.com/open-component-model/git-controller/apis/delivery/v1alpha1"
+	mpasv1beta1 "github.com/open-component-model/git-controller/apis/mpas/v1beta1"
+)
+
+func TestSyncConversion(t *testing.T) {
+	obj := &Sync{
+		ObjectMeta: metav1.ObjectMeta{Name: "sync", Namespace: "default"},
+		Spec: SyncSpec{
+			SnapshotRef:   corev1.LocalObjectReference{Name: "snapshot"},
+			RepositoryRef: meta.NamespacedObjectReference{Name: "repository", Namespace: "mpas-system"},
+			Interval:      metav1.Duration{Duration: time.Minute},
+			Commit: mpasv1beta1.CommitTemplate{
+				Name:    "Bot",
+				Email:   "bot@example.com",
+				Message: "Update podinfo",
+			},
+			BaseBranch:   "main",
+			TargetBranch: "update-podinfo",
+			SubPath:      "apps/podinfo",
+			Prune:        true,
+			PullRequest: &PullRequest{
+				Title:       "Update podinfo",
+				Description: "New version",
+				Base:        "main",
+			},
+		},
+		Status: SyncStatus{
+			Digest:             "sha256:digest",
+			ObservedGeneration: 2,
+			PullRequestID:      3,
+		},
+	}
+
+	hub := &v1alpha1.Sync{}
+	require.NoError(t, obj.ConvertTo(hub))
+
+	assert.Equal(t, v1alpha1.CommitTemplate{
+		Name:         "Bot",
+		Email:        "bot@example.com",
+		Message:      "Update podinfo",
+		TargetBranch: "update-podinfo",
+		BaseBranch:   "main",
+	}, hub.Spec.CommitTemplate)
+	assert.True(t, hub.Spec.AutomaticPullRequestCreation)
+	assert.Equal(t, "Update podinfo", hub.Spec.PullRequestTemplate.Title)
+	assert.Equal(t, 3, hub.Status.PullRequestID)
+
+	converted := &Sync{}
+	require.NoError(t, converted.ConvertFrom(hub))
+	assert.Equal(t, obj, converted)
+}
+
+func TestSyncConversionWithSnapshots(t *testing.T) {
+	obj := &Sync{
+		ObjectMeta: metav1.ObjectMeta{Name: "sync", Namespace: "default"},
+		Spec: SyncSpec{
+			RepositoryRef: meta.NamespacedObjectReference{Name: "repository"},
+			Commit:        mpasv1beta1.CommitTemplate{Name: "Bot", Email: "bot@example.com", Message: "Update product"},
+			BaseBranch:    "main",
+			TargetBranch:  "update-product",
+			Snapshots: []SnapshotPath{
+				{SnapshotRef: corev1.LocalObjectReference{Name: "frontend"}, SubPath: "apps/frontend"},
+				{SnapshotRef: corev1.LocalObjectReference{Name: "backend"}, SubPath: "apps/backend"},
+				{SourceRef: &SourceReference{Kind: "ConfigMap", Name: "values"}, SubPath: "apps/values"},
+			},
+		},
+		Status: SyncStatus{
+			Digest: "sha256:combined",
+			Snapshots: []SnapshotDigest{
+				{Name: "frontend", SubPath: "apps/frontend", Digest: "sha256:frontend"},
+				{Name: "backend", SubPath: "apps/backend", Digest: "sha256:backend"},
+				{Kind: "ConfigMap", Name: "values", SubPath: "apps/values", Digest: "sha256:values"},
+			},
+			Commit:           "2f0e0c1d",
+			Branch:           "update-product",
+			ComponentName:    "ocm.software/product",
+			ComponentVersion: "v1.0.0",
+		},
+	}
+
+	hub := &v1alpha1.Sync{}
+	require.NoError(t, obj.ConvertTo(hub))
+
+	assert.Equal(t, []v1alpha1.SnapshotPath{
+		{SnapshotRef: corev1.LocalObjectReference{Name: "frontend"}, SubPath: "apps/frontend"},
+		{SnapshotRef: corev1.LocalObjectReference{Name: "backend"}, SubPath: "apps/backend"},
+		{SourceRef: &v1alpha1.SourceReference{Kind: "ConfigMap", Name: "values"}, SubPath: "apps/values"},
+	}, hub.Spec.Snapshots)
+	assert.Len(t, hub.Status.Snapshots, 3)
+	assert.Equal(t, "2f0e0c1d", hub.Status.Commit)
+
+	converted := &Sync{}
+	require.NoError(t, converted.ConvertFrom(hub))
+	assert.Equal(t, obj, converted)
+}
+
+func TestSyncConversionWithoutPullRequest(t *testing.T) {
+	hub := &v1alpha1.Sync{
+		ObjectMeta: metav1.ObjectMeta{Name: "sync", Namespace: "default"},
+		Spec: v1alpha1.SyncSpec{
+			CommitTemplate: v1alpha1.CommitTemplate{TargetBranch: "main"},
+			SubPath:        "apps",
+		},
+	}
+
+	obj := &Sync{}
+	require.NoError(t, obj.ConvertFrom(hub))
+	assert.Nil(t, obj.Spec.PullRequest)
+	assert.Equal(t, "main", obj.Spec.TargetBranch)
+
+	converted := &v1alpha1.Sync{}
+	require.NoError(t, obj.ConvertTo(converted))
+	assert.Equal(t, hub, converted)
+}
+
+func TestSyncConversionKeepsUnusedPullRequestTemplate(t *testing.T) {
+	hub := &v1alpha1.Sync{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "sync",
+			Namespace:   "default",
+			Annotations: map[string]string{"team": "platform"},
+		},
+		Spec: v1alpha1.SyncSpec{
+			CommitTemplate:      v1alpha1.CommitTemplate{TargetBranch: "main"},
+			PullRequestTemplate: v1alpha1.PullRequestTemplate{Title: "Update podinfo", Base: "release"},
+		},
+	}
+
+	obj := &Sync{}
+	require.NoError(t, obj.ConvertFrom(hub))
+	assert.Nil(t, obj.Spec.PullRequest)
+	assert.Equal(t, `{"title":"Update podinfo","base":"release"}`, obj.Annotations[PullRequestTemplateAnnotation])
+	assert.Equal(t, map[string]string{"team": "platform"}, hub.Annotations, "the hub object must not be modified")
+
+	converted := &v1alpha1.Sync{}
+	require.NoError(t, obj.ConvertTo(converted))
+	assert.Equal(t, hub, converted)
+}
+
+func TestSyncIsConvertible(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, AddToScheme(scheme))
+
+	ok, err := conversion.IsConvertible(scheme, &v1alpha1.Sync{})
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
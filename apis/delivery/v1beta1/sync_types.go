@@ -0,0 +1,159 @@
+package v1beta1
+
+import (
+	"github.com/fluxcd/pkg/apis/meta"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mpasv1beta1 "github.com/open-component-model/git-controller/apis/mpas/v1beta1"
+)
+
+// PullRequest defines the pull request opened for the pushed changes.
+type PullRequest struct {
+	//+optional
+	Title string `json:"title,omitempty"`
+	//+optional
+	Description string `json:"description,omitempty"`
+	// Base is the branch the pull request is merged into. Defaults to `main`.
+	//+optional
+	Base string `json:"base,omitempty"`
+}
+
+// SourceReference points to an object in the Sync's namespace providing the content to push. Flux sources are
+// pushed with the content of their latest artifact, ConfigMaps with one file per key.
+type SourceReference struct {
+	//+kubebuilder:validation:Enum=Snapshot;OCIRepository;Bucket;GitRepository;ConfigMap
+	//+required
+	Kind string `json:"kind"`
+	//+required
+	Name string `json:"name"`
+}
+
+// SnapshotPath places the content of a snapshot or of another source at a path of the repository. Either
+// snapshotRef or sourceRef must be set.
+type SnapshotPath struct {
+	//+optional
+	SnapshotRef v1.LocalObjectReference `json:"snapshotRef,omitempty"`
+	//+optional
+	SourceRef *SourceReference `json:"sourceRef,omitempty"`
+	//+required
+	SubPath string `json:"subPath"`
+}
+
+// SyncSpec defines the desired state of Sync.
+type SyncSpec struct {
+	// SnapshotRef is the snapshot to push into subPath. Either snapshotRef, sourceRef or snapshots must be set.
+	//+optional
+	SnapshotRef v1.LocalObjectReference `json:"snapshotRef,omitempty"`
+	// SourceRef pushes the content of a Flux source or a ConfigMap instead of a snapshot.
+	//+optional
+	SourceRef *SourceReference `json:"sourceRef,omitempty"`
+	//+required
+	RepositoryRef meta.NamespacedObjectReference `json:"repositoryRef"`
+	//+required
+	Interval metav1.Duration `json:"interval"`
+	// Commit defines the author and message of the pushed commit.
+	//+required
+	Commit mpasv1beta1.CommitTemplate `json:"commit"`
+	// BaseBranch is the branch the changes are committed on top of.
+	//+optional
+	//+kubebuilder:default:=main
+	BaseBranch string `json:"baseBranch,omitempty"`
+	// TargetBranch is the branch the changes are pushed to. It is required unless a pull request is opened, in
+	// which case a branch is generated if it isn't set.
+	//+optional
+	TargetBranch string `json:"targetBranch,omitempty"`
+	//+optional
+	SubPath string `json:"subPath,omitempty"`
+	// Snapshots extracts several snapshots or sources into one commit, each into its own path. Can't be combined
+	// with snapshotRef, sourceRef and subPath.
+	//+optional
+	Snapshots []SnapshotPath `json:"snapshots,omitempty"`
+	//+optional
+	Prune bool `json:"prune,omitempty"`
+	// PullRequest opens a pull request from the target branch. If not set, the changes are only pushed.
+	//+optional
+	PullRequest *PullRequest `json:"pullRequest,omitempty"`
+}
+
+// SnapshotDigest is the digest of a snapshot or source pushed by a Sync.
+type SnapshotDigest struct {
+	// Kind is the kind of the source. It is empty for snapshots.
+	// +optional
+	Kind    string `json:"kind,omitempty"`
+	Name    string `json:"name"`
+	SubPath string `json:"subPath"`
+	Digest  string `json:"digest"`
+}
+
+// SyncStatus defines the observed state of Sync.
+type SyncStatus struct {
+	// Digest is the digest of the pushed snapshot. If several snapshots are pushed, it is calculated from the
+	// digests of all of them.
+	Digest string `json:"digest,omitempty"`
+
+	// Snapshots lists the digest of every pushed snapshot.
+	// +optional
+	Snapshots []SnapshotDigest `json:"snapshots,omitempty"`
+
+	// ObservedGeneration is the last reconciled generation.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// +optional
+	PullRequestID int `json:"pullRequestID,omitempty"`
+
+	// PullRequestURL links to the pull request opened for the last pushed commit.
+	// +optional
+	PullRequestURL string `json:"pullRequestURL,omitempty"`
+
+	// Commit is the SHA of the last pushed commit.
+	// +optional
+	Commit string `json:"commit,omitempty"`
+
+	// CommitURL links to the last pushed commit on the web page of the repository.
+	// +optional
+	CommitURL string `json:"commitURL,omitempty"`
+
+	// Branch is the branch the last commit was pushed to.
+	// +optional
+	Branch string `json:"branch,omitempty"`
+
+	// ComponentName is the name of the component the pushed snapshot belongs to.
+	// +optional
+	ComponentName string `json:"componentName,omitempty"`
+
+	// ComponentVersion is the version of the component the pushed snapshot belongs to.
+	// +optional
+	ComponentVersion string `json:"componentVersion,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:unservedversion
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description=""
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].message",description=""
+
+// Sync is the Schema for the syncs API.
+type Sync struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SyncSpec   `json:"spec,omitempty"`
+	Status SyncStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SyncList contains a list of Sync.
+type SyncList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Sync `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Sync{}, &SyncList{})
+}
@@ -1,9 +1,44 @@
 package v1alpha1
 
+const (
+	// DriftedCondition is true when the settings of the remote repository differ from the spec.
+	DriftedCondition = "Drifted"
+
+	// BranchProtectionUnsupportedCondition is true when the provider can't apply some branch protection settings.
+	BranchProtectionUnsupportedCondition = "BranchProtectionUnsupported"
+)
+
 const (
 	// RepositoryCreateFailedReason is used when we fail to create a Repository.
 	RepositoryCreateFailedReason = "RepositoryCreateFailed"
 
 	// UpdatingBranchProtectionFailedReason is used when we fail to update a branch protection rules.
 	UpdatingBranchProtectionFailedReason = "UpdatingBranchProtectionFailed"
+
+	// RepositoryDeleteFailedReason is used when we fail to delete or archive the remote repository.
+	RepositoryDeleteFailedReason = "RepositoryDeleteFailed"
+
+	// DeletionNotConfirmedReason is used when the delete policy is set but the deletion wasn't confirmed.
+	DeletionNotConfirmedReason = "DeletionNotConfirmed"
+
+	// DriftDetectedReason is used when the settings of the remote repository differ from the spec.
+	DriftDetectedReason = "DriftDetected"
+
+	// DriftCheckFailedReason is used when we fail to read or correct the settings of the remote repository.
+	DriftCheckFailedReason = "DriftCheckFailed"
+
+	// UnsupportedSettingsReason is used when the provider can't apply some of the requested settings.
+	UnsupportedSettingsReason = "UnsupportedSettings"
+
+	// PermissionsUpdateFailedReason is used when we fail to reconcile the access to the repository.
+	PermissionsUpdateFailedReason = "PermissionsUpdateFailed"
+
+	// WebhooksUpdateFailedReason is used when we fail to reconcile the webhooks of the repository.
+	WebhooksUpdateFailedReason = "WebhooksUpdateFailed"
+
+	// DeployKeyFailedReason is used when we fail to generate, register or store the deploy key.
+	DeployKeyFailedReason = "DeployKeyFailed"
+
+	// RepositoryMetadataUpdateFailedReason is used when we fail to apply the description, topics or features.
+	RepositoryMetadataUpdateFailedReason = "RepositoryMetadataUpdateFailed"
 )
@@ -0,0 +1,4 @@
+package v1alpha1
+
+// Hub marks v1alpha1 as the version other Repository versions are converted to and from.
+func (*Repository) Hub() {}
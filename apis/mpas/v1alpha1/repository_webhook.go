@@ -0,0 +1,95 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// SetupWebhookWithManager registers the validating webhook of Repository. Providers is the list of provider
+// names the controller has enabled.
+func (in *Repository) SetupWebhookWithManager(mgr ctrl.Manager, providers []string) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(in).
+		WithValidator(&repositoryValidator{providers: providers}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-mpas-ocm-software-v1alpha1-repository,mutating=false,failurePolicy=fail,sideEffects=None,groups=mpas.ocm.software,resources=repositories,verbs=create;update,versions=v1alpha1,name=vrepository.mpas.ocm.software,admissionReviewVersions=v1
+
+// repositoryValidator validates Repositories against the providers enabled in the controller.
+//
+// +kubebuilder:object:generate=false
+type repositoryValidator struct {
+	providers []string
+}
+
+var _ webhook.CustomValidator = &repositoryValidator{}
+
+// ValidateCreate validates the spec of a new Repository.
+func (v *repositoryValidator) ValidateCreate(_ context.Context, obj runtime.Object) error {
+	return v.validate(obj)
+}
+
+// ValidateUpdate validates the spec of an updated Repository.
+func (v *repositoryValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) error {
+	return v.validate(newObj)
+}
+
+// ValidateDelete allows every deletion.
+func (v *repositoryValidator) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func (v *repositoryValidator) validate(obj runtime.Object) error {
+	repository, ok := obj.(*Repository)
+	if !ok {
+		return fmt.Errorf("expected a Repository but got %T", obj)
+	}
+
+	var errs field.ErrorList
+
+	spec := field.NewPath("spec")
+
+	if !slices.Contains(v.providers, repository.Spec.Provider) {
+		errs = append(errs, field.NotSupported(spec.Child("provider"), repository.Spec.Provider, v.providers))
+	}
+
+	if repository.Spec.Domain != "" {
+		if err := validateDomain(spec.Child("domain"), repository.Spec.Domain); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(GroupVersion.WithKind("Repository").GroupKind(), repository.Name, errs)
+}
+
+// validateDomain accepts a host with an optional port, like `gitea.example.com:3000`.
+func validateDomain(fldPath *field.Path, domain string) *field.Error {
+	if strings.Contains(domain, "://") {
+		return field.Invalid(fldPath, domain, "must not contain a scheme")
+	}
+
+	u, err := url.Parse("https://" + domain)
+	if err != nil {
+		return field.Invalid(fldPath, domain, err.Error())
+	}
+
+	if u.Hostname() == "" || u.User != nil || u.Path != "" || u.RawQuery != "" || u.Fragment != "" {
+		return field.Invalid(fldPath, domain, "must be a host name with an optional port")
+	}
+
+	return nil
+}
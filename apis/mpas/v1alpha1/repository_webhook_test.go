@@ -0,0 +1,67 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func TestRepositoryValidate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		spec    RepositorySpec
+		wantErr string
+	}{
+		{
+			name: "registered provider",
+			spec: RepositorySpec{Provider: "github"},
+		},
+		{
+			name: "domain with port",
+			spec: RepositorySpec{Provider: "gitea", Domain: "gitea.example.com:3000"},
+		},
+		{
+			name:    "unknown provider",
+			spec:    RepositorySpec{Provider: "bitbucket"},
+			wantErr: `spec.provider: Unsupported value: "bitbucket": supported values: "gitea", "github"`,
+		},
+		{
+			name:    "domain with scheme",
+			spec:    RepositorySpec{Provider: "github", Domain: "https://github.example.com"},
+			wantErr: "must not contain a scheme",
+		},
+		{
+			name:    "domain with path",
+			spec:    RepositorySpec{Provider: "github", Domain: "github.example.com/org"},
+			wantErr: "must be a host name with an optional port",
+		},
+		{
+			name:    "domain with invalid port",
+			spec:    RepositorySpec{Provider: "github", Domain: "github.example.com:port"},
+			wantErr: "spec.domain: Invalid value",
+		},
+	}
+
+	validator := &repositoryValidator{providers: []string{"gitea", "github"}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &Repository{Spec: tc.spec}
+
+			err := validator.ValidateCreate(context.Background(), obj)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			require.Error(t, err)
+			assert.True(t, apierrors.IsInvalid(err))
+			assert.Contains(t, err.Error(), tc.wantErr)
+			assert.Equal(t, err, validator.ValidateUpdate(context.Background(), &Repository{}, obj))
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package v1beta1 contains API Schema definitions for the mpas v1beta1 API group
+// +kubebuilder:object:generate=true
+// +groupName=mpas.ocm.software
+package v1beta1
@@ -0,0 +1,256 @@
+package v1beta1
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+)
+
+var _ conversion.Convertible = &Repository{}
+
+// ConvertTo converts this Repository to the v1alpha1 hub version.
+func (in *Repository) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.Repository)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 Repository but got %T", dstRaw)
+	}
+
+	spec := in.Spec
+
+	dst.ObjectMeta = in.ObjectMeta
+	dst.Spec = v1alpha1.RepositorySpec{
+		Provider:                 spec.Provider,
+		Owner:                    spec.Owner,
+		Credentials:              v1alpha1.Credentials(spec.Credentials),
+		DefaultBranch:            spec.DefaultBranch,
+		Interval:                 spec.Interval,
+		Visibility:               spec.Visibility,
+		IsOrganization:           spec.OwnerKind == OwnerKindOrganization,
+		Domain:                   spec.Domain,
+		Insecure:                 spec.Insecure,
+		Maintainers:              spec.Maintainers,
+		ExistingRepositoryPolicy: v1alpha1.ExistingRepositoryPolicy(spec.ExistingRepositoryPolicy),
+		DeletionPolicy:           v1alpha1.DeletionPolicy(spec.DeletionPolicy),
+		DriftPolicy:              v1alpha1.DriftPolicy(spec.DriftPolicy),
+		BranchProtection:         convertSlice(spec.BranchProtection, branchProtectionRuleToHub),
+		Source:                   (*v1alpha1.RepositorySource)(spec.Source),
+		Webhooks:                 convertSlice(spec.Webhooks, webhookToHub),
+		Description:              spec.Description,
+		Topics:                   spec.Topics,
+		Homepage:                 spec.Homepage,
+		Features:                 (*v1alpha1.RepositoryFeatures)(spec.Features),
+		MergeMethods:             (*v1alpha1.MergeMethods)(spec.MergeMethods),
+		DeployKey:                (*v1alpha1.DeployKey)(spec.DeployKey),
+		AllowedNamespaces:        (*v1alpha1.AllowedNamespaces)(spec.AllowedNamespaces),
+	}
+
+	if spec.CommitTemplate != nil {
+		dst.Spec.CommitTemplate = &v1alpha1.CommitTemplate{
+			Email:   spec.CommitTemplate.Email,
+			Message: spec.CommitTemplate.Message,
+			Name:    spec.CommitTemplate.Name,
+		}
+	}
+
+	if spec.InitialContent != nil {
+		dst.Spec.InitialContent = &v1alpha1.InitialContent{
+			ConfigMapRef:       (*v1alpha1.ConfigMapContent)(spec.InitialContent.ConfigMapRef),
+			SnapshotRef:        spec.InitialContent.SnapshotRef,
+			TemplateRepository: (*v1alpha1.TemplateRepository)(spec.InitialContent.TemplateRepository),
+		}
+	}
+
+	if spec.Permissions != nil {
+		dst.Spec.Permissions = &v1alpha1.Permissions{
+			Users: convertSlice(spec.Permissions.Users, permissionToHub),
+			Teams: convertSlice(spec.Permissions.Teams, permissionToHub),
+			Prune: spec.Permissions.Prune,
+		}
+	}
+
+	status := in.Status
+
+	dst.Status = v1alpha1.RepositoryStatus{
+		ObservedGeneration: status.ObservedGeneration,
+		Conditions:         status.Conditions,
+		ID:                 status.ID,
+		HTTPSURL:           status.HTTPSURL,
+		SSHURL:             status.SSHURL,
+		WebURL:             status.WebURL,
+		DefaultBranch:      status.DefaultBranch,
+		Visibility:         status.Visibility,
+		Origin:             v1alpha1.RepositoryOrigin(status.Origin),
+		Webhooks:           status.Webhooks,
+		DeployKey:          (*v1alpha1.DeployKeyStatus)(status.DeployKey),
+		StaleDeployKeys:    convertSlice(status.StaleDeployKeys, deployKeyStatusToHub),
+	}
+
+	return nil
+}
+
+// ConvertFrom converts the v1alpha1 hub version to this Repository.
+func (in *Repository) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.Repository)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 Repository but got %T", srcRaw)
+	}
+
+	spec := src.Spec
+
+	ownerKind := OwnerKindUser
+	if spec.IsOrganization {
+		ownerKind = OwnerKindOrganization
+	}
+
+	in.ObjectMeta = src.ObjectMeta
+	in.Spec = RepositorySpec{
+		Provider:                 spec.Provider,
+		Owner:                    spec.Owner,
+		Credentials:              Credentials(spec.Credentials),
+		DefaultBranch:            spec.DefaultBranch,
+		Interval:                 spec.Interval,
+		Visibility:               spec.Visibility,
+		OwnerKind:                ownerKind,
+		Domain:                   spec.Domain,
+		Insecure:                 spec.Insecure,
+		Maintainers:              spec.Maintainers,
+		ExistingRepositoryPolicy: ExistingRepositoryPolicy(spec.ExistingRepositoryPolicy),
+		DeletionPolicy:           DeletionPolicy(spec.DeletionPolicy),
+		DriftPolicy:              DriftPolicy(spec.DriftPolicy),
+		BranchProtection:         convertSlice(spec.BranchProtection, branchProtectionRuleFromHub),
+		Source:                   (*RepositorySource)(spec.Source),
+		Webhooks:                 convertSlice(spec.Webhooks, webhookFromHub),
+		Description:              spec.Description,
+		Topics:                   spec.Topics,
+		Homepage:                 spec.Homepage,
+		Features:                 (*RepositoryFeatures)(spec.Features),
+		MergeMethods:             (*MergeMethods)(spec.MergeMethods),
+		DeployKey:                (*DeployKey)(spec.DeployKey),
+		AllowedNamespaces:        (*AllowedNamespaces)(spec.AllowedNamespaces),
+	}
+
+	if spec.CommitTemplate != nil {
+		in.Spec.CommitTemplate = &CommitTemplate{
+			Name:    spec.CommitTemplate.Name,
+			Email:   spec.CommitTemplate.Email,
+			Message: spec.CommitTemplate.Message,
+		}
+	}
+
+	if spec.InitialContent != nil {
+		in.Spec.InitialContent = &InitialContent{
+			ConfigMapRef:       (*ConfigMapContent)(spec.InitialContent.ConfigMapRef),
+			SnapshotRef:        spec.InitialContent.SnapshotRef,
+			TemplateRepository: (*TemplateRepository)(spec.InitialContent.TemplateRepository),
+		}
+	}
+
+	if spec.Permissions != nil {
+		in.Spec.Permissions = &Permissions{
+			Users: convertSlice(spec.Permissions.Users, permissionFromHub),
+			Teams: convertSlice(spec.Permissions.Teams, permissionFromHub),
+			Prune: spec.Permissions.Prune,
+		}
+	}
+
+	status := src.Status
+
+	in.Status = RepositoryStatus{
+		ObservedGeneration: status.ObservedGeneration,
+		Conditions:         status.Conditions,
+		ID:                 status.ID,
+		HTTPSURL:           status.HTTPSURL,
+		SSHURL:             status.SSHURL,
+		WebURL:             status.WebURL,
+		DefaultBranch:      status.DefaultBranch,
+		Visibility:         status.Visibility,
+		Origin:             RepositoryOrigin(status.Origin),
+		Webhooks:           status.Webhooks,
+		DeployKey:          (*DeployKeyStatus)(status.DeployKey),
+		StaleDeployKeys:    convertSlice(status.StaleDeployKeys, deployKeyStatusFromHub),
+	}
+
+	return nil
+}
+
+func branchProtectionRuleToHub(in BranchProtectionRule) v1alpha1.BranchProtectionRule {
+	return v1alpha1.BranchProtectionRule{
+		Pattern:                      in.Pattern,
+		RequiredStatusChecks:         in.RequiredStatusChecks,
+		StrictStatusChecks:           in.StrictStatusChecks,
+		RequiredApprovingReviewCount: in.RequiredApprovingReviewCount,
+		DismissStaleReviews:          in.DismissStaleReviews,
+		EnforceAdmins:                in.EnforceAdmins,
+		RestrictPushes:               (*v1alpha1.PushRestrictions)(in.RestrictPushes),
+		RequireLinearHistory:         in.RequireLinearHistory,
+		RequireSignedCommits:         in.RequireSignedCommits,
+	}
+}
+
+func branchProtectionRuleFromHub(in v1alpha1.BranchProtectionRule) BranchProtectionRule {
+	return BranchProtectionRule{
+		Pattern:                      in.Pattern,
+		RequiredStatusChecks:         in.RequiredStatusChecks,
+		StrictStatusChecks:           in.StrictStatusChecks,
+		RequiredApprovingReviewCount: in.RequiredApprovingReviewCount,
+		DismissStaleReviews:          in.DismissStaleReviews,
+		EnforceAdmins:                in.EnforceAdmins,
+		RestrictPushes:               (*PushRestrictions)(in.RestrictPushes),
+		RequireLinearHistory:         in.RequireLinearHistory,
+		RequireSignedCommits:         in.RequireSignedCommits,
+	}
+}
+
+func webhookToHub(in Webhook) v1alpha1.Webhook {
+	return v1alpha1.Webhook{
+		URL: in.URL,
+		Events: convertSlice(in.Events, func(event WebhookEvent) v1alpha1.WebhookEvent {
+			return v1alpha1.WebhookEvent(event)
+		}),
+		ContentType: in.ContentType,
+		SecretRef:   in.SecretRef,
+	}
+}
+
+func webhookFromHub(in v1alpha1.Webhook) Webhook {
+	return Webhook{
+		URL: in.URL,
+		Events: convertSlice(in.Events, func(event v1alpha1.WebhookEvent) WebhookEvent {
+			return WebhookEvent(event)
+		}),
+		ContentType: in.ContentType,
+		SecretRef:   in.SecretRef,
+	}
+}
+
+func permissionToHub(in Permission) v1alpha1.Permission {
+	return v1alpha1.Permission{Name: in.Name, Role: v1alpha1.Role(in.Role)}
+}
+
+func permissionFromHub(in v1alpha1.Permission) Permission {
+	return Permission{Name: in.Name, Role: Role(in.Role)}
+}
+
+func deployKeyStatusToHub(in DeployKeyStatus) v1alpha1.DeployKeyStatus {
+	return v1alpha1.DeployKeyStatus(in)
+}
+
+func deployKeyStatusFromHub(in v1alpha1.DeployKeyStatus) DeployKeyStatus {
+	return DeployKeyStatus(in)
+}
+
+// convertSlice converts every element of a slice and keeps nil slices nil.
+func convertSlice[S, D any](in []S, convert func(S) D) []D {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]D, 0, len(in))
+	for _, item := range in {
+		out = append(out, convert(item))
+	}
+
+	return out
+}
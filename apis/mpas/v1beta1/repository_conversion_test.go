@@ -0,0 +1,114 @@
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	"github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+)
+
+func TestRepositoryConversion(t *testing.T) {
+	enabled := true
+
+	hub := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repository", Namespace: "default"},
+		Spec: v1alpha1.RepositorySpec{
+			Provider:                 "github",
+			Owner:                    "open-component-model",
+			Credentials:              v1alpha1.Credentials{SecretRef: corev1.LocalObjectReference{Name: "credentials"}},
+			DefaultBranch:            "main",
+			Interval:                 metav1.Duration{Duration: time.Minute},
+			Visibility:               "private",
+			IsOrganization:           true,
+			Domain:                   "github.example.com",
+			Maintainers:              []string{"alice"},
+			ExistingRepositoryPolicy: v1alpha1.ExistingRepositoryPolicyAdopt,
+			CommitTemplate: &v1alpha1.CommitTemplate{
+				Email:   "bot@example.com",
+				Message: "Initial commit",
+				Name:    "Bot",
+			},
+			DeletionPolicy: v1alpha1.DeletionPolicyArchive,
+			DriftPolicy:    v1alpha1.DriftPolicyCorrect,
+			BranchProtection: []v1alpha1.BranchProtectionRule{{
+				Pattern:              "main",
+				RequiredStatusChecks: []string{"mpas/validation-check"},
+				RestrictPushes:       &v1alpha1.PushRestrictions{Users: []string{"alice"}},
+			}},
+			InitialContent: &v1alpha1.InitialContent{
+				ConfigMapRef: &v1alpha1.ConfigMapContent{Name: "content"},
+			},
+			Permissions: &v1alpha1.Permissions{
+				Users: []v1alpha1.Permission{{Name: "alice", Role: v1alpha1.RoleAdmin}},
+				Teams: []v1alpha1.Permission{{Name: "developers", Role: v1alpha1.RoleWrite}},
+				Prune: true,
+			},
+			Webhooks: []v1alpha1.Webhook{{
+				URL:    "https://hooks.example.com",
+				Events: []v1alpha1.WebhookEvent{v1alpha1.WebhookEventPush, v1alpha1.WebhookEventTag},
+			}},
+			Topics:            []string{"mpas"},
+			Features:          &v1alpha1.RepositoryFeatures{Wiki: &enabled},
+			MergeMethods:      &v1alpha1.MergeMethods{Squash: &enabled},
+			DeployKey:         &v1alpha1.DeployKey{SecretName: "deploy-key"},
+			AllowedNamespaces: &v1alpha1.AllowedNamespaces{Names: []string{"tenant"}},
+		},
+		Status: v1alpha1.RepositoryStatus{
+			ObservedGeneration: 1,
+			ID:                 "42",
+			Origin:             v1alpha1.RepositoryOriginCreated,
+			Webhooks:           []string{"https://hooks.example.com"},
+			DeployKey:          &v1alpha1.DeployKeyStatus{ID: "1", SecretName: "deploy-key"},
+			StaleDeployKeys:    []v1alpha1.DeployKeyStatus{{ID: "0"}},
+		},
+	}
+
+	obj := &Repository{}
+	require.NoError(t, obj.ConvertFrom(hub))
+
+	assert.Equal(t, OwnerKindOrganization, obj.Spec.OwnerKind)
+	assert.Equal(t, &CommitTemplate{Name: "Bot", Email: "bot@example.com", Message: "Initial commit"}, obj.Spec.CommitTemplate)
+	assert.Equal(t, RoleAdmin, obj.Spec.Permissions.Users[0].Role)
+	assert.Equal(t, []WebhookEvent{WebhookEventPush, WebhookEventTag}, obj.Spec.Webhooks[0].Events)
+
+	converted := &v1alpha1.Repository{}
+	require.NoError(t, obj.ConvertTo(converted))
+	assert.Equal(t, hub, converted)
+}
+
+func TestRepositoryConversionUserOwner(t *testing.T) {
+	obj := &Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repository", Namespace: "default"},
+		Spec: RepositorySpec{
+			Provider:  "gitea",
+			Owner:     "alice",
+			OwnerKind: OwnerKindUser,
+		},
+	}
+
+	hub := &v1alpha1.Repository{}
+	require.NoError(t, obj.ConvertTo(hub))
+	assert.False(t, hub.Spec.IsOrganization)
+	assert.Nil(t, hub.Spec.CommitTemplate)
+
+	converted := &Repository{}
+	require.NoError(t, converted.ConvertFrom(hub))
+	assert.Equal(t, obj, converted)
+}
+
+func TestRepositoryIsConvertible(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, AddToScheme(scheme))
+
+	ok, err := conversion.IsConvertible(scheme, &v1alpha1.Repository{})
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
@@ -0,0 +1,480 @@
+package v1beta1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Credentials contains ways of authenticating the creation of a repository.
+type Credentials struct {
+	SecretRef v1.LocalObjectReference `json:"secretRef"`
+}
+
+// OwnerKind is the kind of account owning a repository.
+type OwnerKind string
+
+var (
+	// OwnerKindOrganization is used when the owner is an organization or a GitLab group.
+	OwnerKindOrganization OwnerKind = "Organization"
+	// OwnerKindUser is used when the owner is a user.
+	OwnerKindUser OwnerKind = "User"
+)
+
+// ExistingRepositoryPolicy defines what to do in case a requested repository already exists.
+type ExistingRepositoryPolicy string
+
+var (
+	// ExistingRepositoryPolicyAdopt will use the repository if it exists.
+	ExistingRepositoryPolicyAdopt ExistingRepositoryPolicy = "adopt"
+	// ExistingRepositoryPolicyFail will fail if the requested repository already exists.
+	ExistingRepositoryPolicyFail ExistingRepositoryPolicy = "fail"
+)
+
+// DeletionPolicy defines what happens to the remote repository once the Repository object is deleted.
+type DeletionPolicy string
+
+var (
+	// DeletionPolicyOrphan leaves the remote repository untouched.
+	DeletionPolicyOrphan DeletionPolicy = "orphan"
+	// DeletionPolicyArchive archives the remote repository.
+	DeletionPolicyArchive DeletionPolicy = "archive"
+	// DeletionPolicyDelete deletes the remote repository. Requires the `mpas.ocm.software/confirm-delete` annotation.
+	DeletionPolicyDelete DeletionPolicy = "delete"
+)
+
+// DriftPolicy defines what happens when the settings of the remote repository differ from the spec.
+type DriftPolicy string
+
+var (
+	// DriftPolicyReport only reports drift in the Drifted condition and through an event.
+	DriftPolicyReport DriftPolicy = "report"
+	// DriftPolicyCorrect resets the drifted settings of the remote repository to the values in the spec.
+	DriftPolicyCorrect DriftPolicy = "correct"
+)
+
+// RepositoryOrigin records how the remote repository came to be managed by the controller.
+type RepositoryOrigin string
+
+var (
+	// RepositoryOriginCreated is used when the controller created the remote repository.
+	RepositoryOriginCreated RepositoryOrigin = "created"
+	// RepositoryOriginAdopted is used when the remote repository already existed.
+	RepositoryOriginAdopted RepositoryOrigin = "adopted"
+)
+
+//+kubebuilder:validation:XValidation:rule="!has(self.source) || !has(self.initialContent)",message="source and initialContent are mutually exclusive"
+
+// RepositorySpec defines the desired state of Repository.
+type RepositorySpec struct {
+	//+required
+	Provider string `json:"provider"`
+	//+required
+	Owner string `json:"owner"`
+	//+required
+	Credentials Credentials `json:"credentials"`
+
+	//+optional
+	//+kubebuilder:default:=main
+	DefaultBranch string `json:"defaultBranch,omitempty"`
+	//+optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+	//+optional
+	//+kubebuilder:validation:Enum=public;private;internal
+	//+kubebuilder:default:=private
+	Visibility string `json:"visibility,omitempty"`
+	// OwnerKind defines whether Owner is an organization or a user. For GitLab, organizations are groups.
+	//+required
+	//+kubebuilder:validation:Enum=Organization;User
+	OwnerKind OwnerKind `json:"ownerKind"`
+	// Domain specifies an optional domain address to be used instead of the defaults like github.com.
+	// Must NOT contain the scheme.
+	//+optional
+	//+kubebuilder:validation:Pattern="^\\w+(\\.|:[0-9]).*$"
+	Domain string `json:"domain,omitempty"`
+	// Insecure should be defined if `domain` is not HTTPS.
+	//+optional
+	Insecure bool `json:"insecure,omitempty"`
+	//+optional
+	Maintainers []string `json:"maintainers,omitempty"`
+	//+optional
+	//+kubebuilder:default:=adopt
+	//+kubebuilder:validation:Enum=adopt;fail
+	ExistingRepositoryPolicy ExistingRepositoryPolicy `json:"existingRepositoryPolicy,omitempty"`
+	//+optional
+	CommitTemplate *CommitTemplate `json:"commitTemplate,omitempty"`
+	// DeletionPolicy defines what happens to the remote repository when this object is deleted.
+	// `delete` only takes effect if the object is annotated with `mpas.ocm.software/confirm-delete: "true"`.
+	//+optional
+	//+kubebuilder:default:=orphan
+	//+kubebuilder:validation:Enum=orphan;archive;delete
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// DriftPolicy defines what happens if the settings of the remote repository have been changed outside
+	// the controller. Drift is checked on every Interval.
+	//+optional
+	//+kubebuilder:default:=report
+	//+kubebuilder:validation:Enum=report;correct
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+	// BranchProtection defines protection rules for the branches of the repository. If empty, the default branch
+	// requires the `mpas/validation-check` status check. Settings a provider can't apply are reported in the
+	// BranchProtectionUnsupported condition.
+	//+optional
+	BranchProtection []BranchProtectionRule `json:"branchProtection,omitempty"`
+	// InitialContent defines the files of the initial commit when the controller creates the repository.
+	// If not set, the MPAS project layout is created.
+	//+optional
+	InitialContent *InitialContent `json:"initialContent,omitempty"`
+	// Source imports the history of an existing repository when the controller creates the repository.
+	// Cannot be combined with InitialContent.
+	//+optional
+	Source *RepositorySource `json:"source,omitempty"`
+	// Permissions grants users and teams access to the repository.
+	//+optional
+	Permissions *Permissions `json:"permissions,omitempty"`
+	// Webhooks are created on the remote repository and kept in sync with the spec.
+	//+optional
+	Webhooks []Webhook `json:"webhooks,omitempty"`
+	// Description of the remote repository.
+	//+optional
+	Description string `json:"description,omitempty"`
+	// Topics label the repository so it can be found in catalogs.
+	//+optional
+	Topics []string `json:"topics,omitempty"`
+	// Homepage is a URL describing the repository's project. GitLab has no homepage.
+	//+optional
+	Homepage string `json:"homepage,omitempty"`
+	// Features toggles optional features of the repository. Features which aren't set are left as they are.
+	//+optional
+	Features *RepositoryFeatures `json:"features,omitempty"`
+	// MergeMethods defines how pull requests can be merged. Methods which aren't set are left as they are.
+	//+optional
+	MergeMethods *MergeMethods `json:"mergeMethods,omitempty"`
+	// DeployKey generates an SSH key pair and registers its public key as a deploy key of the repository.
+	//+optional
+	DeployKey *DeployKey `json:"deployKey,omitempty"`
+	// AllowedNamespaces restricts which namespaces other than the Repository's own may refer to it from a Sync.
+	// If not set, every namespace is allowed unless the controller runs with `--no-cross-namespace-refs`.
+	//+optional
+	AllowedNamespaces *AllowedNamespaces `json:"allowedNamespaces,omitempty"`
+}
+
+// AllowedNamespaces selects namespaces by name or by labels. A namespace is allowed if it matches either.
+type AllowedNamespaces struct {
+	// Names of the allowed namespaces.
+	//+optional
+	Names []string `json:"names,omitempty"`
+	// Selector matches the labels of the allowed namespaces.
+	//+optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// RepositorySource defines an existing repository whose branches and tags are imported into the new repository.
+type RepositorySource struct {
+	// URL of the repository to import.
+	//+required
+	URL string `json:"url"`
+	// SecretRef refers to a Secret with the same keys as the Repository's credentials. Public repositories
+	// don't need one. Providers which import through their migration API only support username and password.
+	//+optional
+	SecretRef *v1.LocalObjectReference `json:"secretRef,omitempty"`
+	// Mirror keeps the new repository as a pull mirror of the source. Only supported by GitLab and Gitea.
+	//+optional
+	Mirror bool `json:"mirror,omitempty"`
+	// MirrorInterval defines how often Gitea updates the mirror. GitLab uses its own schedule.
+	//+optional
+	MirrorInterval *metav1.Duration `json:"mirrorInterval,omitempty"`
+}
+
+// RepositoryFeatures toggles optional features of a repository.
+type RepositoryFeatures struct {
+	//+optional
+	Issues *bool `json:"issues,omitempty"`
+	//+optional
+	Wiki *bool `json:"wiki,omitempty"`
+	// Projects are not available on GitLab.
+	//+optional
+	Projects *bool `json:"projects,omitempty"`
+}
+
+// MergeMethods defines the methods allowed to merge pull requests.
+type MergeMethods struct {
+	// Merge allows merge commits. Not configurable on GitLab.
+	//+optional
+	Merge *bool `json:"merge,omitempty"`
+	//+optional
+	Squash *bool `json:"squash,omitempty"`
+	// Rebase allows rebasing pull requests onto the base branch. Not configurable on GitLab.
+	//+optional
+	Rebase *bool `json:"rebase,omitempty"`
+}
+
+// DeployKey defines a deploy key managed by the controller.
+type DeployKey struct {
+	// SecretName is the name of the Secret the private key and known_hosts are written to. It uses the
+	// `identity`, `identity.pub` and `known_hosts` keys Flux expects.
+	//+required
+	SecretName string `json:"secretName"`
+	// ReadOnly deploy keys can only clone the repository. Syncs need a key with write access.
+	//+optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+	// RotationInterval defines how often a new key pair is generated. Keys are not rotated if not set.
+	//+optional
+	RotationInterval *metav1.Duration `json:"rotationInterval,omitempty"`
+}
+
+//+kubebuilder:validation:Enum=push;pull_request;tag;release;issues
+
+// WebhookEvent is an event of the repository which triggers a webhook.
+type WebhookEvent string
+
+var (
+	WebhookEventPush        WebhookEvent = "push"
+	WebhookEventPullRequest WebhookEvent = "pull_request"
+	WebhookEventTag         WebhookEvent = "tag"
+	WebhookEventRelease     WebhookEvent = "release"
+	WebhookEventIssues      WebhookEvent = "issues"
+)
+
+// Webhook defines a hook which the provider calls on events of the repository.
+type Webhook struct {
+	// URL receives the event payloads. It identifies the webhook on the remote repository.
+	//+required
+	URL string `json:"url"`
+	// Events trigger the webhook. Pull requests are merge requests on GitLab.
+	//+optional
+	//+kubebuilder:default:={push}
+	Events []WebhookEvent `json:"events,omitempty"`
+	// ContentType of the payload. GitLab always sends json.
+	//+optional
+	//+kubebuilder:default:=json
+	//+kubebuilder:validation:Enum=json;form
+	ContentType string `json:"contentType,omitempty"`
+	// SecretRef refers to a Secret with a `token` key. It is used to sign the payload, GitLab sends it as is
+	// in the `X-Gitlab-Token` header instead.
+	//+optional
+	SecretRef *v1.LocalObjectReference `json:"secretRef,omitempty"`
+}
+
+// Role is the level of access granted on a repository. Providers with fewer levels use the closest one.
+type Role string
+
+var (
+	RoleRead     Role = "read"
+	RoleTriage   Role = "triage"
+	RoleWrite    Role = "write"
+	RoleMaintain Role = "maintain"
+	RoleAdmin    Role = "admin"
+)
+
+// Permissions defines who has access to the repository.
+type Permissions struct {
+	//+optional
+	Users []Permission `json:"users,omitempty"`
+	// Teams are the teams of the organization owning the repository. For GitLab, these are group paths.
+	//+optional
+	Teams []Permission `json:"teams,omitempty"`
+	// Prune removes the access of users and teams which are not listed.
+	//+optional
+	Prune bool `json:"prune,omitempty"`
+}
+
+// Permission grants a role to a user or a team.
+type Permission struct {
+	//+required
+	Name string `json:"name"`
+	//+required
+	//+kubebuilder:validation:Enum=read;triage;write;maintain;admin
+	Role Role `json:"role"`
+}
+
+//+kubebuilder:validation:MinProperties=1
+//+kubebuilder:validation:MaxProperties=1
+
+// InitialContent defines where the files of a new repository's initial commit come from. Exactly one source
+// must be set. Text files are rendered as Go templates with the Repository object as data, e.g. `{{ .Spec.Owner }}`.
+type InitialContent struct {
+	// ConfigMapRef uses the data of a ConfigMap in the Repository's namespace.
+	//+optional
+	ConfigMapRef *ConfigMapContent `json:"configMapRef,omitempty"`
+	// SnapshotRef uses the content of an OCM Snapshot in the Repository's namespace.
+	//+optional
+	SnapshotRef *v1.LocalObjectReference `json:"snapshotRef,omitempty"`
+	// TemplateRepository uses the files of another git repository.
+	//+optional
+	TemplateRepository *TemplateRepository `json:"templateRepository,omitempty"`
+}
+
+// ConfigMapContent selects the keys of a ConfigMap which become files.
+type ConfigMapContent struct {
+	//+required
+	Name string `json:"name"`
+	// Items maps keys of the ConfigMap to file paths. If empty, every key becomes a file in the root
+	// of the repository.
+	//+optional
+	Items []v1.KeyToPath `json:"items,omitempty"`
+}
+
+// TemplateRepository defines a git repository whose files are copied into the new repository.
+type TemplateRepository struct {
+	// URL of the template repository.
+	//+required
+	URL string `json:"url"`
+	//+optional
+	//+kubebuilder:default:=main
+	Branch string `json:"branch,omitempty"`
+	// SecretRef refers to a Secret with the same keys as the Repository's credentials. Public repositories
+	// don't need one.
+	//+optional
+	SecretRef *v1.LocalObjectReference `json:"secretRef,omitempty"`
+}
+
+// BranchProtectionRule defines the protection of all branches matching a pattern.
+type BranchProtectionRule struct {
+	// Pattern is the name of a branch or a pattern like `release/*` matching multiple branches.
+	// Not every provider supports patterns.
+	//+required
+	Pattern string `json:"pattern"`
+	// RequiredStatusChecks lists the status checks which must pass before a pull request can be merged.
+	//+optional
+	RequiredStatusChecks []string `json:"requiredStatusChecks,omitempty"`
+	// StrictStatusChecks requires branches to be up-to-date with the base branch before merging.
+	//+optional
+	StrictStatusChecks bool `json:"strictStatusChecks,omitempty"`
+	// RequiredApprovingReviewCount is the number of approving reviews a pull request needs before merging.
+	//+optional
+	//+kubebuilder:validation:Minimum=0
+	RequiredApprovingReviewCount int `json:"requiredApprovingReviewCount,omitempty"`
+	// DismissStaleReviews dismisses approving reviews once new commits are pushed.
+	//+optional
+	DismissStaleReviews bool `json:"dismissStaleReviews,omitempty"`
+	// EnforceAdmins applies the rule to administrators as well.
+	//+optional
+	EnforceAdmins bool `json:"enforceAdmins,omitempty"`
+	// RestrictPushes limits who can push to matching branches. If not set, everyone with write access can push.
+	//+optional
+	RestrictPushes *PushRestrictions `json:"restrictPushes,omitempty"`
+	// RequireLinearHistory prevents merge commits from being pushed to matching branches.
+	//+optional
+	RequireLinearHistory bool `json:"requireLinearHistory,omitempty"`
+	// RequireSignedCommits requires all commits on matching branches to be signed.
+	//+optional
+	RequireSignedCommits bool `json:"requireSignedCommits,omitempty"`
+}
+
+// PushRestrictions lists the users and teams allowed to push to a protected branch.
+type PushRestrictions struct {
+	//+optional
+	Users []string `json:"users,omitempty"`
+	//+optional
+	Teams []string `json:"teams,omitempty"`
+}
+
+// CommitTemplate defines the author and message of the commits made by the controller. It is shared by
+// Repositories and Syncs.
+type CommitTemplate struct {
+	//+required
+	Name string `json:"name"`
+	//+required
+	Email string `json:"email"`
+	//+required
+	Message string `json:"message"`
+}
+
+// RepositoryStatus defines the observed state of Repository.
+type RepositoryStatus struct {
+	// ObservedGeneration is the last reconciled generation.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ID is the provider's identifier of the remote repository.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// HTTPSURL is the URL to clone the repository through HTTPS.
+	// +optional
+	HTTPSURL string `json:"httpsURL,omitempty"`
+
+	// SSHURL is the URL to clone the repository through SSH.
+	// +optional
+	SSHURL string `json:"sshURL,omitempty"`
+
+	// WebURL is the URL of the repository's web page.
+	// +optional
+	WebURL string `json:"webURL,omitempty"`
+
+	// DefaultBranch is the default branch of the remote repository.
+	// +optional
+	DefaultBranch string `json:"defaultBranch,omitempty"`
+
+	// Visibility is the visibility of the remote repository.
+	// +optional
+	Visibility string `json:"visibility,omitempty"`
+
+	// Origin is `created` if the controller created the remote repository and `adopted` if it already existed.
+	// +optional
+	Origin RepositoryOrigin `json:"origin,omitempty"`
+
+	// Webhooks lists the URLs of the webhooks managed on the remote repository. Webhooks removed from the spec
+	// are deleted from the remote repository.
+	// +optional
+	Webhooks []string `json:"webhooks,omitempty"`
+
+	// DeployKey describes the currently registered deploy key.
+	// +optional
+	DeployKey *DeployKeyStatus `json:"deployKey,omitempty"`
+
+	// StaleDeployKeys lists replaced deploy keys which couldn't be removed yet. Their removal is retried on every
+	// reconcile.
+	// +optional
+	StaleDeployKeys []DeployKeyStatus `json:"staleDeployKeys,omitempty"`
+}
+
+// DeployKeyStatus describes a registered deploy key.
+type DeployKeyStatus struct {
+	// ID is the provider's identifier of the deploy key.
+	ID string `json:"id"`
+	// Fingerprint is the SHA256 fingerprint of the public key.
+	Fingerprint string `json:"fingerprint"`
+	// SecretName is the Secret holding the private key.
+	SecretName string `json:"secretName"`
+	// ReadOnly is true if the key can only clone the repository.
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+	// GeneratedAt is the time the key pair was generated.
+	GeneratedAt metav1.Time `json:"generatedAt"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:unservedversion
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description=""
+//+kubebuilder:printcolumn:name="URL",type="string",JSONPath=".status.webURL",description=""
+//+kubebuilder:printcolumn:name="Branch",type="string",JSONPath=".status.defaultBranch",description=""
+//+kubebuilder:printcolumn:name="Visibility",type="string",JSONPath=".status.visibility",description="",priority=1
+//+kubebuilder:printcolumn:name="Origin",type="string",JSONPath=".status.origin",description="",priority=1
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].message",description=""
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// Repository is the Schema for the repositories API.
+type Repository struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RepositorySpec   `json:"spec,omitempty"`
+	Status RepositoryStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RepositoryList contains a list of Repository.
+type RepositoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Repository `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Repository{}, &RepositoryList{})
+}
@@ -0,0 +1,554 @@
+//go:build !ignore_autogenerated
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AllowedNamespaces) DeepCopyInto(out *AllowedNamespaces) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllowedNamespaces.
+func (in *AllowedNamespaces) DeepCopy() *AllowedNamespaces {
+	if in == nil {
+		return nil
+	}
+	out := new(AllowedNamespaces)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BranchProtectionRule) DeepCopyInto(out *BranchProtectionRule) {
+	*out = *in
+	if in.RequiredStatusChecks != nil {
+		in, out := &in.RequiredStatusChecks, &out.RequiredStatusChecks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RestrictPushes != nil {
+		in, out := &in.RestrictPushes, &out.RestrictPushes
+		*out = new(PushRestrictions)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BranchProtectionRule.
+func (in *BranchProtectionRule) DeepCopy() *BranchProtectionRule {
+	if in == nil {
+		return nil
+	}
+	out := new(BranchProtectionRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommitTemplate) DeepCopyInto(out *CommitTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommitTemplate.
+func (in *CommitTemplate) DeepCopy() *CommitTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(CommitTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapContent) DeepCopyInto(out *ConfigMapContent) {
+	*out = *in
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]corev1.KeyToPath, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapContent.
+func (in *ConfigMapContent) DeepCopy() *ConfigMapContent {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapContent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Credentials) DeepCopyInto(out *Credentials) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Credentials.
+func (in *Credentials) DeepCopy() *Credentials {
+	if in == nil {
+		return nil
+	}
+	out := new(Credentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeployKey) DeepCopyInto(out *DeployKey) {
+	*out = *in
+	if in.RotationInterval != nil {
+		in, out := &in.RotationInterval, &out.RotationInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployKey.
+func (in *DeployKey) DeepCopy() *DeployKey {
+	if in == nil {
+		return nil
+	}
+	out := new(DeployKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeployKeyStatus) DeepCopyInto(out *DeployKeyStatus) {
+	*out = *in
+	in.GeneratedAt.DeepCopyInto(&out.GeneratedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployKeyStatus.
+func (in *DeployKeyStatus) DeepCopy() *DeployKeyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeployKeyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InitialContent) DeepCopyInto(out *InitialContent) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(ConfigMapContent)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SnapshotRef != nil {
+		in, out := &in.SnapshotRef, &out.SnapshotRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.TemplateRepository != nil {
+		in, out := &in.TemplateRepository, &out.TemplateRepository
+		*out = new(TemplateRepository)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InitialContent.
+func (in *InitialContent) DeepCopy() *InitialContent {
+	if in == nil {
+		return nil
+	}
+	out := new(InitialContent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MergeMethods) DeepCopyInto(out *MergeMethods) {
+	*out = *in
+	if in.Merge != nil {
+		in, out := &in.Merge, &out.Merge
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Squash != nil {
+		in, out := &in.Squash, &out.Squash
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Rebase != nil {
+		in, out := &in.Rebase, &out.Rebase
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MergeMethods.
+func (in *MergeMethods) DeepCopy() *MergeMethods {
+	if in == nil {
+		return nil
+	}
+	out := new(MergeMethods)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Permission) DeepCopyInto(out *Permission) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Permission.
+func (in *Permission) DeepCopy() *Permission {
+	if in == nil {
+		return nil
+	}
+	out := new(Permission)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Permissions) DeepCopyInto(out *Permissions) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]Permission, len(*in))
+		copy(*out, *in)
+	}
+	if in.Teams != nil {
+		in, out := &in.Teams, &out.Teams
+		*out = make([]Permission, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Permissions.
+func (in *Permissions) DeepCopy() *Permissions {
+	if in == nil {
+		return nil
+	}
+	out := new(Permissions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushRestrictions) DeepCopyInto(out *PushRestrictions) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Teams != nil {
+		in, out := &in.Teams, &out.Teams
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushRestrictions.
+func (in *PushRestrictions) DeepCopy() *PushRestrictions {
+	if in == nil {
+		return nil
+	}
+	out := new(PushRestrictions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Repository) DeepCopyInto(out *Repository) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Repository.
+func (in *Repository) DeepCopy() *Repository {
+	if in == nil {
+		return nil
+	}
+	out := new(Repository)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Repository) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryFeatures) DeepCopyInto(out *RepositoryFeatures) {
+	*out = *in
+	if in.Issues != nil {
+		in, out := &in.Issues, &out.Issues
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Wiki != nil {
+		in, out := &in.Wiki, &out.Wiki
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Projects != nil {
+		in, out := &in.Projects, &out.Projects
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryFeatures.
+func (in *RepositoryFeatures) DeepCopy() *RepositoryFeatures {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryFeatures)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryList) DeepCopyInto(out *RepositoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Repository, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryList.
+func (in *RepositoryList) DeepCopy() *RepositoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RepositoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositorySource) DeepCopyInto(out *RepositorySource) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.MirrorInterval != nil {
+		in, out := &in.MirrorInterval, &out.MirrorInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositorySource.
+func (in *RepositorySource) DeepCopy() *RepositorySource {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositorySource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositorySpec) DeepCopyInto(out *RepositorySpec) {
+	*out = *in
+	out.Credentials = in.Credentials
+	out.Interval = in.Interval
+	if in.Maintainers != nil {
+		in, out := &in.Maintainers, &out.Maintainers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CommitTemplate != nil {
+		in, out := &in.CommitTemplate, &out.CommitTemplate
+		*out = new(CommitTemplate)
+		**out = **in
+	}
+	if in.BranchProtection != nil {
+		in, out := &in.BranchProtection, &out.BranchProtection
+		*out = make([]BranchProtectionRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitialContent != nil {
+		in, out := &in.InitialContent, &out.InitialContent
+		*out = new(InitialContent)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(RepositorySource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = new(Permissions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Webhooks != nil {
+		in, out := &in.Webhooks, &out.Webhooks
+		*out = make([]Webhook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Topics != nil {
+		in, out := &in.Topics, &out.Topics
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = new(RepositoryFeatures)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MergeMethods != nil {
+		in, out := &in.MergeMethods, &out.MergeMethods
+		*out = new(MergeMethods)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeployKey != nil {
+		in, out := &in.DeployKey, &out.DeployKey
+		*out = new(DeployKey)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = new(AllowedNamespaces)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositorySpec.
+func (in *RepositorySpec) DeepCopy() *RepositorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryStatus) DeepCopyInto(out *RepositoryStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Webhooks != nil {
+		in, out := &in.Webhooks, &out.Webhooks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeployKey != nil {
+		in, out := &in.DeployKey, &out.DeployKey
+		*out = new(DeployKeyStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StaleDeployKeys != nil {
+		in, out := &in.StaleDeployKeys, &out.StaleDeployKeys
+		*out = make([]DeployKeyStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryStatus.
+func (in *RepositoryStatus) DeepCopy() *RepositoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateRepository) DeepCopyInto(out *TemplateRepository) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateRepository.
+func (in *TemplateRepository) DeepCopy() *TemplateRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Webhook) DeepCopyInto(out *Webhook) {
+	*out = *in
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]WebhookEvent, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Webhook.
+func (in *Webhook) DeepCopy() *Webhook {
+	if in == nil {
+		return nil
+	}
+	out := new(Webhook)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,218 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/patch"
+	rreconcile "github.com/fluxcd/pkg/runtime/reconcile"
+	"go.opentelemetry.io/otel/attribute"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kuberecorder "k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/open-component-model/ocm-controller/pkg/status"
+
+	"github.com/open-component-model/git-controller/apis/delivery/v1alpha1"
+	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+	"github.com/open-component-model/git-controller/pkg/providers"
+	"github.com/open-component-model/git-controller/pkg/tracing"
+)
+
+const syncRefIndexKey = ".spec.syncRef.name"
+
+// CommitStatusReconciler reconciles a CommitStatus object.
+type CommitStatusReconciler struct {
+	client.Client
+	kuberecorder.EventRecorder
+	Scheme *runtime.Scheme
+
+	Provider providers.Provider
+	// MaxConcurrentReconciles is the number of CommitStatuses reconciled in parallel.
+	MaxConcurrentReconciles int
+}
+
+//+kubebuilder:rbac:groups=delivery.ocm.software,resources=commitstatuses,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=delivery.ocm.software,resources=commitstatuses/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=delivery.ocm.software,resources=commitstatuses/finalizers,verbs=update
+
+// Reconcile reports the state of a CommitStatus to the provider of the pull request created by the referenced Sync.
+func (r *CommitStatusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "CommitStatus.Reconcile",
+		attribute.String("commitstatus.namespace", req.Namespace),
+		attribute.String("commitstatus.name", req.Name),
+	)
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	obj := &v1alpha1.CommitStatus{}
+	if err = r.Get(ctx, req.NamespacedName, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, fmt.Errorf("failed to get commit status object: %w", err)
+	}
+
+	patchHelper := patch.NewSerialPatcher(obj, r.Client)
+
+	// Always attempt to patch the object and status after each reconciliation.
+	defer func() {
+		// Patching has not been set up, or the controller errored earlier.
+		if patchHelper == nil {
+			return
+		}
+
+		if derr := status.UpdateStatus(ctx, patchHelper, obj, r.EventRecorder, obj.GetRequeueAfter()); derr != nil {
+			err = errors.Join(err, derr)
+		}
+	}()
+
+	// Starts the progression by setting ReconcilingCondition.
+	// This will be checked in defer.
+	// Should only be deleted on a success.
+	rreconcile.ProgressiveStatus(false, obj, meta.ProgressingReason, "reconciliation in progress for resource: %s", obj.Name)
+
+	if err := r.reconcile(ctx, obj); err != nil {
+		// Retrying before the provider's quota resets would only fail again.
+		if retryAfter, ok := providers.RetryAfter(err); ok {
+			return ctrl.Result{RequeueAfter: retryAfter}, nil
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CommitStatusReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.CommitStatus{}, syncRefIndexKey, func(obj client.Object) []string {
+		commitStatus, ok := obj.(*v1alpha1.CommitStatus)
+		if !ok {
+			return nil
+		}
+
+		return []string{commitStatus.Spec.SyncRef.Name}
+	}); err != nil {
+		return fmt.Errorf("failed setting index fields: %w", err)
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.CommitStatus{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&source.Kind{Type: &v1alpha1.Sync{}},
+			handler.EnqueueRequestsFromMapFunc(r.findCommitStatuses),
+		).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
+		Complete(r)
+}
+
+// findCommitStatuses enqueues all CommitStatus objects which refer to a changed Sync. This makes sure that
+// a state set before the pull request existed is reported once it does.
+func (r *CommitStatusReconciler) findCommitStatuses(obj client.Object) []reconcile.Request {
+	list := &v1alpha1.CommitStatusList{}
+	if err := r.List(context.Background(), list, client.InNamespace(obj.GetNamespace()), client.MatchingFields{
+		syncRefIndexKey: obj.GetName(),
+	}); err != nil {
+		log.FromContext(context.Background()).Error(err, "failed to list commit statuses referencing sync",
+			"sync", client.ObjectKeyFromObject(obj))
+
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(list.Items))
+	for _, item := range list.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      item.GetName(),
+				Namespace: item.GetNamespace(),
+			},
+		})
+	}
+
+	return requests
+}
+
+func (r *CommitStatusReconciler) reconcile(ctx context.Context, obj *v1alpha1.CommitStatus) error {
+	sync := &v1alpha1.Sync{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Namespace: obj.Namespace,
+		Name:      obj.Spec.SyncRef.Name,
+	}, sync); err != nil {
+		err = fmt.Errorf("failed to find sync: %w", err)
+		status.MarkNotReady(r.EventRecorder, obj, v1alpha1.SyncGetFailedReason, err.Error())
+
+		return err
+	}
+
+	if sync.Status.PullRequestID == 0 {
+		// The Sync watch will trigger a new reconcile once the pull request has been created.
+		status.MarkNotReady(r.EventRecorder, obj, v1alpha1.PullRequestMissingReason, "sync has not created a pull request yet")
+
+		return nil
+	}
+
+	// A new commit pushed to the pull request needs the state as well.
+	if obj.Generation == obj.Status.ObservedGeneration &&
+		obj.Status.PullRequestID == sync.Status.PullRequestID &&
+		obj.Status.ReportedCommit == sync.Status.Commit &&
+		obj.Status.ReportedState == obj.Spec.State {
+		status.MarkReady(r.EventRecorder, obj, "Commit status already reported")
+
+		return nil
+	}
+
+	namespace := sync.Spec.RepositoryRef.Namespace
+	if namespace == "" {
+		namespace = sync.Namespace
+	}
+
+	repository := &mpasv1alpha1.Repository{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Namespace: namespace,
+		Name:      sync.Spec.RepositoryRef.Name,
+	}, repository); err != nil {
+		err = fmt.Errorf("failed to find repository: %w", err)
+		status.MarkNotReady(r.EventRecorder, obj, v1alpha1.RepositoryGetFailedReason, err.Error())
+
+		return err
+	}
+
+	rreconcile.ProgressiveStatus(
+		false,
+		obj,
+		meta.ProgressingReason,
+		"reporting state %s for pull request %d",
+		obj.Spec.State,
+		sync.Status.PullRequestID,
+	)
+
+	if err := r.Provider.CreateCommitStatus(ctx, *repository, sync.Status.PullRequestID, *obj); err != nil {
+		err = fmt.Errorf("failed to create commit status: %w", err)
+		status.MarkNotReady(r.EventRecorder, obj, v1alpha1.CommitStatusUpdateFailedReason, err.Error())
+
+		return err
+	}
+
+	obj.Status.PullRequestID = sync.Status.PullRequestID
+	obj.Status.ReportedState = obj.Spec.State
+	obj.Status.ReportedCommit = sync.Status.Commit
+
+	status.MarkReady(r.EventRecorder, obj, "Commit status reported")
+
+	return nil
+}
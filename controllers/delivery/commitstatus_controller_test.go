@@ -0,0 +1,175 @@
+package delivery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/open-component-model/git-controller/apis/delivery/v1alpha1"
+	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+	"github.com/open-component-model/git-controller/pkg/providers/fakes"
+)
+
+func TestCommitStatusReconciler(t *testing.T) {
+	repository := &mpasv1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-repository",
+			Namespace: "default",
+		},
+		Spec: mpasv1alpha1.RepositorySpec{
+			Provider: "github",
+			Owner:    "open-component-model",
+			Credentials: mpasv1alpha1.Credentials{
+				SecretRef: v1.LocalObjectReference{
+					Name: "auth-secret",
+				},
+			},
+		},
+	}
+	sync := &v1alpha1.Sync{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "git-test",
+			Namespace: "default",
+		},
+		Spec: v1alpha1.SyncSpec{
+			RepositoryRef: meta.NamespacedObjectReference{
+				Name: repository.Name,
+			},
+		},
+		Status: v1alpha1.SyncStatus{
+			PullRequestID: 12,
+			Commit:        "2f0e0c1d",
+		},
+	}
+	commitStatus := &v1alpha1.CommitStatus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "validation",
+			Namespace: "default",
+		},
+		Spec: v1alpha1.CommitStatusSpec{
+			SyncRef: v1.LocalObjectReference{
+				Name: sync.Name,
+			},
+			State:       v1alpha1.CommitStatusStateSuccess,
+			Description: "validation passed",
+		},
+	}
+
+	client := env.FakeKubeClient(WithObjets(commitStatus, sync, repository), WithAddToScheme(mpasv1alpha1.AddToScheme))
+	fakeProvider := fakes.NewProvider()
+	recorder := &record.FakeRecorder{
+		Events:        make(chan string, 32),
+		IncludeObject: true,
+	}
+
+	csr := &CommitStatusReconciler{
+		Client:        client,
+		Scheme:        env.scheme,
+		Provider:      fakeProvider,
+		EventRecorder: recorder,
+	}
+
+	_, err := csr.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: commitStatus.Namespace,
+			Name:      commitStatus.Name,
+		},
+	})
+	require.NoError(t, err)
+
+	err = client.Get(context.Background(), types.NamespacedName{
+		Name:      commitStatus.Name,
+		Namespace: commitStatus.Namespace,
+	}, commitStatus)
+	require.NoError(t, err)
+
+	assert.True(t, conditions.IsTrue(commitStatus, meta.ReadyCondition))
+	assert.Equal(t, 12, commitStatus.Status.PullRequestID)
+	assert.Equal(t, v1alpha1.CommitStatusStateSuccess, commitStatus.Status.ReportedState)
+	assert.Equal(t, v1alpha1.StatusCheckName, commitStatus.GetContext())
+
+	args, err := fakeProvider.CreateCommitStatusCallArgsForNumber(0)
+	require.NoError(t, err)
+	assert.Equal(t, repository.Name, args[0].(mpasv1alpha1.Repository).Name)
+	assert.Equal(t, 12, args[1].(int))
+	assert.Equal(t, v1alpha1.CommitStatusStateSuccess, args[2].(v1alpha1.CommitStatus).Spec.State)
+	assert.Equal(t, "2f0e0c1d", commitStatus.Status.ReportedCommit)
+
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: commitStatus.Namespace, Name: commitStatus.Name}}
+
+	_, err = csr.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, 1, fakeProvider.CreateCommitStatusCallCount, "the state is only reported once per commit")
+
+	sync.Status.Commit = "7c4a9e2b"
+	require.NoError(t, client.Status().Update(context.Background(), sync))
+
+	_, err = csr.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, 2, fakeProvider.CreateCommitStatusCallCount, "a new commit of the pull request gets the state as well")
+
+	require.NoError(t, client.Get(context.Background(), request.NamespacedName, commitStatus))
+	assert.Equal(t, "7c4a9e2b", commitStatus.Status.ReportedCommit)
+}
+
+func TestCommitStatusReconcilerWaitsForPullRequest(t *testing.T) {
+	sync := &v1alpha1.Sync{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "git-test",
+			Namespace: "default",
+		},
+	}
+	commitStatus := &v1alpha1.CommitStatus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "validation",
+			Namespace: "default",
+		},
+		Spec: v1alpha1.CommitStatusSpec{
+			SyncRef: v1.LocalObjectReference{
+				Name: sync.Name,
+			},
+			State: v1alpha1.CommitStatusStateFailure,
+		},
+	}
+
+	client := env.FakeKubeClient(WithObjets(commitStatus, sync))
+	fakeProvider := fakes.NewProvider()
+	recorder := &record.FakeRecorder{
+		Events:        make(chan string, 32),
+		IncludeObject: true,
+	}
+
+	csr := &CommitStatusReconciler{
+		Client:        client,
+		Scheme:        env.scheme,
+		Provider:      fakeProvider,
+		EventRecorder: recorder,
+	}
+
+	_, err := csr.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: commitStatus.Namespace,
+			Name:      commitStatus.Name,
+		},
+	})
+	require.NoError(t, err)
+
+	err = client.Get(context.Background(), types.NamespacedName{
+		Name:      commitStatus.Name,
+		Namespace: commitStatus.Namespace,
+	}, commitStatus)
+	require.NoError(t, err)
+
+	assert.True(t, conditions.IsFalse(commitStatus, meta.ReadyCondition))
+	assert.Equal(t, v1alpha1.PullRequestMissingReason, conditions.GetReason(commitStatus, meta.ReadyCondition))
+	assert.Zero(t, fakeProvider.CreateCommitStatusCallCount)
+}
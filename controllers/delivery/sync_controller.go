@@ -2,32 +2,52 @@ package delivery
 
 import (
 	"context"
+	"crypto/sha256"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/fluxcd/pkg/apis/meta"
 	"github.com/fluxcd/pkg/runtime/patch"
 	rreconcile "github.com/fluxcd/pkg/runtime/reconcile"
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	kuberecorder "k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	sourcev1beta2 "github.com/fluxcd/source-controller/api/v1beta2"
 	ocmv1 "github.com/open-component-model/ocm-controller/api/v1alpha1"
 	"github.com/open-component-model/ocm-controller/pkg/status"
 
 	"github.com/open-component-model/git-controller/apis/delivery/v1alpha1"
 	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+	mpascontrollers "github.com/open-component-model/git-controller/controllers/mpas"
 	"github.com/open-component-model/git-controller/pkg"
+	"github.com/open-component-model/git-controller/pkg/event"
+	"github.com/open-component-model/git-controller/pkg/metrics"
+	"github.com/open-component-model/git-controller/pkg/predicates"
 	"github.com/open-component-model/git-controller/pkg/providers"
+	"github.com/open-component-model/git-controller/pkg/tracing"
 )
 
+// repositoryIndexKey indexes Syncs by the namespaced name of their Repository.
+const repositoryIndexKey = ".spec.repositoryRef"
+
 // SyncReconciler reconciles a Sync object.
 type SyncReconciler struct {
 	client.Client
@@ -36,6 +56,10 @@ type SyncReconciler struct {
 
 	Git      pkg.Git
 	Provider providers.Provider
+	// NoCrossNamespaceRefs denies references to Repositories outside the Sync's namespace.
+	NoCrossNamespaceRefs bool
+	// MaxConcurrentReconciles is the number of Syncs reconciled in parallel.
+	MaxConcurrentReconciles int
 }
 
 //+kubebuilder:rbac:groups=delivery.ocm.software,resources=syncs,verbs=get;list;watch;create;update;patch;delete
@@ -43,13 +67,24 @@ type SyncReconciler struct {
 //+kubebuilder:rbac:groups=delivery.ocm.software,resources=syncs/finalizers,verbs=update
 //+kubebuilder:rbac:groups=delivery.ocm.software,resources=ocmresources,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 //+kubebuilder:rbac:groups=delivery.ocm.software,resources=snapshots,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=delivery.ocm.software,resources=snapshots/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=source.toolkit.fluxcd.io,resources=gitrepositories;ocirepositories;buckets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *SyncReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "Sync.Reconcile",
+		attribute.String("sync.namespace", req.Namespace),
+		attribute.String("sync.name", req.Name),
+	)
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	obj := &v1alpha1.Sync{}
 	if err = r.Get(ctx, req.NamespacedName, obj); err != nil {
 		if apierrors.IsNotFound(err) {
@@ -82,12 +117,17 @@ func (r *SyncReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctr
 
 	// It's important that this happens here so any residual status condition can be overwritten / set.
 	if obj.Status.Digest != "" {
-		status.MarkReady(r.EventRecorder, obj, "Digest already reconciled")
+		event.MarkReady(r.EventRecorder, obj, "Digest already reconciled")
 
 		return ctrl.Result{}, nil
 	}
 
 	if err := r.reconcile(ctx, obj); err != nil {
+		// Retrying before the provider's quota resets would only fail again.
+		if retryAfter, ok := providers.RetryAfter(err); ok {
+			return ctrl.Result{RequeueAfter: retryAfter}, nil
+		}
+
 		return ctrl.Result{}, err
 	}
 
@@ -96,11 +136,295 @@ func (r *SyncReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctr
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *SyncReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&v1alpha1.Sync{},
+		repositoryIndexKey,
+		indexRepository,
+	); err != nil {
+		return fmt.Errorf("failed to set index field '%s': %w", repositoryIndexKey, err)
+	}
+
+	// Secrets are mapped to Syncs through the Repositories using them.
+	if err := mpascontrollers.SetupSecretIndex(mgr); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.Sync{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.findSyncsForSecret),
+			builder.WithPredicates(predicates.SecretDataChangedPredicate{}),
+		).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Complete(r)
 }
 
+// indexRepository returns the namespaced name of the Repository a Sync pushes to.
+func indexRepository(obj client.Object) []string {
+	sync, ok := obj.(*v1alpha1.Sync)
+	if !ok {
+		return nil
+	}
+
+	namespace := sync.Spec.RepositoryRef.Namespace
+	if namespace == "" {
+		namespace = sync.Namespace
+	}
+
+	return []string{types.NamespacedName{Namespace: namespace, Name: sync.Spec.RepositoryRef.Name}.String()}
+}
+
+// findSyncsForSecret enqueues the Syncs of every Repository using the Secret as credentials so that failed
+// pushes are retried with rotated credentials.
+func (r *SyncReconciler) findSyncsForSecret(secret client.Object) []reconcile.Request {
+	ctx := context.Background()
+	logger := log.FromContext(ctx).WithValues("secret", client.ObjectKeyFromObject(secret))
+
+	repositories := &mpasv1alpha1.RepositoryList{}
+	if err := r.List(ctx, repositories,
+		client.InNamespace(secret.GetNamespace()),
+		client.MatchingFields{mpascontrollers.SecretIndexKey: secret.GetName()},
+	); err != nil {
+		logger.Error(err, "failed to list repositories referencing secret")
+
+		return nil
+	}
+
+	var requests []reconcile.Request
+
+	for _, repository := range repositories.Items {
+		// The index also contains the Secrets of webhooks and sources, which aren't used for pushing.
+		if repository.Spec.Credentials.SecretRef.Name != secret.GetName() {
+			continue
+		}
+
+		syncs := &v1alpha1.SyncList{}
+		if err := r.List(ctx, syncs, client.MatchingFields{
+			repositoryIndexKey: client.ObjectKeyFromObject(&repository).String(),
+		}); err != nil {
+			logger.Error(err, "failed to list syncs of repository", "repository", client.ObjectKeyFromObject(&repository))
+
+			return nil
+		}
+
+		for _, sync := range syncs.Items {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&sync),
+			})
+		}
+	}
+
+	return requests
+}
+
+// syncContent is the content of a Sync entry together with its status.
+type syncContent struct {
+	content pkg.Content
+	digest  v1alpha1.SnapshotDigest
+}
+
+// getSources fetches the snapshots and sources of a Sync in the order they are extracted.
+func (r *SyncReconciler) getSources(ctx context.Context, obj *v1alpha1.Sync) (_ []syncContent, err error) {
+	ctx, span := tracing.Start(ctx, "Sync.GetSources")
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	var sources []syncContent
+
+	for _, ref := range obj.GetSnapshots() {
+		kind, name := v1alpha1.SnapshotKind, ref.SnapshotRef.Name
+		if ref.SourceRef != nil {
+			kind, name = ref.SourceRef.Kind, ref.SourceRef.Name
+		}
+
+		src, err := r.getSource(ctx, types.NamespacedName{Namespace: obj.Namespace, Name: name}, kind)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s '%s': %w", kind, name, err)
+		}
+
+		src.content.SubPath = ref.SubPath
+		src.digest.SubPath = ref.SubPath
+		sources = append(sources, src)
+	}
+
+	return sources, nil
+}
+
+// getSource fetches a single snapshot, Flux source or ConfigMap.
+func (r *SyncReconciler) getSource(ctx context.Context, key types.NamespacedName, kind string) (syncContent, error) {
+	switch kind {
+	case v1alpha1.SnapshotKind:
+		snapshot := &ocmv1.Snapshot{}
+		if err := r.Get(ctx, key, snapshot); err != nil {
+			return syncContent{}, err
+		}
+
+		return syncContent{
+			content: pkg.Content{Snapshot: snapshot},
+			digest:  v1alpha1.SnapshotDigest{Name: key.Name, Digest: snapshot.Spec.Digest},
+		}, nil
+	case v1alpha1.ConfigMapKind:
+		configMap := &corev1.ConfigMap{}
+		if err := r.Get(ctx, key, configMap); err != nil {
+			return syncContent{}, err
+		}
+
+		files := configMapFiles(configMap)
+
+		return syncContent{
+			content: pkg.Content{Files: files},
+			digest:  v1alpha1.SnapshotDigest{Kind: kind, Name: key.Name, Digest: filesDigest(files)},
+		}, nil
+	}
+
+	var obj interface {
+		client.Object
+		GetArtifact() *sourcev1.Artifact
+	}
+
+	switch kind {
+	case v1alpha1.GitRepositoryKind:
+		obj = &sourcev1.GitRepository{}
+	case v1alpha1.OCIRepositoryKind:
+		obj = &sourcev1beta2.OCIRepository{}
+	case v1alpha1.BucketKind:
+		obj = &sourcev1beta2.Bucket{}
+	default:
+		return syncContent{}, fmt.Errorf("unsupported source kind '%s'", kind)
+	}
+
+	if err := r.Get(ctx, key, obj); err != nil {
+		return syncContent{}, err
+	}
+
+	artifact := obj.GetArtifact()
+	if artifact == nil {
+		return syncContent{}, errors.New("source has no artifact yet")
+	}
+
+	return syncContent{
+		content: pkg.Content{Artifact: artifact},
+		digest:  v1alpha1.SnapshotDigest{Kind: kind, Name: key.Name, Digest: artifact.Digest},
+	}, nil
+}
+
+// configMapFiles returns a file per key of the ConfigMap.
+func configMapFiles(configMap *corev1.ConfigMap) map[string][]byte {
+	files := make(map[string][]byte, len(configMap.Data)+len(configMap.BinaryData))
+	for name, data := range configMap.Data {
+		files[name] = []byte(data)
+	}
+
+	for name, data := range configMap.BinaryData {
+		files[name] = data
+	}
+
+	return files
+}
+
+// filesDigest calculates a digest from the names and content of the files.
+func filesDigest(files map[string][]byte) string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(h, "%s\n%d\n", name, len(files[name]))
+		h.Write(files[name])
+	}
+
+	return fmt.Sprintf("sha256:%x", h.Sum(nil))
+}
+
+// getRepository fetches the Repository a Sync pushes to and its credentials, and verifies that the Sync may use it.
+func (r *SyncReconciler) getRepository(
+	ctx context.Context,
+	obj *v1alpha1.Sync,
+	namespace string,
+) (_ *mpasv1alpha1.Repository, _ *corev1.Secret, err error) {
+	ctx, span := tracing.Start(ctx, "Sync.GetRepository",
+		attribute.String("repository.namespace", namespace),
+		attribute.String("repository.name", obj.Spec.RepositoryRef.Name),
+	)
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	repository := &mpasv1alpha1.Repository{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Namespace: namespace,
+		Name:      obj.Spec.RepositoryRef.Name,
+	}, repository); err != nil {
+		err = fmt.Errorf("failed to find repository: %w", err)
+		status.MarkNotReady(r.EventRecorder, obj, v1alpha1.RepositoryGetFailedReason, err.Error())
+
+		return nil, nil, err
+	}
+
+	if err := r.checkAccess(ctx, obj, repository); err != nil {
+		status.MarkNotReady(r.EventRecorder, obj, v1alpha1.AccessDeniedReason, err.Error())
+
+		return nil, nil, err
+	}
+
+	authSecret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Namespace: repository.Namespace,
+		Name:      repository.Spec.Credentials.SecretRef.Name,
+	}, authSecret); err != nil {
+		err = fmt.Errorf("failed to find authentication secret: %w", err)
+		status.MarkNotReady(r.EventRecorder, obj, v1alpha1.CredentialsNotFoundReason, err.Error())
+
+		return nil, nil, err
+	}
+
+	span.SetAttributes(attribute.String("provider", repository.Spec.Provider))
+
+	return repository, authSecret, nil
+}
+
+// checkAccess verifies that the Sync's namespace may use the Repository. References within the same namespace are
+// always allowed.
+func (r *SyncReconciler) checkAccess(ctx context.Context, obj *v1alpha1.Sync, repository *mpasv1alpha1.Repository) error {
+	if obj.Namespace == repository.Namespace {
+		return nil
+	}
+
+	if r.NoCrossNamespaceRefs {
+		return fmt.Errorf("cross-namespace references are not allowed, can't use repository '%s/%s'", repository.Namespace, repository.Name)
+	}
+
+	allowed := repository.Spec.AllowedNamespaces
+	if allowed == nil || slices.Contains(allowed.Names, obj.Namespace) {
+		return nil
+	}
+
+	if allowed.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(allowed.Selector)
+		if err != nil {
+			return fmt.Errorf("failed to parse namespace selector of repository '%s/%s': %w", repository.Namespace, repository.Name, err)
+		}
+
+		namespace := &corev1.Namespace{}
+		if err := r.Get(ctx, types.NamespacedName{Name: obj.Namespace}, namespace); err != nil {
+			return fmt.Errorf("failed to get namespace '%s': %w", obj.Namespace, err)
+		}
+
+		if selector.Matches(labels.Set(namespace.Labels)) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("namespace '%s' is not allowed to use repository '%s/%s'", obj.Namespace, repository.Namespace, repository.Name)
+}
+
 func (r *SyncReconciler) parseAuthSecret(secret *corev1.Secret, opts *pkg.PushOptions) {
 	if _, ok := secret.Data["identity"]; ok {
 		opts.Auth = &pkg.Auth{
@@ -122,7 +446,7 @@ func (r *SyncReconciler) parseAuthSecret(secret *corev1.Secret, opts *pkg.PushOp
 	}
 }
 
-func (r *SyncReconciler) reconcile(ctx context.Context, obj *v1alpha1.Sync) error {
+func (r *SyncReconciler) reconcile(ctx context.Context, obj *v1alpha1.Sync) (err error) {
 	if obj.Generation != obj.Status.ObservedGeneration {
 		rreconcile.ProgressiveStatus(
 			false,
@@ -134,12 +458,9 @@ func (r *SyncReconciler) reconcile(ctx context.Context, obj *v1alpha1.Sync) erro
 		)
 	}
 
-	snapshot := &ocmv1.Snapshot{}
-	if err := r.Get(ctx, types.NamespacedName{
-		Namespace: obj.Namespace,
-		Name:      obj.Spec.SnapshotRef.Name,
-	}, snapshot); err != nil {
-		err = fmt.Errorf("failed to find snapshot: %w", err)
+	sources, err := r.getSources(ctx, obj)
+	if err != nil {
+		err = fmt.Errorf("failed to find content to push: %w", err)
 		status.MarkNotReady(r.EventRecorder, obj, v1alpha1.SnapshotGetFailedReason, err.Error())
 
 		return err
@@ -150,31 +471,14 @@ func (r *SyncReconciler) reconcile(ctx context.Context, obj *v1alpha1.Sync) erro
 		namespace = obj.Namespace
 	}
 
-	repository := &mpasv1alpha1.Repository{}
-	if err := r.Get(ctx, types.NamespacedName{
-		Namespace: namespace,
-		Name:      obj.Spec.RepositoryRef.Name,
-	}, repository); err != nil {
-		err = fmt.Errorf("failed to find repository: %w", err)
-		status.MarkNotReady(r.EventRecorder, obj, v1alpha1.RepositoryGetFailedReason, err.Error())
-
-		return err
-	}
-
-	authSecret := &corev1.Secret{}
-	if err := r.Get(ctx, types.NamespacedName{
-		Namespace: repository.Namespace,
-		Name:      repository.Spec.Credentials.SecretRef.Name,
-	}, authSecret); err != nil {
-		err = fmt.Errorf("failed to find authentication secret: %w", err)
-		status.MarkNotReady(r.EventRecorder, obj, v1alpha1.CredentialsNotFoundReason, err.Error())
-
+	repository, authSecret, err := r.getRepository(ctx, obj, namespace)
+	if err != nil {
 		return err
 	}
 
 	baseBranch := obj.Spec.CommitTemplate.BaseBranch
 	if baseBranch == "" {
-		baseBranch = "main"
+		baseBranch = v1alpha1.DefaultBaseBranch
 	}
 
 	targetBranch := obj.Spec.CommitTemplate.TargetBranch
@@ -201,16 +505,14 @@ func (r *SyncReconciler) reconcile(ctx context.Context, obj *v1alpha1.Sync) erro
 		Message:      obj.Spec.CommitTemplate.Message,
 		Name:         obj.Spec.CommitTemplate.Name,
 		Email:        obj.Spec.CommitTemplate.Email,
-		Snapshot:     snapshot,
+		Content:      contents(sources),
 		BaseBranch:   baseBranch,
 		TargetBranch: targetBranch,
-		SubPath:      obj.Spec.SubPath,
 	}
 
 	r.parseAuthSecret(authSecret, opts)
 
-	var digest string
-	digest, err := r.Git.Push(ctx, opts)
+	result, err := r.Git.Push(ctx, opts)
 	if err != nil {
 		err = fmt.Errorf("failed to push to git repository: %w", err)
 		status.MarkNotReady(r.EventRecorder, obj, v1alpha1.GitRepositoryPushFailedReason, err.Error())
@@ -218,12 +520,17 @@ func (r *SyncReconciler) reconcile(ctx context.Context, obj *v1alpha1.Sync) erro
 		return err
 	}
 
-	obj.Status.Digest = digest
+	obj.Status.Snapshots = snapshotDigests(sources)
+	obj.Status.Digest = combinedDigest(obj.Status.Snapshots)
+	obj.Status.Commit = result.Commit
+	obj.Status.CommitURL = r.Provider.CommitURL(*repository, result.Commit)
+	obj.Status.Branch = targetBranch
+	obj.Status.ComponentName, obj.Status.ComponentVersion = commonComponent(sources)
 
 	if obj.Spec.AutomaticPullRequestCreation {
 		rreconcile.ProgressiveStatus(false, obj, meta.ProgressingReason, "creating pull request")
 
-		id, err := r.Provider.CreatePullRequest(ctx, targetBranch, *obj, *repository)
+		pr, err := r.Provider.CreatePullRequest(ctx, targetBranch, *obj, *repository)
 		if err != nil {
 			err = fmt.Errorf("failed to create pull request: %w", err)
 			status.MarkNotReady(r.EventRecorder, obj, v1alpha1.CreatePullRequestFailedReason, err.Error())
@@ -231,10 +538,70 @@ func (r *SyncReconciler) reconcile(ctx context.Context, obj *v1alpha1.Sync) erro
 			return err
 		}
 
-		obj.Status.PullRequestID = id
+		obj.Status.PullRequestID = pr.ID
+		obj.Status.PullRequestURL = pr.URL
+
+		metrics.PullRequestsOpened.WithLabelValues(repository.Spec.Provider).Inc()
 	}
 
-	status.MarkReady(r.EventRecorder, obj, "Reconciliation success")
+	event.MarkReady(r.EventRecorder, obj, "Reconciliation success")
 
 	return nil
 }
+
+// contents returns the content to push of every source.
+func contents(sources []syncContent) []pkg.Content {
+	content := make([]pkg.Content, 0, len(sources))
+	for _, src := range sources {
+		content = append(content, src.content)
+	}
+
+	return content
+}
+
+// snapshotDigests records the digest of every pushed snapshot and source.
+func snapshotDigests(sources []syncContent) []v1alpha1.SnapshotDigest {
+	digests := make([]v1alpha1.SnapshotDigest, 0, len(sources))
+	for _, src := range sources {
+		digests = append(digests, src.digest)
+	}
+
+	return digests
+}
+
+// combinedDigest is the digest of a single snapshot, or a digest calculated from the paths and digests of all
+// snapshots if there are several.
+func combinedDigest(digests []v1alpha1.SnapshotDigest) string {
+	if len(digests) == 1 {
+		return digests[0].Digest
+	}
+
+	h := sha256.New()
+	for _, digest := range digests {
+		fmt.Fprintf(h, "%s=%s\n", digest.SubPath, digest.Digest)
+	}
+
+	return fmt.Sprintf("sha256:%x", h.Sum(nil))
+}
+
+// commonComponent returns the component name and version the snapshots belong to. Both are empty if the snapshots
+// belong to different component versions or if other sources are pushed.
+func commonComponent(sources []syncContent) (string, string) {
+	var name, version string
+
+	for i, src := range sources {
+		snapshot := src.content.Snapshot
+		if snapshot == nil {
+			return "", ""
+		}
+
+		n, v := snapshot.Spec.Identity[ocmv1.ComponentNameKey], snapshot.GetComponentVersion()
+		if i > 0 && (n != name || v != version) {
+			return "", ""
+		}
+
+		name, version = n, v
+	}
+
+	return name, version
+}
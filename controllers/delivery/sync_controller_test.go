@@ -7,6 +7,7 @@ import (
 
 	"github.com/fluxcd/pkg/apis/meta"
 	"github.com/fluxcd/pkg/runtime/conditions"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
 	sourcebeta2 "github.com/fluxcd/source-controller/api/v1beta2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -20,7 +21,9 @@ import (
 
 	"github.com/open-component-model/git-controller/apis/delivery/v1alpha1"
 	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+	mpascontrollers "github.com/open-component-model/git-controller/controllers/mpas"
 	"github.com/open-component-model/git-controller/pkg"
+	"github.com/open-component-model/git-controller/pkg/providers"
 	"github.com/open-component-model/git-controller/pkg/providers/fakes"
 )
 
@@ -78,9 +81,7 @@ func TestSyncReconciler(t *testing.T) {
 	}
 
 	client := env.FakeKubeClient(WithObjets(sync, snapshot, secret, repository), WithAddToScheme(ocmv1.AddToScheme), WithAddToScheme(mpasv1alpha1.AddToScheme))
-	m := &mockGit{
-		digest: "test-digest",
-	}
+	m := &mockGit{}
 	recorder := &record.FakeRecorder{
 		Events:        make(chan string, 32),
 		IncludeObject: true,
@@ -90,6 +91,7 @@ func TestSyncReconciler(t *testing.T) {
 		Client:        client,
 		Scheme:        env.scheme,
 		Git:           m,
+		Provider:      fakes.NewProvider(),
 		EventRecorder: recorder,
 	}
 
@@ -111,6 +113,309 @@ func TestSyncReconciler(t *testing.T) {
 	assert.True(t, conditions.IsTrue(sync, meta.ReadyCondition))
 }
 
+func TestSyncReconcilerWithSeveralSnapshots(t *testing.T) {
+	frontend := DefaultSnapshot.DeepCopy()
+	frontend.Name = "frontend"
+	frontend.Spec.Digest = "sha256:frontend"
+	backend := DefaultSnapshot.DeepCopy()
+	backend.Name = "backend"
+	backend.Spec.Digest = "sha256:backend"
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "auth-secret",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"username": []byte("username"),
+			"password": []byte("password"),
+		},
+	}
+	repository := &mpasv1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-repository",
+			Namespace: "default",
+		},
+		Spec: mpasv1alpha1.RepositorySpec{
+			Provider: "github",
+			Owner:    "open-component-model",
+			Credentials: mpasv1alpha1.Credentials{
+				SecretRef: v1.LocalObjectReference{
+					Name: secret.Name,
+				},
+			},
+		},
+	}
+	sync := &v1alpha1.Sync{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "git-test",
+			Namespace: "default",
+		},
+		Spec: v1alpha1.SyncSpec{
+			RepositoryRef: meta.NamespacedObjectReference{
+				Name: repository.Name,
+			},
+			CommitTemplate: v1alpha1.CommitTemplate{
+				TargetBranch: "main",
+				Name:         "open-component-model",
+				Email:        "email@mail.com",
+				Message:      "This is my message",
+			},
+			Snapshots: []v1alpha1.SnapshotPath{
+				{SnapshotRef: v1.LocalObjectReference{Name: frontend.Name}, SubPath: "apps/frontend"},
+				{SnapshotRef: v1.LocalObjectReference{Name: backend.Name}, SubPath: "apps/backend"},
+			},
+		},
+	}
+
+	client := env.FakeKubeClient(
+		WithObjets(sync, frontend, backend, secret, repository),
+		WithAddToScheme(ocmv1.AddToScheme),
+		WithAddToScheme(mpasv1alpha1.AddToScheme),
+	)
+	m := &mockGit{commit: "2f0e0c1d"}
+
+	gsr := &SyncReconciler{
+		Client:        client,
+		Scheme:        env.scheme,
+		Git:           m,
+		Provider:      fakes.NewProvider(),
+		EventRecorder: record.NewFakeRecorder(32),
+	}
+
+	_, err := gsr.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: sync.Namespace,
+			Name:      sync.Name,
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, m.opts.Content, 2)
+	assert.Equal(t, frontend.Name, m.opts.Content[0].Snapshot.Name)
+	assert.Equal(t, "apps/frontend", m.opts.Content[0].SubPath)
+	assert.Equal(t, backend.Name, m.opts.Content[1].Snapshot.Name)
+	assert.Equal(t, "apps/backend", m.opts.Content[1].SubPath)
+
+	err = client.Get(context.Background(), types.NamespacedName{
+		Name:      sync.Name,
+		Namespace: sync.Namespace,
+	}, sync)
+	require.NoError(t, err)
+
+	assert.True(t, conditions.IsTrue(sync, meta.ReadyCondition))
+	assert.Equal(t, []v1alpha1.SnapshotDigest{
+		{Name: frontend.Name, SubPath: "apps/frontend", Digest: "sha256:frontend"},
+		{Name: backend.Name, SubPath: "apps/backend", Digest: "sha256:backend"},
+	}, sync.Status.Snapshots)
+	assert.Regexp(t, "^sha256:[0-9a-f]{64}$", sync.Status.Digest)
+	assert.Equal(t, "2f0e0c1d", sync.Status.Commit)
+	assert.Equal(t, DefaultComponent.Name, sync.Status.ComponentName)
+	assert.Equal(t, "v0.0.1", sync.Status.ComponentVersion)
+}
+
+func TestSyncReconcilerWithSources(t *testing.T) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "auth-secret",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"username": []byte("username"),
+			"password": []byte("password"),
+		},
+	}
+	repository := &mpasv1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-repository",
+			Namespace: "default",
+		},
+		Spec: mpasv1alpha1.RepositorySpec{
+			Provider: "github",
+			Owner:    "open-component-model",
+			Credentials: mpasv1alpha1.Credentials{
+				SecretRef: v1.LocalObjectReference{
+					Name: secret.Name,
+				},
+			},
+		},
+	}
+	gitRepository := &sourcev1.GitRepository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "manifests",
+			Namespace: "default",
+		},
+		Status: sourcev1.GitRepositoryStatus{
+			Artifact: &sourcev1.Artifact{
+				URL:    "http://source-controller/gitrepository/default/manifests/latest.tar.gz",
+				Digest: "sha256:manifests",
+			},
+		},
+	}
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "values",
+			Namespace: "default",
+		},
+		Data: map[string]string{
+			"values.yaml": "replicas: 2",
+		},
+		BinaryData: map[string][]byte{
+			"logo.png": {0x89, 0x50, 0x4e, 0x47},
+		},
+	}
+	sync := &v1alpha1.Sync{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "git-test",
+			Namespace: "default",
+		},
+		Spec: v1alpha1.SyncSpec{
+			RepositoryRef: meta.NamespacedObjectReference{
+				Name: repository.Name,
+			},
+			CommitTemplate: v1alpha1.CommitTemplate{
+				TargetBranch: "main",
+				Name:         "open-component-model",
+				Email:        "email@mail.com",
+				Message:      "This is my message",
+			},
+			Snapshots: []v1alpha1.SnapshotPath{
+				{SourceRef: &v1alpha1.SourceReference{Kind: v1alpha1.GitRepositoryKind, Name: gitRepository.Name}, SubPath: "manifests"},
+				{SourceRef: &v1alpha1.SourceReference{Kind: v1alpha1.ConfigMapKind, Name: configMap.Name}, SubPath: "values"},
+			},
+		},
+	}
+
+	client := env.FakeKubeClient(
+		WithObjets(sync, gitRepository, configMap, secret, repository),
+		WithAddToScheme(mpasv1alpha1.AddToScheme),
+		WithAddToScheme(sourcev1.AddToScheme),
+	)
+	m := &mockGit{commit: "2f0e0c1d"}
+
+	gsr := &SyncReconciler{
+		Client:        client,
+		Scheme:        env.scheme,
+		Git:           m,
+		Provider:      fakes.NewProvider(),
+		EventRecorder: record.NewFakeRecorder(32),
+	}
+
+	_, err := gsr.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: sync.Namespace,
+			Name:      sync.Name,
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, m.opts.Content, 2)
+	assert.Equal(t, gitRepository.Status.Artifact.URL, m.opts.Content[0].Artifact.URL)
+	assert.Equal(t, "manifests", m.opts.Content[0].SubPath)
+	assert.Equal(t, map[string][]byte{
+		"values.yaml": []byte("replicas: 2"),
+		"logo.png":    {0x89, 0x50, 0x4e, 0x47},
+	}, m.opts.Content[1].Files)
+	assert.Equal(t, "values", m.opts.Content[1].SubPath)
+
+	err = client.Get(context.Background(), types.NamespacedName{
+		Name:      sync.Name,
+		Namespace: sync.Namespace,
+	}, sync)
+	require.NoError(t, err)
+
+	assert.True(t, conditions.IsTrue(sync, meta.ReadyCondition))
+	require.Len(t, sync.Status.Snapshots, 2)
+	assert.Equal(t, v1alpha1.SnapshotDigest{
+		Kind:    v1alpha1.GitRepositoryKind,
+		Name:    gitRepository.Name,
+		SubPath: "manifests",
+		Digest:  "sha256:manifests",
+	}, sync.Status.Snapshots[0])
+	assert.Equal(t, v1alpha1.ConfigMapKind, sync.Status.Snapshots[1].Kind)
+	assert.Regexp(t, "^sha256:[0-9a-f]{64}$", sync.Status.Snapshots[1].Digest)
+	assert.Empty(t, sync.Status.ComponentName)
+}
+
+func TestSyncReconcilerWaitsForSourceArtifact(t *testing.T) {
+	ociRepository := &sourcebeta2.OCIRepository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "manifests",
+			Namespace: "default",
+		},
+	}
+	sync := &v1alpha1.Sync{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "git-test",
+			Namespace: "default",
+		},
+		Spec: v1alpha1.SyncSpec{
+			SourceRef: &v1alpha1.SourceReference{Kind: v1alpha1.OCIRepositoryKind, Name: ociRepository.Name},
+			RepositoryRef: meta.NamespacedObjectReference{
+				Name: "test-repository",
+			},
+			CommitTemplate: v1alpha1.CommitTemplate{
+				TargetBranch: "main",
+				Name:         "open-component-model",
+				Email:        "email@mail.com",
+				Message:      "This is my message",
+			},
+		},
+	}
+
+	client := env.FakeKubeClient(WithObjets(sync, ociRepository), WithAddToScheme(sourcebeta2.AddToScheme))
+	m := &mockGit{}
+
+	gsr := &SyncReconciler{
+		Client:        client,
+		Scheme:        env.scheme,
+		Git:           m,
+		Provider:      fakes.NewProvider(),
+		EventRecorder: record.NewFakeRecorder(32),
+	}
+
+	_, err := gsr.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: sync.Namespace,
+			Name:      sync.Name,
+		},
+	})
+	assert.EqualError(t, err, "failed to find content to push: failed to get OCIRepository 'manifests': source has no artifact yet")
+	assert.False(t, m.called)
+}
+
+func TestFilesDigest(t *testing.T) {
+	digest := filesDigest(map[string][]byte{"a": []byte("bc"), "d": []byte("e")})
+
+	assert.Equal(t, digest, filesDigest(map[string][]byte{"d": []byte("e"), "a": []byte("bc")}))
+	assert.NotEqual(t, digest, filesDigest(map[string][]byte{"a": []byte("b"), "d": []byte("ce")}))
+}
+
+func TestCommonComponent(t *testing.T) {
+	snapshot := func(name, version string) syncContent {
+		return syncContent{content: pkg.Content{Snapshot: &ocmv1.Snapshot{
+			Spec: ocmv1.SnapshotSpec{
+				Identity: map[string]string{
+					ocmv1.ComponentNameKey:    name,
+					ocmv1.ComponentVersionKey: version,
+				},
+			},
+		}}}
+	}
+
+	name, version := commonComponent([]syncContent{snapshot("product", "v1"), snapshot("product", "v1")})
+	assert.Equal(t, "product", name)
+	assert.Equal(t, "v1", version)
+
+	name, version = commonComponent([]syncContent{snapshot("product", "v1"), snapshot("product", "v2")})
+	assert.Empty(t, name)
+	assert.Empty(t, version)
+
+	name, version = commonComponent([]syncContent{snapshot("product", "v1"), {content: pkg.Content{Files: map[string][]byte{}}}})
+	assert.Empty(t, name)
+	assert.Empty(t, version)
+}
+
 func TestSyncReconcilerIsSkippedIfDigestIsAlreadyPresent(t *testing.T) {
 	sync := &v1alpha1.Sync{
 		ObjectMeta: metav1.ObjectMeta{
@@ -140,9 +445,7 @@ func TestSyncReconcilerIsSkippedIfDigestIsAlreadyPresent(t *testing.T) {
 	}
 
 	client := env.FakeKubeClient(WithObjets(sync))
-	m := &mockGit{
-		digest: "test-digest",
-	}
+	m := &mockGit{}
 	fakeProvider := fakes.NewProvider()
 	recorder := &record.FakeRecorder{
 		Events:        make(chan string, 32),
@@ -198,6 +501,9 @@ func TestSyncReconcilerWithAutomaticPullRequest(t *testing.T) {
 			Visibility:               "public",
 			ExistingRepositoryPolicy: mpasv1alpha1.ExistingRepositoryPolicyAdopt,
 		},
+		Status: mpasv1alpha1.RepositoryStatus{
+			WebURL: "https://github.com/open-component-model/test-repository",
+		},
 	}
 	sync := &v1alpha1.Sync{
 		ObjectMeta: metav1.ObjectMeta{
@@ -235,9 +541,13 @@ func TestSyncReconcilerWithAutomaticPullRequest(t *testing.T) {
 		WithAddToScheme(sourcebeta2.AddToScheme),
 	)
 	m := &mockGit{
-		digest: "test-digest",
+		commit: "2f0e0c1d",
 	}
 	fakeProvider := fakes.NewProvider()
+	fakeProvider.PullRequest = providers.PullRequest{
+		ID:  7,
+		URL: "https://github.com/open-component-model/test-repository/pull/7",
+	}
 	recorder := &record.FakeRecorder{
 		Events:        make(chan string, 32),
 		IncludeObject: true,
@@ -273,15 +583,246 @@ func TestSyncReconcilerWithAutomaticPullRequest(t *testing.T) {
 
 	branch := args[0]
 	assert.NotEmpty(t, branch.(string))
+
+	assert.Equal(t, "2f0e0c1d", sync.Status.Commit)
+	assert.Equal(t, "https://github.com/open-component-model/test-repository/commit/2f0e0c1d", sync.Status.CommitURL)
+	assert.Equal(t, branch, sync.Status.Branch)
+	assert.Equal(t, 7, sync.Status.PullRequestID)
+	assert.Equal(t, "https://github.com/open-component-model/test-repository/pull/7", sync.Status.PullRequestURL)
+	assert.Equal(t, DefaultComponent.Name, sync.Status.ComponentName)
+	assert.Equal(t, "v0.0.1", sync.Status.ComponentVersion)
 }
 
 type mockGit struct {
-	digest string
+	commit string
 	err    error
 	called bool
+	opts   *pkg.PushOptions
 }
 
-func (g *mockGit) Push(ctx context.Context, opts *pkg.PushOptions) (string, error) {
+func (g *mockGit) Push(ctx context.Context, opts *pkg.PushOptions) (pkg.PushResult, error) {
 	g.called = true
-	return g.digest, g.err
+	g.opts = opts
+	return pkg.PushResult{Commit: g.commit}, g.err
+}
+
+func TestSyncReconcilerFindsSyncsForSecret(t *testing.T) {
+	repository := &mpasv1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "repository",
+			Namespace: "default",
+		},
+		Spec: mpasv1alpha1.RepositorySpec{
+			Credentials: mpasv1alpha1.Credentials{
+				SecretRef: v1.LocalObjectReference{
+					Name: "rotated",
+				},
+			},
+		},
+	}
+	unrelatedRepository := repository.DeepCopy()
+	unrelatedRepository.Name = "unrelated"
+	unrelatedRepository.Spec.Credentials.SecretRef.Name = "other"
+	webhookRepository := unrelatedRepository.DeepCopy()
+	webhookRepository.Name = "webhook"
+	webhookRepository.Spec.Webhooks = []mpasv1alpha1.Webhook{
+		{URL: "https://example.com/hook", SecretRef: &v1.LocalObjectReference{Name: "rotated"}},
+	}
+
+	newSync := func(name, namespace string, ref meta.NamespacedObjectReference) *v1alpha1.Sync {
+		return &v1alpha1.Sync{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: v1alpha1.SyncSpec{
+				RepositoryRef: ref,
+			},
+		}
+	}
+
+	client := env.FakeKubeClient(
+		WithAddToScheme(mpasv1alpha1.AddToScheme),
+		WithObjets(
+			repository,
+			unrelatedRepository,
+			webhookRepository,
+			newSync("local", "default", meta.NamespacedObjectReference{Name: "repository"}),
+			newSync("remote", "other", meta.NamespacedObjectReference{Name: "repository", Namespace: "default"}),
+			newSync("same-name", "other", meta.NamespacedObjectReference{Name: "repository"}),
+			newSync("unrelated", "default", meta.NamespacedObjectReference{Name: "unrelated"}),
+			newSync("webhook", "default", meta.NamespacedObjectReference{Name: "webhook"}),
+		),
+		WithIndex(&v1alpha1.Sync{}, repositoryIndexKey, indexRepository),
+		WithIndex(&mpasv1alpha1.Repository{}, mpascontrollers.SecretIndexKey, mpascontrollers.IndexSecrets),
+	)
+
+	r := &SyncReconciler{
+		Client: client,
+	}
+
+	requests := r.findSyncsForSecret(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rotated",
+			Namespace: "default",
+		},
+	})
+
+	assert.ElementsMatch(t, []ctrl.Request{
+		{NamespacedName: types.NamespacedName{Name: "local", Namespace: "default"}},
+		{NamespacedName: types.NamespacedName{Name: "remote", Namespace: "other"}},
+	}, requests)
+}
+
+func TestSyncReconcilerCheckAccess(t *testing.T) {
+	tenant := &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "tenant",
+			Labels: map[string]string{"team": "platform"},
+		},
+	}
+
+	testCases := []struct {
+		name                 string
+		namespace            string
+		allowed              *mpasv1alpha1.AllowedNamespaces
+		noCrossNamespaceRefs bool
+		err                  string
+	}{
+		{
+			name:                 "same namespace is always allowed",
+			namespace:            "default",
+			noCrossNamespaceRefs: true,
+			allowed:              &mpasv1alpha1.AllowedNamespaces{},
+		},
+		{
+			name:      "any namespace is allowed without restrictions",
+			namespace: "tenant",
+		},
+		{
+			name:                 "cross-namespace references are denied by the flag",
+			namespace:            "tenant",
+			noCrossNamespaceRefs: true,
+			allowed:              &mpasv1alpha1.AllowedNamespaces{Names: []string{"tenant"}},
+			err:                  "cross-namespace references are not allowed",
+		},
+		{
+			name:      "namespace allowed by name",
+			namespace: "tenant",
+			allowed:   &mpasv1alpha1.AllowedNamespaces{Names: []string{"tenant"}},
+		},
+		{
+			name:      "namespace allowed by selector",
+			namespace: "tenant",
+			allowed: &mpasv1alpha1.AllowedNamespaces{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "platform"}},
+			},
+		},
+		{
+			name:      "namespace not matching the selector is denied",
+			namespace: "tenant",
+			allowed: &mpasv1alpha1.AllowedNamespaces{
+				Names:    []string{"other"},
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "security"}},
+			},
+			err: "namespace 'tenant' is not allowed to use repository 'default/repository'",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repository := &mpasv1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "repository",
+					Namespace: "default",
+				},
+				Spec: mpasv1alpha1.RepositorySpec{
+					AllowedNamespaces: tc.allowed,
+				},
+			}
+			sync := &v1alpha1.Sync{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sync",
+					Namespace: tc.namespace,
+				},
+			}
+
+			r := &SyncReconciler{
+				Client:               env.FakeKubeClient(WithObjets(tenant)),
+				NoCrossNamespaceRefs: tc.noCrossNamespaceRefs,
+			}
+
+			err := r.checkAccess(context.Background(), sync, repository)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSyncReconcilerDeniesCrossNamespaceReference(t *testing.T) {
+	snapshot := DefaultSnapshot.DeepCopy()
+	snapshot.Namespace = "tenant"
+	repository := &mpasv1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "repository",
+			Namespace: "default",
+		},
+		Spec: mpasv1alpha1.RepositorySpec{
+			AllowedNamespaces: &mpasv1alpha1.AllowedNamespaces{
+				Names: []string{"other"},
+			},
+		},
+	}
+	sync := &v1alpha1.Sync{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sync",
+			Namespace: "tenant",
+		},
+		Spec: v1alpha1.SyncSpec{
+			SnapshotRef: v1.LocalObjectReference{
+				Name: snapshot.Name,
+			},
+			RepositoryRef: meta.NamespacedObjectReference{
+				Name:      repository.Name,
+				Namespace: repository.Namespace,
+			},
+		},
+	}
+
+	client := env.FakeKubeClient(
+		WithObjets(sync, snapshot, repository),
+		WithAddToScheme(ocmv1.AddToScheme),
+		WithAddToScheme(mpasv1alpha1.AddToScheme),
+	)
+	m := &mockGit{}
+
+	gsr := &SyncReconciler{
+		Client:   client,
+		Scheme:   env.scheme,
+		Git:      m,
+		Provider: fakes.NewProvider(),
+		EventRecorder: &record.FakeRecorder{
+			Events: make(chan string, 32),
+		},
+	}
+
+	_, err := gsr.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: sync.Namespace,
+			Name:      sync.Name,
+		},
+	})
+	require.Error(t, err)
+
+	require.NoError(t, client.Get(context.Background(), types.NamespacedName{
+		Namespace: sync.Namespace,
+		Name:      sync.Name,
+	}, sync))
+	assert.Equal(t, v1alpha1.AccessDeniedReason, conditions.GetReason(sync, meta.ReadyCondition))
+	assert.False(t, m.called)
 }
@@ -4,21 +4,55 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
 	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
 	"github.com/fluxcd/pkg/runtime/patch"
 	rreconcile "github.com/fluxcd/pkg/runtime/reconcile"
 	"github.com/open-component-model/ocm-controller/pkg/status"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	kuberecorder "k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	deliveryv1alpha1 "github.com/open-component-model/git-controller/apis/delivery/v1alpha1"
 	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+	"github.com/open-component-model/git-controller/pkg/deploykey"
+	"github.com/open-component-model/git-controller/pkg/event"
+	"github.com/open-component-model/git-controller/pkg/predicates"
 	"github.com/open-component-model/git-controller/pkg/providers"
+	"github.com/open-component-model/git-controller/pkg/tracing"
+)
+
+const (
+	// SecretIndexKey indexes Repositories by the names of the Secrets they reference. The index is registered by
+	// SetupSecretIndex.
+	SecretIndexKey = ".metadata.secrets"
+
+	// webhookTokenKey is the key of the webhook's signing secret in the referenced Secret.
+	webhookTokenKey = "token"
+
+	// Keys of the deploy key Secret, matching what Flux expects.
+	deployKeyIdentityKey   = "identity"
+	deployKeyPublicKey     = "identity.pub"
+	deployKeyKnownHostsKey = "known_hosts"
 )
 
 // RepositoryReconciler reconciles a Repository object.
@@ -27,15 +61,30 @@ type RepositoryReconciler struct {
 	kuberecorder.EventRecorder
 	Scheme   *runtime.Scheme
 	Provider providers.Provider
+	// KnownHosts returns the known_hosts entry of the SSH server of a clone URL. Defaults to scanning the server.
+	KnownHosts func(ctx context.Context, sshURL string) ([]byte, error)
+	// MaxConcurrentReconciles is the number of Repositories reconciled in parallel.
+	MaxConcurrentReconciles int
 }
 
 //+kubebuilder:rbac:groups=mpas.ocm.software,resources=repositories,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=mpas.ocm.software,resources=repositories/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=mpas.ocm.software,resources=repositories/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=delivery.ocm.software,resources=snapshots,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *RepositoryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "Repository.Reconcile",
+		attribute.String("repository.namespace", req.Namespace),
+		attribute.String("repository.name", req.Name),
+	)
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	obj := &mpasv1alpha1.Repository{}
 
 	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
@@ -46,6 +95,18 @@ func (r *RepositoryReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, fmt.Errorf("failed to get component object: %w", err)
 	}
 
+	if !obj.GetDeletionTimestamp().IsZero() {
+		if err := r.reconcileDelete(ctx, obj); err != nil {
+			if retryAfter, ok := providers.RetryAfter(err); ok {
+				return ctrl.Result{RequeueAfter: retryAfter}, nil
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
 	patchHelper := patch.NewSerialPatcher(obj, r.Client)
 
 	// Always attempt to patch the object and status after each reconciliation.
@@ -65,21 +126,138 @@ func (r *RepositoryReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	// Should only be deleted on a success.
 	rreconcile.ProgressiveStatus(false, obj, meta.ProgressingReason, "reconciliation in progress for resource: %s", obj.Name)
 
+	// The finalizer is added as part of the deferred patch.
+	controllerutil.AddFinalizer(obj, mpasv1alpha1.RepositoryFinalizer)
+
 	if err := r.reconcile(ctx, obj); err != nil {
+		// Retrying before the provider's quota resets would only fail again.
+		if retryAfter, ok := providers.RetryAfter(err); ok {
+			return ctrl.Result{RequeueAfter: retryAfter}, nil
+		}
+
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: obj.GetRequeueAfter()}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *RepositoryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := SetupSecretIndex(mgr); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&mpasv1alpha1.Repository{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		For(&mpasv1alpha1.Repository{}, builder.WithPredicates(
+			predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}),
+		)).
+		Watches(
+			&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.findRepositoriesForSecret),
+			builder.WithPredicates(predicates.SecretDataChangedPredicate{}),
+		).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Complete(r)
 }
 
+var (
+	secretIndexMu sync.Mutex
+	secretIndexed = map[client.FieldIndexer]bool{}
+)
+
+// SetupSecretIndex registers the SecretIndexKey index with the Manager. Every controller looking up Repositories
+// through the index calls it, so the index is only registered once per Manager.
+func SetupSecretIndex(mgr ctrl.Manager) error {
+	secretIndexMu.Lock()
+	defer secretIndexMu.Unlock()
+
+	indexer := mgr.GetFieldIndexer()
+	if secretIndexed[indexer] {
+		return nil
+	}
+
+	if err := indexer.IndexField(
+		context.Background(),
+		&mpasv1alpha1.Repository{},
+		SecretIndexKey,
+		IndexSecrets,
+	); err != nil {
+		return fmt.Errorf("failed to set index field '%s': %w", SecretIndexKey, err)
+	}
+
+	secretIndexed[indexer] = true
+
+	return nil
+}
+
+// IndexSecrets returns the names of the Secrets referenced by a Repository.
+func IndexSecrets(obj client.Object) []string {
+	repository, ok := obj.(*mpasv1alpha1.Repository)
+	if !ok {
+		return nil
+	}
+
+	return repository.GetSecretNames()
+}
+
+// findRepositoriesForSecret enqueues every Repository referencing the Secret so that rotated credentials are
+// picked up without changing the Repository.
+func (r *RepositoryReconciler) findRepositoriesForSecret(secret client.Object) []reconcile.Request {
+	repositories := &mpasv1alpha1.RepositoryList{}
+	if err := r.List(context.Background(), repositories,
+		client.InNamespace(secret.GetNamespace()),
+		client.MatchingFields{SecretIndexKey: secret.GetName()},
+	); err != nil {
+		log.FromContext(context.Background()).Error(err, "failed to list repositories referencing secret",
+			"secret", client.ObjectKeyFromObject(secret))
+
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(repositories.Items))
+	for _, repository := range repositories.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      repository.Name,
+				Namespace: repository.Namespace,
+			},
+		})
+	}
+
+	return requests
+}
+
 func (r *RepositoryReconciler) reconcile(ctx context.Context, obj *mpasv1alpha1.Repository) error {
+	// The repository only has to be created for a new generation or if the last attempt failed. Otherwise,
+	// the periodic reconciliation only checks for drift.
+	if obj.Generation != obj.Status.ObservedGeneration || !conditions.IsTrue(obj, meta.ReadyCondition) {
+		if err := r.reconcileRepository(ctx, obj); err != nil {
+			return err
+		}
+	}
+
+	if err := r.reconcileDrift(ctx, obj); err != nil {
+		return err
+	}
+
+	if err := r.reconcilePermissions(ctx, obj); err != nil {
+		return err
+	}
+
+	if err := r.reconcileWebhooks(ctx, obj); err != nil {
+		return err
+	}
+
+	if err := r.reconcileDeployKey(ctx, obj); err != nil {
+		return err
+	}
+
+	event.MarkReady(r.EventRecorder, obj, "Successful reconciliation")
+
+	return nil
+}
+
+func (r *RepositoryReconciler) reconcileRepository(ctx context.Context, obj *mpasv1alpha1.Repository) error {
 	if obj.Generation != obj.Status.ObservedGeneration {
 		rreconcile.ProgressiveStatus(
 			false,
@@ -93,30 +271,528 @@ func (r *RepositoryReconciler) reconcile(ctx context.Context, obj *mpasv1alpha1.
 
 	rreconcile.ProgressiveStatus(false, obj, meta.ProgressingReason, "creating repository: %s", obj.Name)
 
-	if err := r.Provider.CreateRepository(ctx, *obj); err != nil {
+	info, err := r.Provider.CreateRepository(ctx, *obj)
+	if err != nil {
 		err := fmt.Errorf("failed to create repository: %w", err)
 		status.MarkNotReady(r.EventRecorder, obj, mpasv1alpha1.RepositoryCreateFailedReason, err.Error())
 
 		return err
 	}
 
+	setRepositoryInfo(obj, info)
+
+	// Metadata is applied for every new generation, afterwards drift detection takes care of outside changes.
+	if metadata := providers.DesiredMetadata(*obj); !metadata.Empty() {
+		if err := r.Provider.UpdateRepositorySettings(ctx, *obj, metadata); err != nil && !errors.Is(err, providers.ErrNotSupported) {
+			err := fmt.Errorf("failed to update repository metadata: %w", err)
+			status.MarkNotReady(r.EventRecorder, obj, mpasv1alpha1.RepositoryMetadataUpdateFailedReason, err.Error())
+
+			return err
+		}
+	}
+
 	rreconcile.ProgressiveStatus(false, obj, meta.ProgressingReason, "setting up branch protection rules: %s", obj.Name)
 
-	if err := r.Provider.CreateBranchProtection(ctx, *obj); err != nil {
-		if errors.Is(err, providers.ErrNotSupported) {
-			status.MarkReady(r.EventRecorder, obj, "Successful reconciliation")
+	err = r.Provider.CreateBranchProtection(ctx, *obj)
+
+	var unsupported *providers.UnsupportedSettingsError
+
+	switch {
+	case err == nil:
+		conditions.Delete(obj, mpasv1alpha1.BranchProtectionUnsupportedCondition)
+	case errors.As(err, &unsupported):
+		conditions.MarkTrue(
+			obj,
+			mpasv1alpha1.BranchProtectionUnsupportedCondition,
+			mpasv1alpha1.UnsupportedSettingsReason,
+			"branch protection settings not supported by provider: %s",
+			strings.Join(unsupported.Settings, ", "),
+		)
+	case errors.Is(err, providers.ErrNotSupported):
+		// Without explicit rules there is nothing to report, the default protection is simply skipped.
+		if len(obj.Spec.BranchProtection) == 0 {
+			conditions.Delete(obj, mpasv1alpha1.BranchProtectionUnsupportedCondition)
 
-			// ignore and return without branch protection rules.
 			return nil
 		}
 
+		conditions.MarkTrue(
+			obj,
+			mpasv1alpha1.BranchProtectionUnsupportedCondition,
+			mpasv1alpha1.UnsupportedSettingsReason,
+			"branch protection is not supported by provider '%s'",
+			obj.Spec.Provider,
+		)
+	default:
 		err := fmt.Errorf("failed to update branch protection rules: %w", err)
 		status.MarkNotReady(r.EventRecorder, obj, mpasv1alpha1.UpdatingBranchProtectionFailedReason, err.Error())
 
 		return err
 	}
 
-	status.MarkReady(r.EventRecorder, obj, "Successful reconciliation")
+	return nil
+}
+
+// setRepositoryInfo records the remote repository's details in the status. The origin is only set once, because
+// every later reconciliation finds the repository already existing.
+func setRepositoryInfo(obj *mpasv1alpha1.Repository, info providers.RepositoryInfo) {
+	obj.Status.ID = info.ID
+	obj.Status.HTTPSURL = info.HTTPSURL
+	obj.Status.SSHURL = info.SSHURL
+	obj.Status.WebURL = info.WebURL
+	obj.Status.DefaultBranch = info.DefaultBranch
+	obj.Status.Visibility = info.Visibility
+
+	if obj.Status.Origin != "" {
+		return
+	}
+
+	obj.Status.Origin = mpasv1alpha1.RepositoryOriginAdopted
+	if info.Created {
+		obj.Status.Origin = mpasv1alpha1.RepositoryOriginCreated
+	}
+}
+
+// reconcileDrift compares the settings of the remote repository with the spec. Depending on the drift policy,
+// differences are either reported in the Drifted condition or corrected.
+func (r *RepositoryReconciler) reconcileDrift(ctx context.Context, obj *mpasv1alpha1.Repository) error {
+	rreconcile.ProgressiveStatus(false, obj, meta.ProgressingReason, "checking repository settings for drift: %s", obj.Name)
+
+	actual, err := r.Provider.GetRepositorySettings(ctx, *obj)
+	if err != nil {
+		if errors.Is(err, providers.ErrNotSupported) {
+			conditions.Delete(obj, mpasv1alpha1.DriftedCondition)
+
+			return nil
+		}
+
+		err := fmt.Errorf("failed to get repository settings: %w", err)
+		status.MarkNotReady(r.EventRecorder, obj, mpasv1alpha1.DriftCheckFailedReason, err.Error())
+
+		return err
+	}
+
+	drift, correction := providers.Drift(providers.DesiredSettings(*obj), actual)
+	setObservedSettings(obj, actual)
+
+	if len(drift) == 0 {
+		conditions.Delete(obj, mpasv1alpha1.DriftedCondition)
+
+		return nil
+	}
+
+	msg := fmt.Sprintf("repository settings drifted: %s", strings.Join(drift, ", "))
+
+	if obj.Spec.DriftPolicy != mpasv1alpha1.DriftPolicyCorrect {
+		// The same drift is found on every interval until someone fixes it, so it's only announced once and
+		// afterwards kept in the condition.
+		reported := conditions.IsTrue(obj, mpasv1alpha1.DriftedCondition) &&
+			conditions.GetMessage(obj, mpasv1alpha1.DriftedCondition) == msg
+
+		conditions.MarkTrue(obj, mpasv1alpha1.DriftedCondition, mpasv1alpha1.DriftDetectedReason, msg)
+
+		if !reported {
+			event.New(r.EventRecorder, obj, eventv1.EventSeverityError, msg, nil)
+		}
+
+		return nil
+	}
+
+	if err := r.correctDrift(ctx, obj, correction); err != nil {
+		err := fmt.Errorf("failed to correct drifted repository settings: %w", err)
+		status.MarkNotReady(r.EventRecorder, obj, mpasv1alpha1.DriftCheckFailedReason, err.Error())
+
+		return err
+	}
+
+	setObservedSettings(obj, correction)
+	conditions.Delete(obj, mpasv1alpha1.DriftedCondition)
+	event.New(r.EventRecorder, obj, eventv1.EventSeverityInfo, "corrected "+msg, nil)
 
 	return nil
 }
+
+// reconcilePermissions grants the declared users and teams access to the repository. This runs on every
+// reconciliation so access changed outside the controller is restored.
+func (r *RepositoryReconciler) reconcilePermissions(ctx context.Context, obj *mpasv1alpha1.Repository) error {
+	if obj.Spec.Permissions == nil {
+		return nil
+	}
+
+	rreconcile.ProgressiveStatus(false, obj, meta.ProgressingReason, "updating repository permissions: %s", obj.Name)
+
+	if err := r.Provider.ReconcilePermissions(ctx, *obj); err != nil {
+		if errors.Is(err, providers.ErrNotSupported) {
+			log.FromContext(ctx).V(deliveryv1alpha1.LevelDebug).Info("provider does not support permissions", "provider", obj.Spec.Provider)
+
+			return nil
+		}
+
+		err := fmt.Errorf("failed to update repository permissions: %w", err)
+		status.MarkNotReady(r.EventRecorder, obj, mpasv1alpha1.PermissionsUpdateFailedReason, err.Error())
+
+		return err
+	}
+
+	return nil
+}
+
+// reconcileWebhooks keeps the webhooks of the remote repository in sync with the spec. The managed URLs are
+// recorded in the status, so webhooks removed from the spec can be deleted.
+func (r *RepositoryReconciler) reconcileWebhooks(ctx context.Context, obj *mpasv1alpha1.Repository) error {
+	if len(obj.Spec.Webhooks) == 0 && len(obj.Status.Webhooks) == 0 {
+		return nil
+	}
+
+	rreconcile.ProgressiveStatus(false, obj, meta.ProgressingReason, "updating repository webhooks: %s", obj.Name)
+
+	hooks, err := r.webhooks(ctx, obj)
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, obj, mpasv1alpha1.WebhooksUpdateFailedReason, err.Error())
+
+		return err
+	}
+
+	if err := r.Provider.ReconcileWebhooks(ctx, *obj, hooks); err != nil {
+		if errors.Is(err, providers.ErrNotSupported) {
+			log.FromContext(ctx).V(deliveryv1alpha1.LevelDebug).Info("provider does not support webhooks", "provider", obj.Spec.Provider)
+
+			return nil
+		}
+
+		err := fmt.Errorf("failed to update repository webhooks: %w", err)
+		status.MarkNotReady(r.EventRecorder, obj, mpasv1alpha1.WebhooksUpdateFailedReason, err.Error())
+
+		return err
+	}
+
+	obj.Status.Webhooks = nil
+	for _, hook := range hooks {
+		obj.Status.Webhooks = append(obj.Status.Webhooks, hook.URL)
+	}
+
+	return nil
+}
+
+// webhooks resolves the signing secrets of the webhooks in the spec.
+func (r *RepositoryReconciler) webhooks(ctx context.Context, obj *mpasv1alpha1.Repository) ([]providers.Webhook, error) {
+	hooks := make([]providers.Webhook, 0, len(obj.Spec.Webhooks))
+
+	for _, hook := range obj.Spec.Webhooks {
+		webhook := providers.Webhook{
+			URL:         hook.URL,
+			Events:      hook.Events,
+			ContentType: hook.ContentType,
+		}
+
+		if hook.SecretRef != nil {
+			secret := &corev1.Secret{}
+			if err := r.Get(ctx, types.NamespacedName{
+				Name:      hook.SecretRef.Name,
+				Namespace: obj.Namespace,
+			}, secret); err != nil {
+				return nil, fmt.Errorf("failed to get webhook secret: %w", err)
+			}
+
+			token, ok := secret.Data[webhookTokenKey]
+			if !ok {
+				return nil, fmt.Errorf("key '%s' not found in webhook secret '%s'", webhookTokenKey, hook.SecretRef.Name)
+			}
+
+			webhook.Secret = string(token)
+		}
+
+		hooks = append(hooks, webhook)
+	}
+
+	return hooks, nil
+}
+
+// reconcileDeployKey generates and registers a deploy key if there is none yet, its settings changed or it is due
+// for rotation. The previous key is removed once the new one is stored.
+func (r *RepositoryReconciler) reconcileDeployKey(ctx context.Context, obj *mpasv1alpha1.Repository) error {
+	if err := r.removeStaleDeployKeys(ctx, obj); err != nil {
+		err := fmt.Errorf("failed to remove previous deploy key: %w", err)
+		status.MarkNotReady(r.EventRecorder, obj, mpasv1alpha1.DeployKeyFailedReason, err.Error())
+
+		return err
+	}
+
+	if obj.Spec.DeployKey == nil {
+		if obj.Status.DeployKey == nil {
+			return nil
+		}
+
+		if err := r.removeDeployKey(ctx, obj, *obj.Status.DeployKey); err != nil {
+			err := fmt.Errorf("failed to remove deploy key: %w", err)
+			status.MarkNotReady(r.EventRecorder, obj, mpasv1alpha1.DeployKeyFailedReason, err.Error())
+
+			return err
+		}
+
+		obj.Status.DeployKey = nil
+
+		return nil
+	}
+
+	due, err := r.deployKeyDue(ctx, obj)
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, obj, mpasv1alpha1.DeployKeyFailedReason, err.Error())
+
+		return err
+	}
+
+	if !due {
+		return nil
+	}
+
+	rreconcile.ProgressiveStatus(false, obj, meta.ProgressingReason, "generating deploy key: %s", obj.Name)
+
+	if err := r.rotateDeployKey(ctx, obj); err != nil {
+		err := fmt.Errorf("failed to rotate deploy key: %w", err)
+		status.MarkNotReady(r.EventRecorder, obj, mpasv1alpha1.DeployKeyFailedReason, err.Error())
+
+		return err
+	}
+
+	event.New(r.EventRecorder, obj, eventv1.EventSeverityInfo, fmt.Sprintf("generated deploy key %s", obj.Status.DeployKey.Fingerprint), nil)
+
+	return nil
+}
+
+// deployKeyDue returns whether a new deploy key has to be generated.
+func (r *RepositoryReconciler) deployKeyDue(ctx context.Context, obj *mpasv1alpha1.Repository) (bool, error) {
+	spec, current := obj.Spec.DeployKey, obj.Status.DeployKey
+
+	if current == nil || current.SecretName != spec.SecretName || current.ReadOnly != spec.ReadOnly {
+		return true, nil
+	}
+
+	if spec.RotationInterval != nil && !time.Now().Before(current.GeneratedAt.Add(spec.RotationInterval.Duration)) {
+		return true, nil
+	}
+
+	// Recreate the key if its Secret got lost, there is no other copy of the private key.
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Name:      spec.SecretName,
+		Namespace: obj.Namespace,
+	}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+
+		return false, fmt.Errorf("failed to get deploy key secret: %w", err)
+	}
+
+	return false, nil
+}
+
+func (r *RepositoryReconciler) rotateDeployKey(ctx context.Context, obj *mpasv1alpha1.Repository) error {
+	spec := obj.Spec.DeployKey
+
+	if obj.Status.SSHURL == "" {
+		return fmt.Errorf("repository has no ssh url")
+	}
+
+	knownHostsFn := r.KnownHosts
+	if knownHostsFn == nil {
+		knownHostsFn = deploykey.KnownHosts
+	}
+
+	knownHosts, err := knownHostsFn(ctx, obj.Status.SSHURL)
+	if err != nil {
+		return fmt.Errorf("failed to get known hosts: %w", err)
+	}
+
+	title := fmt.Sprintf("git-controller %s/%s", obj.Namespace, obj.Name)
+
+	pair, err := deploykey.Generate(title)
+	if err != nil {
+		return err
+	}
+
+	id, err := r.Provider.AddDeployKey(ctx, *obj, providers.DeployKey{
+		Title:     title,
+		PublicKey: pair.PublicKey,
+		ReadOnly:  spec.ReadOnly,
+	})
+	if err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      spec.SecretName,
+			Namespace: obj.Namespace,
+		},
+	}
+
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		secret.Data = map[string][]byte{
+			deployKeyIdentityKey:   pair.PrivateKey,
+			deployKeyPublicKey:     pair.PublicKey,
+			deployKeyKnownHostsKey: knownHosts,
+		}
+
+		return controllerutil.SetControllerReference(obj, secret, r.Scheme)
+	}); err != nil {
+		// Without the private key the new deploy key is useless.
+		_ = r.Provider.DeleteDeployKey(ctx, *obj, id)
+
+		return fmt.Errorf("failed to write deploy key secret: %w", err)
+	}
+
+	// The new key is recorded before the previous one is removed, so it isn't lost if the removal fails.
+	if obj.Status.DeployKey != nil {
+		previous := *obj.Status.DeployKey
+		// The Secret now holds the new key.
+		if previous.SecretName == spec.SecretName {
+			previous.SecretName = ""
+		}
+
+		obj.Status.StaleDeployKeys = append(obj.Status.StaleDeployKeys, previous)
+	}
+
+	obj.Status.DeployKey = &mpasv1alpha1.DeployKeyStatus{
+		ID:          id,
+		Fingerprint: pair.Fingerprint,
+		SecretName:  spec.SecretName,
+		ReadOnly:    spec.ReadOnly,
+		GeneratedAt: metav1.Now(),
+	}
+
+	if err := r.removeStaleDeployKeys(ctx, obj); err != nil {
+		return fmt.Errorf("failed to remove previous deploy key: %w", err)
+	}
+
+	return nil
+}
+
+// removeStaleDeployKeys removes the replaced deploy keys. Keys which couldn't be removed are kept in the status to
+// be retried.
+func (r *RepositoryReconciler) removeStaleDeployKeys(ctx context.Context, obj *mpasv1alpha1.Repository) error {
+	var (
+		remaining []mpasv1alpha1.DeployKeyStatus
+		errs      []error
+	)
+
+	for _, key := range obj.Status.StaleDeployKeys {
+		if err := r.removeDeployKey(ctx, obj, key); err != nil {
+			remaining = append(remaining, key)
+			errs = append(errs, err)
+		}
+	}
+
+	obj.Status.StaleDeployKeys = remaining
+
+	return errors.Join(errs...)
+}
+
+// removeDeployKey deletes a deploy key from the remote repository and its Secret, if it has a name.
+func (r *RepositoryReconciler) removeDeployKey(ctx context.Context, obj *mpasv1alpha1.Repository, key mpasv1alpha1.DeployKeyStatus) error {
+	if err := r.Provider.DeleteDeployKey(ctx, *obj, key.ID); err != nil && !errors.Is(err, providers.ErrNotSupported) {
+		return err
+	}
+
+	if key.SecretName == "" {
+		return nil
+	}
+
+	if err := r.Delete(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      key.SecretName,
+			Namespace: obj.Namespace,
+		},
+	}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete deploy key secret: %w", err)
+	}
+
+	return nil
+}
+
+// setObservedSettings updates the status with the known settings of the remote repository.
+func setObservedSettings(obj *mpasv1alpha1.Repository, settings providers.RepositorySettings) {
+	if settings.Visibility != "" {
+		obj.Status.Visibility = settings.Visibility
+	}
+
+	if settings.DefaultBranch != "" {
+		obj.Status.DefaultBranch = settings.DefaultBranch
+	}
+}
+
+func (r *RepositoryReconciler) correctDrift(ctx context.Context, obj *mpasv1alpha1.Repository, correction providers.RepositorySettings) error {
+	update := correction
+	update.BranchProtection = nil
+
+	if !update.Empty() {
+		if err := r.Provider.UpdateRepositorySettings(ctx, *obj, update); err != nil {
+			return err
+		}
+	}
+
+	if correction.BranchProtection != nil {
+		var unsupported *providers.UnsupportedSettingsError
+		if err := r.Provider.CreateBranchProtection(ctx, *obj); err != nil && !errors.As(err, &unsupported) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reconcileDelete applies the deletion policy of the Repository and removes the finalizer once done.
+func (r *RepositoryReconciler) reconcileDelete(ctx context.Context, obj *mpasv1alpha1.Repository) (err error) {
+	if !controllerutil.ContainsFinalizer(obj, mpasv1alpha1.RepositoryFinalizer) {
+		return nil
+	}
+
+	logger := log.FromContext(ctx)
+	patchHelper := patch.NewSerialPatcher(obj, r.Client)
+
+	switch obj.Spec.DeletionPolicy {
+	case mpasv1alpha1.DeletionPolicyArchive:
+		logger.Info("archiving remote repository", "repository", obj.GetRepositoryURL())
+
+		err = r.Provider.ArchiveRepository(ctx, *obj)
+	case mpasv1alpha1.DeletionPolicyDelete:
+		if !obj.IsDeleteConfirmed() {
+			msg := fmt.Sprintf(
+				"deletion of the remote repository must be confirmed by setting the '%s' annotation to 'true'",
+				mpasv1alpha1.DeleteConfirmationAnnotation,
+			)
+			status.MarkNotReady(r.EventRecorder, obj, mpasv1alpha1.DeletionNotConfirmedReason, msg)
+
+			// Annotating the object will trigger a new reconciliation.
+			return patchHelper.Patch(ctx, obj)
+		}
+
+		logger.Info("deleting remote repository", "repository", obj.GetRepositoryURL())
+
+		err = r.Provider.DeleteRepository(ctx, *obj)
+	default:
+		logger.Info("orphaning remote repository", "repository", obj.GetRepositoryURL())
+	}
+
+	if errors.Is(err, providers.ErrNotSupported) {
+		logger.Info("provider does not support the deletion policy, orphaning remote repository", "policy", obj.Spec.DeletionPolicy)
+
+		err = nil
+	}
+
+	if err != nil {
+		err = fmt.Errorf("failed to apply deletion policy '%s': %w", obj.Spec.DeletionPolicy, err)
+		status.MarkNotReady(r.EventRecorder, obj, mpasv1alpha1.RepositoryDeleteFailedReason, err.Error())
+
+		if perr := patchHelper.Patch(ctx, obj); perr != nil {
+			err = errors.Join(err, perr)
+		}
+
+		return err
+	}
+
+	controllerutil.RemoveFinalizer(obj, mpasv1alpha1.RepositoryFinalizer)
+
+	return patchHelper.Patch(ctx, obj)
+}
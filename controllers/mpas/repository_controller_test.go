@@ -2,21 +2,28 @@ package mpas
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/fluxcd/pkg/apis/meta"
 	"github.com/fluxcd/pkg/runtime/conditions"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	ocmv1 "github.com/open-component-model/ocm-controller/api/v1alpha1"
 
 	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+	"github.com/open-component-model/git-controller/pkg/providers"
 	"github.com/open-component-model/git-controller/pkg/providers/fakes"
 )
 
@@ -63,3 +70,873 @@ func TestRepositoryReconciler(t *testing.T) {
 
 	assert.True(t, conditions.IsTrue(repository, meta.ReadyCondition))
 }
+
+func TestRepositoryReconcilerSetsRepositoryInfo(t *testing.T) {
+	repository := DefaultRepository.DeepCopy()
+
+	client := env.FakeKubeClient(WithAddToScheme(mpasv1alpha1.AddToScheme), WithObjets(repository))
+	fakeProvider := fakes.NewProvider()
+	fakeProvider.RepositoryInfo = providers.RepositoryInfo{
+		ID:            "1234",
+		HTTPSURL:      "https://github.com/e2e-tester/test-component.git",
+		SSHURL:        "ssh://git@github.com/e2e-tester/test-component.git",
+		WebURL:        "https://github.com/e2e-tester/test-component",
+		DefaultBranch: "main",
+		Visibility:    "public",
+		Created:       true,
+	}
+	controller := &RepositoryReconciler{
+		Client:   client,
+		Scheme:   env.scheme,
+		Provider: fakeProvider,
+		EventRecorder: &record.FakeRecorder{
+			Events: make(chan string, 32),
+		},
+	}
+
+	_, err := controller.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: repository.Namespace,
+			Name:      repository.Name,
+		},
+	})
+	require.NoError(t, err)
+
+	err = client.Get(context.Background(), types.NamespacedName{
+		Namespace: repository.Namespace,
+		Name:      repository.Name,
+	}, repository)
+	require.NoError(t, err)
+
+	assert.Equal(t, mpasv1alpha1.RepositoryStatus{
+		ObservedGeneration: repository.Status.ObservedGeneration,
+		Conditions:         repository.Status.Conditions,
+		ID:                 "1234",
+		HTTPSURL:           "https://github.com/e2e-tester/test-component.git",
+		SSHURL:             "ssh://git@github.com/e2e-tester/test-component.git",
+		WebURL:             "https://github.com/e2e-tester/test-component",
+		DefaultBranch:      "main",
+		Visibility:         "public",
+		Origin:             mpasv1alpha1.RepositoryOriginCreated,
+	}, repository.Status)
+
+	// A later reconciliation finds the existing repository, but it stays recorded as created.
+	fakeProvider.RepositoryInfo.Created = false
+	repository.Generation++
+	require.NoError(t, client.Update(context.Background(), repository))
+
+	_, err = controller.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: repository.Namespace,
+			Name:      repository.Name,
+		},
+	})
+	require.NoError(t, err)
+
+	err = client.Get(context.Background(), types.NamespacedName{
+		Namespace: repository.Namespace,
+		Name:      repository.Name,
+	}, repository)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, fakeProvider.CreateRepositoryCallCount)
+	assert.Equal(t, mpasv1alpha1.RepositoryOriginCreated, repository.Status.Origin)
+}
+
+func TestRepositoryReconcilerAddsFinalizer(t *testing.T) {
+	repository := DefaultRepository.DeepCopy()
+
+	client := env.FakeKubeClient(WithAddToScheme(mpasv1alpha1.AddToScheme), WithObjets(repository))
+	controller := &RepositoryReconciler{
+		Client:   client,
+		Scheme:   env.scheme,
+		Provider: fakes.NewProvider(),
+		EventRecorder: &record.FakeRecorder{
+			Events: make(chan string, 32),
+		},
+	}
+
+	_, err := controller.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: repository.Namespace,
+			Name:      repository.Name,
+		},
+	})
+	require.NoError(t, err)
+
+	err = client.Get(context.Background(), types.NamespacedName{
+		Namespace: repository.Namespace,
+		Name:      repository.Name,
+	}, repository)
+	require.NoError(t, err)
+
+	assert.Contains(t, repository.Finalizers, mpasv1alpha1.RepositoryFinalizer)
+}
+
+func TestRepositoryReconcilerDeletionPolicy(t *testing.T) {
+	testCases := []struct {
+		name             string
+		policy           mpasv1alpha1.DeletionPolicy
+		annotations      map[string]string
+		archiveCount     int
+		deleteCount      int
+		finalizerRemoved bool
+	}{
+		{
+			name:             "orphan leaves the repository untouched",
+			policy:           mpasv1alpha1.DeletionPolicyOrphan,
+			finalizerRemoved: true,
+		},
+		{
+			name:             "archive archives the repository",
+			policy:           mpasv1alpha1.DeletionPolicyArchive,
+			archiveCount:     1,
+			finalizerRemoved: true,
+		},
+		{
+			name:   "delete requires confirmation",
+			policy: mpasv1alpha1.DeletionPolicyDelete,
+		},
+		{
+			name:   "delete with confirmation deletes the repository",
+			policy: mpasv1alpha1.DeletionPolicyDelete,
+			annotations: map[string]string{
+				mpasv1alpha1.DeleteConfirmationAnnotation: "true",
+			},
+			deleteCount:      1,
+			finalizerRemoved: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			now := metav1.Now()
+			repository := DefaultRepository.DeepCopy()
+			repository.Spec.DeletionPolicy = tc.policy
+			repository.Annotations = tc.annotations
+			repository.Finalizers = []string{mpasv1alpha1.RepositoryFinalizer}
+			repository.DeletionTimestamp = &now
+
+			client := env.FakeKubeClient(WithAddToScheme(mpasv1alpha1.AddToScheme), WithObjets(repository))
+			fakeProvider := fakes.NewProvider()
+			controller := &RepositoryReconciler{
+				Client:   client,
+				Scheme:   env.scheme,
+				Provider: fakeProvider,
+				EventRecorder: &record.FakeRecorder{
+					Events: make(chan string, 32),
+				},
+			}
+
+			_, err := controller.Reconcile(context.Background(), ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: repository.Namespace,
+					Name:      repository.Name,
+				},
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.archiveCount, fakeProvider.ArchiveRepositoryCallCount)
+			assert.Equal(t, tc.deleteCount, fakeProvider.DeleteRepositoryCallCount)
+			assert.Zero(t, fakeProvider.CreateRepositoryCallCount)
+
+			result := &mpasv1alpha1.Repository{}
+			err = client.Get(context.Background(), types.NamespacedName{
+				Namespace: repository.Namespace,
+				Name:      repository.Name,
+			}, result)
+			if tc.finalizerRemoved {
+				if err == nil {
+					assert.NotContains(t, result.Finalizers, mpasv1alpha1.RepositoryFinalizer)
+				} else {
+					assert.True(t, apierrors.IsNotFound(err))
+				}
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Contains(t, result.Finalizers, mpasv1alpha1.RepositoryFinalizer)
+			assert.Equal(t, mpasv1alpha1.DeletionNotConfirmedReason, conditions.GetReason(result, meta.ReadyCondition))
+		})
+	}
+}
+
+func TestRepositoryReconcilerDrift(t *testing.T) {
+	testCases := []struct {
+		name          string
+		policy        mpasv1alpha1.DriftPolicy
+		actual        providers.RepositorySettings
+		wantDrifted   bool
+		wantCorrected bool
+	}{
+		{
+			name:   "no drift",
+			policy: mpasv1alpha1.DriftPolicyReport,
+			actual: providers.RepositorySettings{
+				Visibility: "public",
+			},
+		},
+		{
+			name:   "drift is reported",
+			policy: mpasv1alpha1.DriftPolicyReport,
+			actual: providers.RepositorySettings{
+				Visibility: "private",
+			},
+			wantDrifted: true,
+		},
+		{
+			name:   "drift is corrected",
+			policy: mpasv1alpha1.DriftPolicyCorrect,
+			actual: providers.RepositorySettings{
+				Visibility: "private",
+			},
+			wantCorrected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repository := DefaultRepository.DeepCopy()
+			repository.Spec.DriftPolicy = tc.policy
+			repository.Spec.Interval = metav1.Duration{Duration: time.Minute}
+
+			client := env.FakeKubeClient(WithAddToScheme(mpasv1alpha1.AddToScheme), WithObjets(repository))
+			fakeProvider := fakes.NewProvider()
+			fakeProvider.RepositorySettings = tc.actual
+			fakeProvider.RepositorySettings.BranchProtection = providers.DesiredSettings(*repository).BranchProtection
+			controller := &RepositoryReconciler{
+				Client:   client,
+				Scheme:   env.scheme,
+				Provider: fakeProvider,
+				EventRecorder: &record.FakeRecorder{
+					Events: make(chan string, 32),
+				},
+			}
+
+			result, err := controller.Reconcile(context.Background(), ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: repository.Namespace,
+					Name:      repository.Name,
+				},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, time.Minute, result.RequeueAfter)
+
+			err = client.Get(context.Background(), types.NamespacedName{
+				Namespace: repository.Namespace,
+				Name:      repository.Name,
+			}, repository)
+			require.NoError(t, err)
+
+			assert.True(t, conditions.IsTrue(repository, meta.ReadyCondition))
+			assert.Equal(t, tc.wantDrifted, conditions.IsTrue(repository, mpasv1alpha1.DriftedCondition))
+
+			if tc.wantCorrected {
+				require.Len(t, fakeProvider.UpdateRepositorySettingsCalledWith, 1)
+				assert.Equal(t, "public", fakeProvider.UpdateRepositorySettingsCalledWith[0].Visibility)
+			} else {
+				assert.Empty(t, fakeProvider.UpdateRepositorySettingsCalledWith)
+			}
+		})
+	}
+}
+
+func TestRepositoryReconcilerReportsDriftOnce(t *testing.T) {
+	repository := DefaultRepository.DeepCopy()
+	repository.Spec.DriftPolicy = mpasv1alpha1.DriftPolicyReport
+
+	client := env.FakeKubeClient(WithAddToScheme(mpasv1alpha1.AddToScheme), WithObjets(repository))
+	fakeProvider := fakes.NewProvider()
+	fakeProvider.RepositorySettings = providers.RepositorySettings{
+		Visibility: "private",
+	}
+	recorder := &record.FakeRecorder{
+		Events: make(chan string, 32),
+	}
+	controller := &RepositoryReconciler{
+		Client:        client,
+		Scheme:        env.scheme,
+		Provider:      fakeProvider,
+		EventRecorder: recorder,
+	}
+
+	reconcileAndCountDriftEvents := func() int {
+		_, err := controller.Reconcile(context.Background(), ctrl.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: repository.Namespace,
+				Name:      repository.Name,
+			},
+		})
+		require.NoError(t, err)
+
+		count := 0
+
+		for len(recorder.Events) > 0 {
+			if strings.Contains(<-recorder.Events, "repository settings drifted") {
+				count++
+			}
+		}
+
+		return count
+	}
+
+	assert.Equal(t, 1, reconcileAndCountDriftEvents())
+	assert.Equal(t, 0, reconcileAndCountDriftEvents())
+
+	fakeProvider.RepositorySettings.BranchProtection = map[string]*mpasv1alpha1.BranchProtectionRule{
+		"main": nil,
+	}
+	assert.Equal(t, 1, reconcileAndCountDriftEvents())
+
+	err := client.Get(context.Background(), types.NamespacedName{
+		Namespace: repository.Namespace,
+		Name:      repository.Name,
+	}, repository)
+	require.NoError(t, err)
+	assert.Contains(t, conditions.GetMessage(repository, mpasv1alpha1.DriftedCondition), "branch 'main' is not protected")
+}
+
+func TestRepositoryReconcilerUnsupportedBranchProtection(t *testing.T) {
+	repository := DefaultRepository.DeepCopy()
+	repository.Spec.BranchProtection = []mpasv1alpha1.BranchProtectionRule{
+		{
+			Pattern:              "main",
+			RequireLinearHistory: true,
+		},
+	}
+
+	client := env.FakeKubeClient(WithAddToScheme(mpasv1alpha1.AddToScheme), WithObjets(repository))
+	fakeProvider := fakes.NewProvider()
+	fakeProvider.CreateBranchProtectionErr = &providers.UnsupportedSettingsError{
+		Settings: []string{"requireLinearHistory for 'main'"},
+	}
+	controller := &RepositoryReconciler{
+		Client:   client,
+		Scheme:   env.scheme,
+		Provider: fakeProvider,
+		EventRecorder: &record.FakeRecorder{
+			Events: make(chan string, 32),
+		},
+	}
+
+	_, err := controller.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: repository.Namespace,
+			Name:      repository.Name,
+		},
+	})
+	require.NoError(t, err)
+
+	err = client.Get(context.Background(), types.NamespacedName{
+		Namespace: repository.Namespace,
+		Name:      repository.Name,
+	}, repository)
+	require.NoError(t, err)
+
+	assert.True(t, conditions.IsTrue(repository, meta.ReadyCondition))
+	assert.True(t, conditions.IsTrue(repository, mpasv1alpha1.BranchProtectionUnsupportedCondition))
+	assert.Equal(t,
+		"branch protection settings not supported by provider: requireLinearHistory for 'main'",
+		conditions.GetMessage(repository, mpasv1alpha1.BranchProtectionUnsupportedCondition),
+	)
+}
+
+func TestRepositoryReconcilerPermissions(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions *mpasv1alpha1.Permissions
+		err         error
+		calls       int
+		ready       bool
+	}{
+		{
+			name:  "without permissions the provider isn't called",
+			ready: true,
+		},
+		{
+			name: "permissions are reconciled",
+			permissions: &mpasv1alpha1.Permissions{
+				Users: []mpasv1alpha1.Permission{{Name: "alice", Role: mpasv1alpha1.RoleWrite}},
+				Prune: true,
+			},
+			calls: 1,
+			ready: true,
+		},
+		{
+			name: "unsupported permissions don't fail the reconciliation",
+			permissions: &mpasv1alpha1.Permissions{
+				Users: []mpasv1alpha1.Permission{{Name: "alice", Role: mpasv1alpha1.RoleWrite}},
+			},
+			err:   providers.ErrNotSupported,
+			calls: 1,
+			ready: true,
+		},
+		{
+			name: "failing to update permissions marks the repository not ready",
+			permissions: &mpasv1alpha1.Permissions{
+				Teams: []mpasv1alpha1.Permission{{Name: "developers", Role: mpasv1alpha1.RoleMaintain}},
+			},
+			err:   errors.New("boom"),
+			calls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := DefaultRepository.DeepCopy()
+			repository.Spec.Permissions = tt.permissions
+
+			client := env.FakeKubeClient(WithAddToScheme(mpasv1alpha1.AddToScheme), WithObjets(repository))
+			fakeProvider := fakes.NewProvider()
+			fakeProvider.ReconcilePermissionsErr = tt.err
+			recorder := &record.FakeRecorder{
+				Events: make(chan string, 32),
+			}
+			controller := &RepositoryReconciler{
+				Client:        client,
+				Scheme:        env.scheme,
+				Provider:      fakeProvider,
+				EventRecorder: recorder,
+			}
+
+			_, err := controller.Reconcile(context.Background(), ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: repository.Namespace,
+					Name:      repository.Name,
+				},
+			})
+			if tt.ready {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+
+			err = client.Get(context.Background(), types.NamespacedName{
+				Namespace: repository.Namespace,
+				Name:      repository.Name,
+			}, repository)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.calls, fakeProvider.ReconcilePermissionsCallCount)
+			assert.Equal(t, tt.ready, conditions.IsTrue(repository, meta.ReadyCondition))
+
+			close(recorder.Events)
+			for e := range recorder.Events {
+				assert.NotContains(t, e, "not supported", "unsupported permissions must not be reported on every reconcile")
+			}
+
+			if !tt.ready {
+				assert.Equal(t, mpasv1alpha1.PermissionsUpdateFailedReason, conditions.GetReason(repository, meta.ReadyCondition))
+			}
+		})
+	}
+}
+
+func TestRepositoryReconcilerWebhooks(t *testing.T) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "hook-secret",
+			Namespace: DefaultRepository.Namespace,
+		},
+		Data: map[string][]byte{
+			"token": []byte("hmac-key"),
+		},
+	}
+	repository := DefaultRepository.DeepCopy()
+	repository.Spec.Webhooks = []mpasv1alpha1.Webhook{
+		{
+			URL:         "https://ci.example.com/hook",
+			Events:      []mpasv1alpha1.WebhookEvent{mpasv1alpha1.WebhookEventPush, mpasv1alpha1.WebhookEventPullRequest},
+			ContentType: "json",
+			SecretRef:   &v1.LocalObjectReference{Name: secret.Name},
+		},
+	}
+	repository.Status.Webhooks = []string{"https://old.example.com/hook"}
+
+	client := env.FakeKubeClient(WithAddToScheme(mpasv1alpha1.AddToScheme), WithObjets(repository, secret))
+	fakeProvider := fakes.NewProvider()
+	controller := &RepositoryReconciler{
+		Client:   client,
+		Scheme:   env.scheme,
+		Provider: fakeProvider,
+		EventRecorder: &record.FakeRecorder{
+			Events: make(chan string, 32),
+		},
+	}
+
+	_, err := controller.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: repository.Namespace,
+			Name:      repository.Name,
+		},
+	})
+	require.NoError(t, err)
+
+	err = client.Get(context.Background(), types.NamespacedName{
+		Namespace: repository.Namespace,
+		Name:      repository.Name,
+	}, repository)
+	require.NoError(t, err)
+
+	require.Len(t, fakeProvider.ReconcileWebhooksCalledWith, 1)
+	assert.Equal(t, []providers.Webhook{
+		{
+			URL:         "https://ci.example.com/hook",
+			Events:      []mpasv1alpha1.WebhookEvent{mpasv1alpha1.WebhookEventPush, mpasv1alpha1.WebhookEventPullRequest},
+			ContentType: "json",
+			Secret:      "hmac-key",
+		},
+	}, fakeProvider.ReconcileWebhooksCalledWith[0])
+	assert.Equal(t, []string{"https://ci.example.com/hook"}, repository.Status.Webhooks)
+	assert.True(t, conditions.IsTrue(repository, meta.ReadyCondition))
+}
+
+func TestRepositoryReconcilerWebhookSecretMissing(t *testing.T) {
+	repository := DefaultRepository.DeepCopy()
+	repository.Spec.Webhooks = []mpasv1alpha1.Webhook{
+		{
+			URL:       "https://ci.example.com/hook",
+			SecretRef: &v1.LocalObjectReference{Name: "missing"},
+		},
+	}
+
+	client := env.FakeKubeClient(WithAddToScheme(mpasv1alpha1.AddToScheme), WithObjets(repository))
+	fakeProvider := fakes.NewProvider()
+	controller := &RepositoryReconciler{
+		Client:   client,
+		Scheme:   env.scheme,
+		Provider: fakeProvider,
+		EventRecorder: &record.FakeRecorder{
+			Events: make(chan string, 32),
+		},
+	}
+
+	_, err := controller.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: repository.Namespace,
+			Name:      repository.Name,
+		},
+	})
+	require.Error(t, err)
+
+	err = client.Get(context.Background(), types.NamespacedName{
+		Namespace: repository.Namespace,
+		Name:      repository.Name,
+	}, repository)
+	require.NoError(t, err)
+
+	assert.Empty(t, fakeProvider.ReconcileWebhooksCalledWith)
+	assert.Equal(t, mpasv1alpha1.WebhooksUpdateFailedReason, conditions.GetReason(repository, meta.ReadyCondition))
+}
+
+func TestRepositoryReconcilerDeployKey(t *testing.T) {
+	existingSecret := func() *v1.Secret {
+		return &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "deploy-key",
+				Namespace: DefaultRepository.Namespace,
+			},
+			Data: map[string][]byte{
+				"identity": []byte("old"),
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		deployKey *mpasv1alpha1.DeployKey
+		status    *mpasv1alpha1.DeployKeyStatus
+		objects   []client.Object
+		added     int
+		deleted   []string
+		secret    bool
+	}{
+		{
+			name:      "a new deploy key is generated",
+			deployKey: &mpasv1alpha1.DeployKey{SecretName: "deploy-key", ReadOnly: true},
+			added:     1,
+			secret:    true,
+		},
+		{
+			name:      "a current deploy key is kept",
+			deployKey: &mpasv1alpha1.DeployKey{SecretName: "deploy-key", RotationInterval: &metav1.Duration{Duration: time.Hour}},
+			status: &mpasv1alpha1.DeployKeyStatus{
+				ID:          "old",
+				SecretName:  "deploy-key",
+				GeneratedAt: metav1.Now(),
+			},
+			objects: []client.Object{existingSecret()},
+			secret:  true,
+		},
+		{
+			name:      "a deploy key due for rotation is replaced",
+			deployKey: &mpasv1alpha1.DeployKey{SecretName: "deploy-key", RotationInterval: &metav1.Duration{Duration: time.Hour}},
+			status: &mpasv1alpha1.DeployKeyStatus{
+				ID:          "old",
+				SecretName:  "deploy-key",
+				GeneratedAt: metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+			},
+			objects: []client.Object{existingSecret()},
+			added:   1,
+			deleted: []string{"old"},
+			secret:  true,
+		},
+		{
+			name: "a removed deploy key is deleted",
+			status: &mpasv1alpha1.DeployKeyStatus{
+				ID:          "old",
+				SecretName:  "deploy-key",
+				GeneratedAt: metav1.Now(),
+			},
+			objects: []client.Object{existingSecret()},
+			deleted: []string{"old"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := DefaultRepository.DeepCopy()
+			repository.Spec.DeployKey = tt.deployKey
+			repository.Status.DeployKey = tt.status
+
+			client := env.FakeKubeClient(WithAddToScheme(mpasv1alpha1.AddToScheme), WithObjets(append(tt.objects, repository)...))
+			fakeProvider := fakes.NewProvider()
+			fakeProvider.DeployKeyID = "new"
+			fakeProvider.RepositoryInfo.SSHURL = "git@github.com:test/test.git"
+			controller := &RepositoryReconciler{
+				Client:   client,
+				Scheme:   env.scheme,
+				Provider: fakeProvider,
+				EventRecorder: &record.FakeRecorder{
+					Events: make(chan string, 32),
+				},
+				KnownHosts: func(ctx context.Context, sshURL string) ([]byte, error) {
+					return []byte("github.com ssh-ed25519 AAAA\n"), nil
+				},
+			}
+
+			_, err := controller.Reconcile(context.Background(), ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: repository.Namespace,
+					Name:      repository.Name,
+				},
+			})
+			require.NoError(t, err)
+
+			err = client.Get(context.Background(), types.NamespacedName{
+				Namespace: repository.Namespace,
+				Name:      repository.Name,
+			}, repository)
+			require.NoError(t, err)
+
+			assert.Len(t, fakeProvider.AddDeployKeyCalledWith, tt.added)
+			assert.Equal(t, tt.deleted, fakeProvider.DeleteDeployKeyCalledWith)
+
+			secret := &v1.Secret{}
+			err = client.Get(context.Background(), types.NamespacedName{
+				Namespace: repository.Namespace,
+				Name:      "deploy-key",
+			}, secret)
+
+			if !tt.secret {
+				assert.True(t, apierrors.IsNotFound(err))
+				assert.Nil(t, repository.Status.DeployKey)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, repository.Status.DeployKey)
+
+			if tt.added == 0 {
+				assert.Equal(t, "old", repository.Status.DeployKey.ID)
+				assert.Equal(t, []byte("old"), secret.Data["identity"])
+
+				return
+			}
+
+			assert.Equal(t, tt.deployKey.ReadOnly, fakeProvider.AddDeployKeyCalledWith[0].ReadOnly)
+			assert.Equal(t, "new", repository.Status.DeployKey.ID)
+			assert.Equal(t, fakeProvider.AddDeployKeyCalledWith[0].PublicKey, secret.Data["identity.pub"])
+			assert.Contains(t, string(secret.Data["identity"]), "OPENSSH PRIVATE KEY")
+			assert.Equal(t, []byte("github.com ssh-ed25519 AAAA\n"), secret.Data["known_hosts"])
+			assert.Equal(t, repository.Name, secret.OwnerReferences[0].Name)
+		})
+	}
+}
+
+func TestRepositoryReconcilerRetriesRemovalOfPreviousDeployKey(t *testing.T) {
+	repository := DefaultRepository.DeepCopy()
+	repository.Spec.DeployKey = &mpasv1alpha1.DeployKey{SecretName: "deploy-key"}
+	previous := &mpasv1alpha1.DeployKeyStatus{
+		ID:          "old",
+		SecretName:  "old-deploy-key",
+		GeneratedAt: metav1.Now(),
+	}
+	repository.Status.DeployKey = previous
+
+	client := env.FakeKubeClient(WithAddToScheme(mpasv1alpha1.AddToScheme), WithObjets(repository))
+	fakeProvider := fakes.NewProvider()
+	fakeProvider.DeployKeyID = "new"
+	fakeProvider.DeleteDeployKeyErr = errors.New("service unavailable")
+	fakeProvider.RepositoryInfo.SSHURL = "git@github.com:test/test.git"
+	controller := &RepositoryReconciler{
+		Client:   client,
+		Scheme:   env.scheme,
+		Provider: fakeProvider,
+		EventRecorder: &record.FakeRecorder{
+			Events: make(chan string, 32),
+		},
+		KnownHosts: func(ctx context.Context, sshURL string) ([]byte, error) {
+			return []byte("github.com ssh-ed25519 AAAA\n"), nil
+		},
+	}
+
+	request := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: repository.Namespace,
+			Name:      repository.Name,
+		},
+	}
+
+	_, err := controller.Reconcile(context.Background(), request)
+	require.ErrorContains(t, err, "service unavailable")
+
+	require.NoError(t, client.Get(context.Background(), request.NamespacedName, repository))
+	require.NotNil(t, repository.Status.DeployKey)
+	assert.Equal(t, "new", repository.Status.DeployKey.ID)
+	assert.Equal(t, "deploy-key", repository.Status.DeployKey.SecretName)
+	require.Len(t, repository.Status.StaleDeployKeys, 1)
+	assert.Equal(t, "old", repository.Status.StaleDeployKeys[0].ID)
+	assert.Equal(t, "old-deploy-key", repository.Status.StaleDeployKeys[0].SecretName)
+
+	fakeProvider.DeleteDeployKeyErr = nil
+
+	_, err = controller.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+
+	require.NoError(t, client.Get(context.Background(), request.NamespacedName, repository))
+	assert.Equal(t, "new", repository.Status.DeployKey.ID)
+	assert.Empty(t, repository.Status.StaleDeployKeys)
+	assert.Len(t, fakeProvider.AddDeployKeyCalledWith, 1, "no further key must be added while the previous one is removed")
+	assert.Equal(t, []string{"old", "old"}, fakeProvider.DeleteDeployKeyCalledWith)
+}
+
+func TestRepositoryReconcilerAppliesMetadata(t *testing.T) {
+	disabled := false
+	repository := DefaultRepository.DeepCopy()
+	repository.Spec.Description = "payment service"
+	repository.Spec.Topics = []string{"mpas", "payment"}
+	repository.Spec.Features = &mpasv1alpha1.RepositoryFeatures{
+		Wiki: &disabled,
+	}
+
+	client := env.FakeKubeClient(WithAddToScheme(mpasv1alpha1.AddToScheme), WithObjets(repository))
+	fakeProvider := fakes.NewProvider()
+	controller := &RepositoryReconciler{
+		Client:   client,
+		Scheme:   env.scheme,
+		Provider: fakeProvider,
+		EventRecorder: &record.FakeRecorder{
+			Events: make(chan string, 32),
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := controller.Reconcile(context.Background(), ctrl.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: repository.Namespace,
+				Name:      repository.Name,
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	// The second reconciliation of the same generation only checks for drift.
+	assert.Equal(t, []providers.RepositorySettings{
+		{
+			Description: "payment service",
+			Topics:      []string{"mpas", "payment"},
+			HasWiki:     &disabled,
+		},
+	}, fakeProvider.UpdateRepositorySettingsCalledWith)
+}
+
+func TestRepositoryReconcilerFindsRepositoriesForSecret(t *testing.T) {
+	credentials := DefaultRepository.DeepCopy()
+	credentials.Name = "credentials"
+	credentials.Spec.Credentials.SecretRef.Name = "rotated"
+
+	webhook := DefaultRepository.DeepCopy()
+	webhook.Name = "webhook"
+	webhook.Spec.Webhooks = []mpasv1alpha1.Webhook{{
+		URL:       "https://example.com/hook",
+		SecretRef: &v1.LocalObjectReference{Name: "rotated"},
+	}}
+
+	unrelated := DefaultRepository.DeepCopy()
+	unrelated.Name = "unrelated"
+
+	otherNamespace := credentials.DeepCopy()
+	otherNamespace.Namespace = "other"
+
+	client := env.FakeKubeClient(
+		WithAddToScheme(mpasv1alpha1.AddToScheme),
+		WithObjets(credentials, webhook, unrelated, otherNamespace),
+		WithIndex(&mpasv1alpha1.Repository{}, SecretIndexKey, IndexSecrets),
+	)
+
+	r := &RepositoryReconciler{
+		Client: client,
+	}
+
+	requests := r.findRepositoriesForSecret(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rotated",
+			Namespace: "default",
+		},
+	})
+
+	assert.ElementsMatch(t, []ctrl.Request{
+		{NamespacedName: types.NamespacedName{Name: "credentials", Namespace: "default"}},
+		{NamespacedName: types.NamespacedName{Name: "webhook", Namespace: "default"}},
+	}, requests)
+}
+
+func TestRepositoryReconcilerRequeuesWhenRateLimited(t *testing.T) {
+	repository := DefaultRepository.DeepCopy()
+
+	client := env.FakeKubeClient(WithAddToScheme(mpasv1alpha1.AddToScheme), WithObjets(repository))
+	fakeProvider := fakes.NewProvider()
+	fakeProvider.CreateRepositoryErr = fmt.Errorf("failed to create repository: %w", &providers.RateLimitError{
+		Provider: "github",
+		ResetAt:  time.Now().Add(10 * time.Minute),
+	})
+	controller := &RepositoryReconciler{
+		Client:   client,
+		Scheme:   env.scheme,
+		Provider: fakeProvider,
+		EventRecorder: &record.FakeRecorder{
+			Events: make(chan string, 32),
+		},
+	}
+
+	result, err := controller.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: repository.Namespace,
+			Name:      repository.Name,
+		},
+	})
+	require.NoError(t, err)
+	assert.InDelta(t, (10 * time.Minute).Seconds(), result.RequeueAfter.Seconds(), 5)
+
+	err = client.Get(context.Background(), types.NamespacedName{
+		Namespace: repository.Namespace,
+		Name:      repository.Name,
+	}, repository)
+	require.NoError(t, err)
+
+	assert.True(t, conditions.IsFalse(repository, meta.ReadyCondition))
+	assert.Contains(t, conditions.GetMessage(repository, meta.ReadyCondition), "rate limit exceeded")
+}
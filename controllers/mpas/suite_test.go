@@ -14,8 +14,15 @@ import (
 )
 
 type testEnv struct {
-	scheme *runtime.Scheme
-	obj    []client.Object
+	scheme  *runtime.Scheme
+	obj     []client.Object
+	indexes []index
+}
+
+type index struct {
+	obj     client.Object
+	field   string
+	extract client.IndexerFunc
 }
 
 // FakeKubeClientOption defines options to construct a fake kube client. There are some defaults involved.
@@ -39,12 +46,28 @@ func WithObjets(obj ...client.Object) FakeKubeClientOption {
 	}
 }
 
+// WithIndex provides an option to register a field index with the fake client. Indexes are only used for the
+// client which is created next.
+func WithIndex(obj client.Object, field string, extract client.IndexerFunc) FakeKubeClientOption {
+	return func(testEnv *testEnv) {
+		testEnv.indexes = append(testEnv.indexes, index{obj: obj, field: field, extract: extract})
+	}
+}
+
 // FakeKubeClient creates a fake kube client with some defaults and optional arguments.
 func (t *testEnv) FakeKubeClient(opts ...FakeKubeClientOption) client.Client {
 	for _, o := range opts {
 		o(t)
 	}
-	return fake.NewClientBuilder().WithScheme(t.scheme).WithObjects(t.obj...).Build()
+
+	builder := fake.NewClientBuilder().WithScheme(t.scheme).WithObjects(t.obj...)
+	for _, i := range t.indexes {
+		builder = builder.WithIndex(i.obj, i.field, i.extract)
+	}
+
+	t.indexes = nil
+
+	return builder.Build()
 }
 
 var (
@@ -1,29 +1,47 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/fluxcd/pkg/runtime/events"
+	"github.com/fluxcd/pkg/runtime/leaderelection"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	sourcev1beta2 "github.com/fluxcd/source-controller/api/v1beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	"github.com/open-component-model/ocm-controller/api/v1alpha1"
 	"github.com/open-component-model/ocm-controller/pkg/oci"
 
 	deliveryv1alpha1 "github.com/open-component-model/git-controller/apis/delivery/v1alpha1"
+	deliveryv1beta1 "github.com/open-component-model/git-controller/apis/delivery/v1beta1"
 	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+	mpasv1beta1 "github.com/open-component-model/git-controller/apis/mpas/v1beta1"
 	"github.com/open-component-model/git-controller/controllers/delivery"
 	mpascontrollers "github.com/open-component-model/git-controller/controllers/mpas"
+	"github.com/open-component-model/git-controller/pkg/content"
 	"github.com/open-component-model/git-controller/pkg/gogit"
+	"github.com/open-component-model/git-controller/pkg/metrics"
+	"github.com/open-component-model/git-controller/pkg/providers"
 	"github.com/open-component-model/git-controller/pkg/providers/gitea"
 	"github.com/open-component-model/git-controller/pkg/providers/github"
 	"github.com/open-component-model/git-controller/pkg/providers/gitlab"
+	"github.com/open-component-model/git-controller/pkg/providers/plaingit"
+	"github.com/open-component-model/git-controller/pkg/tracing"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -39,6 +57,10 @@ func init() {
 	utilruntime.Must(v1alpha1.AddToScheme(scheme))
 	utilruntime.Must(deliveryv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(mpasv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(deliveryv1beta1.AddToScheme(scheme))
+	utilruntime.Must(mpasv1beta1.AddToScheme(scheme))
+	utilruntime.Must(sourcev1.AddToScheme(scheme))
+	utilruntime.Must(sourcev1beta2.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
@@ -52,6 +74,12 @@ func main() {
 		ociRegistryAddr           string
 		ociRegistryCertSecretName string
 		ociRegistryNamespace      string
+		enabledProviders          string
+		noCrossNamespaceRefs      bool
+		concurrent                int
+		watchLabelSelector        string
+		enableWebhooks            bool
+		tracingOptions            tracing.Options
 	)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -60,7 +88,26 @@ func main() {
 	flag.StringVar(&ociRegistryCertSecretName, "certificate-secret-name", "ocm-registry-tls-certs", "")
 	flag.StringVar(&ociRegistryNamespace, "oci-registry-namespace", "ocm-system", "The namespace in which the registry is running in.")
 	flag.StringVar(&eventsAddr, "events-addr", "", "The address of the events receiver.")
+	flag.StringVar(
+		&enabledProviders,
+		"providers",
+		strings.Join([]string{github.ProviderType, gitlab.ProviderType, gitea.ProviderType, plaingit.ProviderType}, ","),
+		"Comma separated list of git providers to enable.",
+	)
 
+	flag.BoolVar(&noCrossNamespaceRefs, "no-cross-namespace-refs", false,
+		"When set to true, Syncs can only refer to Repositories in their own namespace.")
+	flag.IntVar(&concurrent, "concurrent", 4,
+		"The number of concurrent reconciles per controller. Earlier releases reconciled one object at a time, set it to 1 to keep that.")
+	flag.StringVar(&watchLabelSelector, "watch-label-selector", "",
+		"Only reconcile Syncs, CommitStatuses and Repositories with matching labels, e.g. 'sharding.ocm.software/shard=shard1'.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"Serve the admission and conversion webhooks of Syncs and Repositories.")
+	flag.StringVar(&tracingOptions.Exporter, "tracing-exporter", tracing.ExporterNone,
+		"Exporter of the OpenTelemetry spans, one of none, otlp or stdout.")
+	flag.StringVar(&tracingOptions.Endpoint, "otlp-endpoint", "",
+		"The host and port of the OTLP HTTP endpoint spans are sent to. Defaults to the OTEL_EXPORTER_OTLP_* environment variables.")
+	flag.BoolVar(&tracingOptions.Insecure, "otlp-insecure", false, "Send spans to the OTLP endpoint without TLS.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -72,6 +119,18 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	watchSelector, err := parseWatchSelector(watchLabelSelector)
+	if err != nil {
+		setupLog.Error(err, "unable to parse watch label selector")
+		os.Exit(1)
+	}
+
+	// Shards must not compete for the same lease, so every selector gets its own leader election ID.
+	leaderElectionID := "76b5aa10.ocm.software"
+	if watchLabelSelector != "" {
+		leaderElectionID = leaderelection.GenerateID(leaderElectionID, watchLabelSelector)
+	}
+
 	const metricsServerPort = 9443
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
@@ -79,7 +138,14 @@ func main() {
 		Port:                   metricsServerPort,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "76b5aa10.ocm.software",
+		LeaderElectionID:       leaderElectionID,
+		NewCache: ctrlcache.BuilderWithOptions(ctrlcache.Options{
+			SelectorsByObject: ctrlcache.SelectorsByObject{
+				&deliveryv1alpha1.Sync{}:         {Label: watchSelector},
+				&deliveryv1alpha1.CommitStatus{}: {Label: watchSelector},
+				&mpasv1alpha1.Repository{}:       {Label: watchSelector},
+			},
+		}),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -92,9 +158,16 @@ func main() {
 		oci.WithCertificateSecret(ociRegistryCertSecretName),
 	)
 	gitClient := gogit.NewGoGit(ctrl.Log, cache)
-	giteaProvider := gitea.NewClient(mgr.GetClient(), nil)
-	gitlabProvider := gitlab.NewClient(mgr.GetClient(), giteaProvider)
-	githubProvider := github.NewClient(mgr.GetClient(), gitlabProvider)
+
+	registry, err := setupProviders(mgr, content.NewLoader(mgr.GetClient(), cache), enabledProviders)
+	if err != nil {
+		setupLog.Error(err, "unable to set up providers")
+		os.Exit(1)
+	}
+
+	provider := providers.NewDispatcher(registry)
+
+	ctrlmetrics.Registry.MustRegister(metrics.NewSyncCollector(mgr.GetClient()))
 
 	var eventsRecorder *events.Recorder
 	if eventsRecorder, err = events.NewRecorder(mgr, ctrl.Log, eventsAddr, controllerName); err != nil {
@@ -103,25 +176,51 @@ func main() {
 	}
 
 	if err = (&delivery.SyncReconciler{
-		EventRecorder: eventsRecorder,
-		Client:        mgr.GetClient(),
-		Scheme:        mgr.GetScheme(),
-		Git:           gitClient,
-		Provider:      githubProvider,
+		EventRecorder:           eventsRecorder,
+		Client:                  mgr.GetClient(),
+		Scheme:                  mgr.GetScheme(),
+		Git:                     gitClient,
+		Provider:                provider,
+		NoCrossNamespaceRefs:    noCrossNamespaceRefs,
+		MaxConcurrentReconciles: concurrent,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Sync")
 		os.Exit(1)
 	}
 
+	if err = (&delivery.CommitStatusReconciler{
+		EventRecorder:           eventsRecorder,
+		Client:                  mgr.GetClient(),
+		Scheme:                  mgr.GetScheme(),
+		Provider:                provider,
+		MaxConcurrentReconciles: concurrent,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CommitStatus")
+		os.Exit(1)
+	}
+
 	if err = (&mpascontrollers.RepositoryReconciler{
-		EventRecorder: eventsRecorder,
-		Client:        mgr.GetClient(),
-		Scheme:        mgr.GetScheme(),
-		Provider:      githubProvider,
+		EventRecorder:           eventsRecorder,
+		Client:                  mgr.GetClient(),
+		Scheme:                  mgr.GetScheme(),
+		Provider:                provider,
+		MaxConcurrentReconciles: concurrent,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Repository")
 		os.Exit(1)
 	}
+
+	if enableWebhooks {
+		if err = (&deliveryv1alpha1.Sync{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Sync")
+			os.Exit(1)
+		}
+
+		if err = (&mpasv1alpha1.Repository{}).SetupWebhookWithManager(mgr, registry.Names()); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Repository")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -133,9 +232,72 @@ func main() {
 		os.Exit(1)
 	}
 
+	ctx := ctrl.SetupSignalHandler()
+
+	shutdownTracing, err := tracing.Setup(ctx, tracingOptions)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+
+	// The signal context is done at this point, flushing the remaining spans needs a fresh one.
+	const tracingShutdownTimeout = 5 * time.Second
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+	defer cancel()
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		setupLog.Error(err, "failed to flush spans")
+	}
+}
+
+// setupProviders registers every enabled provider with a new registry.
+func setupProviders(mgr ctrl.Manager, loader providers.ContentLoader, enabled string) (*providers.Registry, error) {
+	available := map[string]providers.Provider{
+		github.ProviderType:   github.NewClient(mgr.GetClient(), loader),
+		gitlab.ProviderType:   gitlab.NewClient(mgr.GetClient(), loader),
+		gitea.ProviderType:    gitea.NewClient(mgr.GetClient(), loader),
+		plaingit.ProviderType: plaingit.NewClient(mgr.GetClient()),
+	}
+
+	registry := providers.NewRegistry()
+
+	for _, name := range strings.Split(enabled, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		provider, ok := available[name]
+		if !ok {
+			return nil, fmt.Errorf("provider '%s' is not available", name)
+		}
+
+		if err := registry.Register(name, provider); err != nil {
+			return nil, fmt.Errorf("failed to register provider: %w", err)
+		}
+	}
+
+	setupLog.Info("enabled providers", "providers", registry.Names())
+
+	return registry, nil
+}
+
+// parseWatchSelector parses the value of --watch-label-selector. An empty value selects everything.
+func parseWatchSelector(selector string) (labels.Selector, error) {
+	if selector == "" {
+		return labels.Everything(), nil
+	}
+
+	ls, err := metav1.ParseToLabelSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse label selector '%s': %w", selector, err)
+	}
+
+	return metav1.LabelSelectorAsSelector(ls)
 }
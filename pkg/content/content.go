@@ -0,0 +1,328 @@
+package content
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/containers/image/v5/pkg/compression"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	ocmv1 "github.com/open-component-model/ocm-controller/api/v1alpha1"
+	"github.com/open-component-model/ocm-controller/pkg/cache"
+	"github.com/open-component-model/ocm-controller/pkg/ocm"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+	"github.com/open-component-model/git-controller/pkg/gogit"
+	"github.com/open-component-model/git-controller/pkg/providers"
+)
+
+const (
+	identityKey = "identity"
+	usernameKey = "username"
+	passwordKey = "password"
+)
+
+// Loader gathers the initial content of a repository from the source configured in its spec.
+type Loader struct {
+	client client.Client
+	cache  cache.Cache
+}
+
+// NewLoader creates a new content loader. The cache is used to fetch the content of Snapshots.
+func NewLoader(client client.Client, cache cache.Cache) *Loader {
+	return &Loader{
+		client: client,
+		cache:  cache,
+	}
+}
+
+var _ providers.ContentLoader = &Loader{}
+
+// Load returns the rendered files of the repository's initial commit.
+func (l *Loader) Load(ctx context.Context, obj mpasv1alpha1.Repository) ([]providers.File, error) {
+	var (
+		files []providers.File
+		err   error
+	)
+
+	source := obj.Spec.InitialContent
+
+	switch {
+	case source == nil:
+		files = DefaultFiles(obj)
+	case source.ConfigMapRef != nil:
+		files, err = l.fromConfigMap(ctx, obj.Namespace, *source.ConfigMapRef)
+	case source.SnapshotRef != nil:
+		files, err = l.fromSnapshot(ctx, obj.Namespace, source.SnapshotRef.Name)
+	case source.TemplateRepository != nil:
+		files, err = l.fromTemplateRepository(ctx, obj.Namespace, *source.TemplateRepository)
+	default:
+		return nil, fmt.Errorf("initial content must define a source")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return Render(files, obj)
+}
+
+// DefaultFiles returns the MPAS project layout with a CODEOWNERS file listing the maintainers.
+func DefaultFiles(obj mpasv1alpha1.Repository) []providers.File {
+	var files []providers.File
+
+	if len(obj.Spec.Maintainers) > 0 {
+		content := strings.Builder{}
+
+		for _, m := range obj.Spec.Maintainers {
+			content.WriteString(fmt.Sprintf("%s\n", m))
+		}
+
+		files = append(files, providers.File{
+			Path:    "CODEOWNERS",
+			Content: []byte(content.String()),
+		})
+	}
+
+	for _, dir := range []string{"generators", "products", "subscriptions", "targets"} {
+		files = append(files, providers.File{
+			Path: dir + "/.keep",
+		})
+	}
+
+	return files
+}
+
+// Render executes every text file as a template with the Repository as data. Binary files are kept as they are.
+func Render(files []providers.File, obj mpasv1alpha1.Repository) ([]providers.File, error) {
+	result := make([]providers.File, 0, len(files))
+
+	for _, file := range files {
+		if !utf8.Valid(file.Content) {
+			result = append(result, file)
+
+			continue
+		}
+
+		tmpl, err := template.New(file.Path).Option("missingkey=error").Parse(string(file.Content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template '%s': %w", file.Path, err)
+		}
+
+		buf := &bytes.Buffer{}
+		if err := tmpl.Execute(buf, obj); err != nil {
+			return nil, fmt.Errorf("failed to render template '%s': %w", file.Path, err)
+		}
+
+		result = append(result, providers.File{
+			Path:    file.Path,
+			Content: buf.Bytes(),
+		})
+	}
+
+	return result, nil
+}
+
+func (l *Loader) fromConfigMap(ctx context.Context, namespace string, ref mpasv1alpha1.ConfigMapContent) ([]providers.File, error) {
+	cm := &corev1.ConfigMap{}
+	if err := l.client.Get(ctx, types.NamespacedName{
+		Name:      ref.Name,
+		Namespace: namespace,
+	}, cm); err != nil {
+		return nil, fmt.Errorf("failed to get config map: %w", err)
+	}
+
+	data := make(map[string][]byte, len(cm.Data)+len(cm.BinaryData))
+	for k, v := range cm.Data {
+		data[k] = []byte(v)
+	}
+
+	for k, v := range cm.BinaryData {
+		data[k] = v
+	}
+
+	var files []providers.File
+
+	if len(ref.Items) == 0 {
+		for k, v := range data {
+			files = append(files, providers.File{
+				Path:    k,
+				Content: v,
+			})
+		}
+
+		sort.Slice(files, func(i, j int) bool {
+			return files[i].Path < files[j].Path
+		})
+
+		return files, nil
+	}
+
+	for _, item := range ref.Items {
+		v, ok := data[item.Key]
+		if !ok {
+			return nil, fmt.Errorf("key '%s' not found in config map '%s'", item.Key, ref.Name)
+		}
+
+		files = append(files, providers.File{
+			Path:    item.Path,
+			Content: v,
+		})
+	}
+
+	return files, nil
+}
+
+func (l *Loader) fromSnapshot(ctx context.Context, namespace, name string) ([]providers.File, error) {
+	snapshot := &ocmv1.Snapshot{}
+	if err := l.client.Get(ctx, types.NamespacedName{
+		Name:      name,
+		Namespace: namespace,
+	}, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+
+	repositoryName, err := ocm.ConstructRepositoryName(snapshot.Spec.Identity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct name: %w", err)
+	}
+
+	blob, err := l.cache.FetchDataByDigest(ctx, repositoryName, snapshot.Spec.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch blob for digest: %w", err)
+	}
+
+	uncompressed, _, err := compression.AutoDecompress(blob)
+	if err != nil {
+		return nil, fmt.Errorf("failed to auto decompress: %w", err)
+	}
+	defer uncompressed.Close()
+
+	dir, err := os.MkdirTemp("", "snapshot")
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize temp folder: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := gogit.Untar(uncompressed, dir); err != nil {
+		return nil, fmt.Errorf("failed to untar content: %w", err)
+	}
+
+	return readDir(dir)
+}
+
+func (l *Loader) fromTemplateRepository(ctx context.Context, namespace string, ref mpasv1alpha1.TemplateRepository) ([]providers.File, error) {
+	auth, err := l.authentication(ctx, namespace, ref.SecretRef)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "template")
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize temp folder: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	branch := ref.Branch
+	if branch == "" {
+		branch = providers.DefaultBaseBranch
+	}
+
+	if _, err := git.PlainCloneContext(ctx, dir, false, &git.CloneOptions{
+		URL:           ref.URL,
+		Auth:          auth,
+		ReferenceName: plumbing.NewBranchReferenceName(branch),
+		SingleBranch:  true,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to clone template repository: %w", err)
+	}
+
+	return readDir(dir)
+}
+
+// authentication constructs the auth method from the secret using the same keys as the Repository's credentials.
+func (l *Loader) authentication(ctx context.Context, namespace string, ref *corev1.LocalObjectReference) (transport.AuthMethod, error) {
+	if ref == nil {
+		return nil, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := l.client.Get(ctx, types.NamespacedName{
+		Name:      ref.Name,
+		Namespace: namespace,
+	}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	if identity, ok := secret.Data[identityKey]; ok {
+		auth, err := ssh.NewPublicKeys(string(secret.Data[usernameKey]), identity, string(secret.Data[passwordKey]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create public key authentication: %w", err)
+		}
+
+		return auth, nil
+	}
+
+	return &http.BasicAuth{
+		Username: string(secret.Data[usernameKey]),
+		Password: string(secret.Data[passwordKey]),
+	}, nil
+}
+
+// readDir reads all regular files below dir except for the .git folder.
+func readDir(dir string) ([]providers.File, error) {
+	var files []providers.File
+
+	if err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if d.Name() == git.GitDirName {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, providers.File{
+			Path:    filepath.ToSlash(rel),
+			Content: content,
+		})
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to read files: %w", err)
+	}
+
+	return files, nil
+}
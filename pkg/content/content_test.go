@@ -0,0 +1,178 @@
+package content
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+	"github.com/open-component-model/git-controller/pkg/gitserver"
+	"github.com/open-component-model/git-controller/pkg/providers"
+)
+
+func TestLoadDefault(t *testing.T) {
+	repository := mpasv1alpha1.Repository{
+		Spec: mpasv1alpha1.RepositorySpec{
+			Maintainers: []string{"@alice", "@bob"},
+		},
+	}
+
+	files, err := NewLoader(fake.NewClientBuilder().Build(), nil).Load(context.Background(), repository)
+	require.NoError(t, err)
+
+	assert.Equal(t, []providers.File{
+		{Path: "CODEOWNERS", Content: []byte("@alice\n@bob\n")},
+		{Path: "generators/.keep"},
+		{Path: "products/.keep"},
+		{Path: "subscriptions/.keep"},
+		{Path: "targets/.keep"},
+	}, files)
+}
+
+func TestLoadConfigMap(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "layout",
+			Namespace: "default",
+		},
+		Data: map[string]string{
+			"readme": "# {{ .Name }} owned by {{ .Spec.Owner }}",
+			"keep":   "",
+		},
+		BinaryData: map[string][]byte{
+			"logo": {0xff, 0xfe, 0x00},
+		},
+	}
+	repository := mpasv1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "service",
+			Namespace: "default",
+		},
+		Spec: mpasv1alpha1.RepositorySpec{
+			Owner: "team",
+		},
+	}
+
+	loader := NewLoader(fake.NewClientBuilder().WithObjects(cm).Build(), nil)
+
+	repository.Spec.InitialContent = &mpasv1alpha1.InitialContent{
+		ConfigMapRef: &mpasv1alpha1.ConfigMapContent{
+			Name: cm.Name,
+		},
+	}
+
+	files, err := loader.Load(context.Background(), repository)
+	require.NoError(t, err)
+	assert.Equal(t, []providers.File{
+		{Path: "keep"},
+		{Path: "logo", Content: []byte{0xff, 0xfe, 0x00}},
+		{Path: "readme", Content: []byte("# service owned by team")},
+	}, files)
+
+	repository.Spec.InitialContent.ConfigMapRef.Items = []corev1.KeyToPath{
+		{Key: "readme", Path: "docs/README.md"},
+	}
+
+	files, err = loader.Load(context.Background(), repository)
+	require.NoError(t, err)
+	assert.Equal(t, []providers.File{
+		{Path: "docs/README.md", Content: []byte("# service owned by team")},
+	}, files)
+
+	repository.Spec.InitialContent.ConfigMapRef.Items = []corev1.KeyToPath{
+		{Key: "missing", Path: "missing"},
+	}
+
+	_, err = loader.Load(context.Background(), repository)
+	assert.EqualError(t, err, "key 'missing' not found in config map 'layout'")
+}
+
+func TestLoadTemplateRepository(t *testing.T) {
+	server := gitserver.New(t.TempDir())
+	defer server.Close()
+
+	_, err := server.InitRepository("templates/service")
+	require.NoError(t, err)
+	pushFiles(t, server.URL()+"/templates/service", map[string]string{
+		"README.md":         "# {{ .Name }}",
+		"deploy/kustomize":  "namespace: {{ .Namespace }}",
+		"deploy/.gitignore": "*.tmp",
+	})
+
+	repository := mpasv1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "service",
+			Namespace: "default",
+		},
+		Spec: mpasv1alpha1.RepositorySpec{
+			InitialContent: &mpasv1alpha1.InitialContent{
+				TemplateRepository: &mpasv1alpha1.TemplateRepository{
+					URL: server.URL() + "/templates/service",
+				},
+			},
+		},
+	}
+
+	files, err := NewLoader(fake.NewClientBuilder().Build(), nil).Load(context.Background(), repository)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []providers.File{
+		{Path: "README.md", Content: []byte("# service")},
+		{Path: "deploy/kustomize", Content: []byte("namespace: default")},
+		{Path: "deploy/.gitignore", Content: []byte("*.tmp")},
+	}, files)
+}
+
+func TestRenderFailsOnUnknownField(t *testing.T) {
+	_, err := Render([]providers.File{
+		{Path: "README.md", Content: []byte("{{ .Unknown }}")},
+	}, mpasv1alpha1.Repository{})
+	assert.ErrorContains(t, err, "failed to render template 'README.md'")
+}
+
+func pushFiles(t *testing.T, url string, files map[string]string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	r, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+
+	w, err := r.Worktree()
+	require.NoError(t, err)
+
+	for path, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, path), []byte(content), 0o600))
+		_, err = w.Add(path)
+		require.NoError(t, err)
+	}
+
+	commit, err := w.Commit("initial commit", &git.CommitOptions{
+		Author: &object.Signature{
+			Name:  "test",
+			Email: "test@example.com",
+			When:  time.Now(),
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = r.CreateRemote(&config.RemoteConfig{
+		Name: git.DefaultRemoteName,
+		URLs: []string{url},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, r.Push(&git.PushOptions{
+		RefSpecs: []config.RefSpec{config.RefSpec(commit.String() + ":refs/heads/main")},
+	}))
+}
@@ -0,0 +1,132 @@
+package deploykey
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const defaultSSHPort = "22"
+
+// KeyPair is a generated ED25519 key pair.
+type KeyPair struct {
+	// PrivateKey is the PEM encoded private key in OpenSSH format.
+	PrivateKey []byte
+	// PublicKey is the public key in authorized_keys format.
+	PublicKey []byte
+	// Fingerprint is the SHA256 fingerprint of the public key.
+	Fingerprint string
+}
+
+// Generate creates a new ED25519 key pair.
+func Generate(comment string) (KeyPair, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	block, err := ssh.MarshalPrivateKey(private, comment)
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("failed to marshal private key: %w", err)
+	}
+
+	publicKey, err := ssh.NewPublicKey(public)
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("failed to create public key: %w", err)
+	}
+
+	return KeyPair{
+		PrivateKey:  pem.EncodeToMemory(block),
+		PublicKey:   ssh.MarshalAuthorizedKey(publicKey),
+		Fingerprint: ssh.FingerprintSHA256(publicKey),
+	}, nil
+}
+
+// KnownHosts connects to the SSH server of the clone URL and returns its host key as a known_hosts line.
+// Both `ssh://` and scp-like URLs such as `git@github.com:owner/repo.git` are accepted.
+func KnownHosts(ctx context.Context, sshURL string) ([]byte, error) {
+	address, err := Address(sshURL)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{}
+
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to '%s': %w", address, err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return nil, fmt.Errorf("failed to set deadline: %w", err)
+		}
+	}
+
+	var hostKey ssh.PublicKey
+
+	// The handshake is aborted once the host key is known, there is no need to authenticate.
+	errHostKey := errors.New("host key received")
+
+	_, _, _, err = ssh.NewClientConn(conn, address, &ssh.ClientConfig{
+		User: "git",
+		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			hostKey = key
+
+			return errHostKey
+		},
+	})
+	if hostKey == nil {
+		return nil, fmt.Errorf("failed to get host key of '%s': %w", address, err)
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to split address: %w", err)
+	}
+
+	if port != defaultSSHPort {
+		host = address
+	}
+
+	return []byte(knownhosts.Line([]string{knownhosts.Normalize(host)}, hostKey) + "\n"), nil
+}
+
+// Address returns the host and port of an SSH clone URL.
+func Address(sshURL string) (string, error) {
+	if strings.Contains(sshURL, "://") {
+		u, err := url.Parse(sshURL)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse url: %w", err)
+		}
+
+		port := u.Port()
+		if port == "" {
+			port = defaultSSHPort
+		}
+
+		return net.JoinHostPort(u.Hostname(), port), nil
+	}
+
+	// scp-like syntax: [user@]host:path
+	host, _, ok := strings.Cut(sshURL, ":")
+	if !ok {
+		return "", fmt.Errorf("invalid ssh url '%s'", sshURL)
+	}
+
+	if _, h, found := strings.Cut(host, "@"); found {
+		host = h
+	}
+
+	return net.JoinHostPort(host, defaultSSHPort), nil
+}
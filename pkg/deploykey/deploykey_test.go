@@ -0,0 +1,89 @@
+package deploykey
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestGenerate(t *testing.T) {
+	pair, err := Generate("test")
+	require.NoError(t, err)
+
+	signer, err := ssh.ParsePrivateKey(pair.PrivateKey)
+	require.NoError(t, err)
+
+	public, _, _, _, err := ssh.ParseAuthorizedKey(pair.PublicKey)
+	require.NoError(t, err)
+
+	assert.Equal(t, ssh.KeyAlgoED25519, public.Type())
+	assert.Equal(t, public.Marshal(), signer.PublicKey().Marshal())
+	assert.Equal(t, ssh.FingerprintSHA256(public), pair.Fingerprint)
+}
+
+func TestAddress(t *testing.T) {
+	tests := []struct {
+		url     string
+		address string
+		err     bool
+	}{
+		{url: "git@github.com:owner/repo.git", address: "github.com:22"},
+		{url: "gitlab.com:owner/repo.git", address: "gitlab.com:22"},
+		{url: "ssh://git@gitea.example.com:2222/owner/repo.git", address: "gitea.example.com:2222"},
+		{url: "ssh://git@gitea.example.com/owner/repo.git", address: "gitea.example.com:22"},
+		{url: "github.com/owner/repo", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			address, err := Address(tt.url)
+			if tt.err {
+				assert.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.address, address)
+		})
+	}
+}
+
+func TestKnownHosts(t *testing.T) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	hostKey, err := ssh.NewSignerFromKey(private)
+	require.NoError(t, err)
+
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(hostKey)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, _, _, _ = ssh.NewServerConn(conn, config)
+	}()
+
+	knownHosts, err := KnownHosts(context.Background(), "ssh://git@"+listener.Addr().String()+"/owner/repo.git")
+	require.NoError(t, err)
+
+	line := strings.TrimSpace(string(knownHosts))
+	assert.True(t, strings.HasPrefix(line, "["+strings.Replace(listener.Addr().String(), ":", "]:", 1)+" "))
+	assert.True(t, strings.HasSuffix(line, strings.TrimSpace(string(ssh.MarshalAuthorizedKey(hostKey.PublicKey())))))
+}
@@ -27,3 +27,19 @@ func New(recorder kuberecorder.EventRecorder, obj conditions.Getter, severity, m
 
 	recorder.AnnotatedEventf(obj, metadata, eventType, reason, msg)
 }
+
+// Identifiable is an object which describes itself through the metadata of its events.
+type Identifiable interface {
+	conditions.Setter
+
+	// GetVID returns the metadata identifying the last reconciled state of the object.
+	GetVID() map[string]string
+}
+
+// MarkReady sets the Ready condition of obj to true and emits an event carrying the metadata of obj, so alerts
+// forwarded to notification-controller can link to the reconciled change.
+func MarkReady(recorder kuberecorder.EventRecorder, obj Identifiable, msg string) {
+	conditions.MarkTrue(obj, meta.ReadyCondition, meta.SucceededReason, "%s", msg)
+	conditions.Delete(obj, meta.ReconcilingCondition)
+	New(recorder, obj, eventv1.EventSeverityInfo, msg, obj.GetVID())
+}
@@ -11,7 +11,10 @@ import (
 
 	"github.com/fluxcd/pkg/runtime/conditions"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
+
+	deliveryv1alpha1 "github.com/open-component-model/git-controller/apis/delivery/v1alpha1"
 )
 
 func TestNewEvent(t *testing.T) {
@@ -48,3 +51,37 @@ func TestNewEvent(t *testing.T) {
 		})
 	}
 }
+
+type annotationRecorder struct {
+	record.FakeRecorder
+	annotations map[string]string
+}
+
+func (r *annotationRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.annotations = annotations
+	r.FakeRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+}
+
+func TestMarkReady(t *testing.T) {
+	recorder := &annotationRecorder{FakeRecorder: *record.NewFakeRecorder(1)}
+	obj := &deliveryv1alpha1.Sync{
+		Status: deliveryv1alpha1.SyncStatus{
+			Digest:        "digest",
+			Commit:        "2f0e0c1d",
+			PullRequestID: 7,
+		},
+	}
+	conditions.MarkUnknown(obj, meta.ReconcilingCondition, meta.ProgressingReason, "reconciling")
+
+	MarkReady(recorder, obj, "Reconciliation success")
+
+	assert.True(t, conditions.IsReady(obj))
+	assert.False(t, conditions.IsReconciling(obj))
+	assert.Equal(t, "Reconciliation success", conditions.GetMessage(obj, meta.ReadyCondition))
+	assert.Equal(t, "Normal Succeeded Reconciliation success", <-recorder.Events)
+	assert.Equal(t, map[string]string{
+		"delivery.ocm.software/sync":         "7:digest",
+		"delivery.ocm.software/commit":       "2f0e0c1d",
+		"delivery.ocm.software/pull_request": "7",
+	}, recorder.annotations)
+}
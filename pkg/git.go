@@ -3,6 +3,7 @@ package pkg
 import (
 	"context"
 
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
 	ocmv1 "github.com/open-component-model/ocm-controller/api/v1alpha1"
 )
 
@@ -26,21 +27,46 @@ type Auth struct {
 	SSH       *SSH
 }
 
+// Content is written into a path of the repository. Exactly one of Snapshot, Artifact and Files is set.
+type Content struct {
+	// Snapshot is fetched from the OCI cache.
+	Snapshot *ocmv1.Snapshot
+	// Artifact is the tarball of a Flux source, downloaded from its URL.
+	Artifact *sourcev1.Artifact
+	// Files maps paths relative to SubPath to their content.
+	Files   map[string][]byte
+	SubPath string
+}
+
 // PushOptions contains settings for a push action.
 type PushOptions struct {
-	Auth         *Auth
-	URL          string
-	Message      string
-	Name         string
-	Email        string
-	Snapshot     *ocmv1.Snapshot
+	Auth    *Auth
+	URL     string
+	Message string
+	Name    string
+	Email   string
+	// Content is written in order into the same worktree and pushed as a single commit.
+	Content      []Content
 	BaseBranch   string
 	TargetBranch string
-	SubPath      string
 	Prune        bool
 }
 
+// ImportOptions contains settings for copying the history of a repository into another one.
+type ImportOptions struct {
+	SourceURL  string
+	SourceAuth *Auth
+	TargetURL  string
+	TargetAuth *Auth
+}
+
+// PushResult describes the commit created by a push action.
+type PushResult struct {
+	// Commit is the SHA of the pushed commit.
+	Commit string
+}
+
 // Git defines an interface to abstract git operations.
 type Git interface {
-	Push(ctx context.Context, opts *PushOptions) (string, error)
+	Push(ctx context.Context, opts *PushOptions) (PushResult, error)
 }
@@ -0,0 +1,209 @@
+// Package gitserver provides a minimal smart HTTP git server backed by go-git. It is meant to be used in tests
+// which need a real remote to clone from and push to.
+package gitserver
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/format/pktline"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/server"
+)
+
+const (
+	uploadPackService  = "git-upload-pack"
+	receivePackService = "git-receive-pack"
+)
+
+// Server serves bare repositories located under a root folder.
+type Server struct {
+	root      string
+	transport transport.Transport
+	server    *httptest.Server
+}
+
+// New creates and starts a new git server serving repositories from root.
+func New(root string) *Server {
+	s := &Server{
+		root:      root,
+		transport: server.NewServer(server.NewFilesystemLoader(osfs.New(root))),
+	}
+
+	s.server = httptest.NewServer(s)
+
+	return s
+}
+
+// URL returns the base address of the server.
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// InitRepository creates a new bare repository at path relative to the server root.
+func (s *Server) InitRepository(path string) (*git.Repository, error) {
+	return git.PlainInit(filepath.Join(s.root, path), true)
+}
+
+// ServeHTTP implements the smart HTTP protocol for both fetching and pushing.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/info/refs"):
+		s.advertiseReferences(w, r, strings.TrimSuffix(r.URL.Path, "/info/refs"), r.URL.Query().Get("service"))
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/"+uploadPackService):
+		s.uploadPack(w, r, strings.TrimSuffix(r.URL.Path, "/"+uploadPackService))
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/"+receivePackService):
+		s.receivePack(w, r, strings.TrimSuffix(r.URL.Path, "/"+receivePackService))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) advertiseReferences(w http.ResponseWriter, r *http.Request, path, service string) {
+	ep, err := transport.NewEndpoint(path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	var refs *packp.AdvRefs
+
+	switch service {
+	case uploadPackService:
+		session, err := s.transport.NewUploadPackSession(ep, nil)
+		if err != nil {
+			writeSessionError(w, err)
+
+			return
+		}
+
+		refs, err = session.AdvertisedReferencesContext(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+	case receivePackService:
+		session, err := s.transport.NewReceivePackSession(ep, nil)
+		if err != nil {
+			writeSessionError(w, err)
+
+			return
+		}
+
+		refs, err = session.AdvertisedReferencesContext(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+	default:
+		http.Error(w, fmt.Sprintf("unsupported service '%s'", service), http.StatusForbidden)
+
+		return
+	}
+
+	refs.Prefix = [][]byte{[]byte("# service=" + service), pktline.Flush}
+
+	w.Header().Set("Content-Type", fmt.Sprintf("application/x-%s-advertisement", service))
+
+	if err := refs.Encode(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) uploadPack(w http.ResponseWriter, r *http.Request, path string) {
+	ep, err := transport.NewEndpoint(path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	session, err := s.transport.NewUploadPackSession(ep, nil)
+	if err != nil {
+		writeSessionError(w, err)
+
+		return
+	}
+
+	req := packp.NewUploadPackRequest()
+	if err := req.Decode(r.Body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	resp, err := session.UploadPack(r.Context(), req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+	defer resp.Close()
+
+	w.Header().Set("Content-Type", "application/x-git-upload-pack-result")
+
+	if err := resp.Encode(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) receivePack(w http.ResponseWriter, r *http.Request, path string) {
+	ep, err := transport.NewEndpoint(path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	session, err := s.transport.NewReceivePackSession(ep, nil)
+	if err != nil {
+		writeSessionError(w, err)
+
+		return
+	}
+
+	req := packp.NewReferenceUpdateRequest()
+	if err := req.Decode(r.Body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	status, err := session.ReceivePack(r.Context(), req)
+	if status == nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-git-receive-pack-result")
+
+	if err := status.Encode(w); err != nil {
+		_, _ = io.WriteString(w, err.Error())
+	}
+}
+
+func writeSessionError(w http.ResponseWriter, err error) {
+	if err == transport.ErrRepositoryNotFound {
+		http.Error(w, err.Error(), http.StatusNotFound)
+
+		return
+	}
+
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
@@ -1,14 +1,19 @@
 package gogit
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	nethttp "net/http"
 	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/containers/image/v5/pkg/compression"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/plumbing/transport"
@@ -16,119 +21,351 @@ import (
 	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
 	"github.com/go-logr/logr"
 	"github.com/open-component-model/git-controller/apis/delivery/v1alpha1"
+	"github.com/opencontainers/go-digest"
+	"go.opentelemetry.io/otel/attribute"
 
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	ocmv1 "github.com/open-component-model/ocm-controller/api/v1alpha1"
 	"github.com/open-component-model/ocm-controller/pkg/cache"
 	"github.com/open-component-model/ocm-controller/pkg/ocm"
 
 	"github.com/open-component-model/git-controller/pkg"
+	"github.com/open-component-model/git-controller/pkg/metrics"
+	"github.com/open-component-model/git-controller/pkg/tracing"
 )
 
 type Git struct {
 	Logger   logr.Logger
 	OciCache cache.Cache
+	// CloneDepth limits the history fetched before pushing. Zero clones the full history.
+	CloneDepth int
+	// HTTPClient downloads the artifacts of Flux sources. Defaults to http.DefaultClient.
+	HTTPClient *nethttp.Client
+
+	// locks orders pushes into the same repository and branch.
+	locks keyedMutex
 }
 
 func NewGoGit(log logr.Logger, cache cache.Cache) *Git {
 	return &Git{
-		Logger:   log,
-		OciCache: cache,
+		Logger:     log,
+		OciCache:   cache,
+		CloneDepth: 1,
 	}
 }
 
-func (g *Git) Push(ctx context.Context, opts *pkg.PushOptions) (string, error) {
-	g.Logger.V(v1alpha1.LevelDebug).Info(
+func (g *Git) Push(ctx context.Context, opts *pkg.PushOptions) (_ pkg.PushResult, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.PushDuration.WithLabelValues(metrics.Result(err)).Observe(time.Since(start).Seconds())
+	}()
+
+	ctx, span := tracing.Start(ctx, "git.Push",
+		attribute.String("git.url", opts.URL),
+		attribute.String("git.base_branch", opts.BaseBranch),
+		attribute.String("git.target_branch", opts.TargetBranch),
+		attribute.Int("content.count", len(opts.Content)),
+	)
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	logger := tracing.WithTraceID(ctx, g.Logger)
+	logger.V(v1alpha1.LevelDebug).Info(
 		"running push operation",
 		"msg",
 		opts.Message,
-		"snapshot",
-		opts.Snapshot.Name,
+		"content",
+		len(opts.Content),
 		"url",
 		opts.URL,
-		"sub-path",
-		opts.SubPath,
 	)
 
+	// Pushes into the same branch are serialized, so each of them clones the result of the previous one instead
+	// of being rejected as a non-fast-forward update.
+	_, lockSpan := tracing.Start(ctx, "git.WaitForLock")
+	unlock, err := g.locks.Lock(ctx, opts.URL+"#"+opts.TargetBranch)
+	tracing.End(lockSpan, err)
+	if err != nil {
+		return pkg.PushResult{}, fmt.Errorf("failed to wait for concurrent push to finish: %w", err)
+	}
+	defer unlock()
+
 	dir, err := os.MkdirTemp("", "clone")
 	if err != nil {
-		return "", fmt.Errorf("failed to initialize temp folder: %w", err)
+		return pkg.PushResult{}, fmt.Errorf("failed to initialize temp folder: %w", err)
 	}
 
-	var auth transport.AuthMethod
-	if opts.Auth != nil {
-		if v := opts.Auth.BasicAuth; v != nil {
-			auth = &http.BasicAuth{
-				Username: v.Username,
-				Password: v.Password,
-			}
-		}
-		if v := opts.Auth.SSH; v != nil {
-			pb, err := ssh.NewPublicKeys(v.User, v.PemBytes, v.Password)
-			if err != nil {
-				return "", fmt.Errorf("failed to create public key authentication: %w", err)
-			}
-			auth = pb
-		}
+	auth, err := AuthMethod(opts.Auth)
+	if err != nil {
+		return pkg.PushResult{}, err
 	}
 
 	cloneOptions := &git.CloneOptions{
 		URL:           opts.URL,
-		Depth:         1,
+		Depth:         g.CloneDepth,
 		ReferenceName: plumbing.ReferenceName(fmt.Sprintf("refs/heads/%s", opts.BaseBranch)),
 		Auth:          auth,
 	}
 
+	_, cloneSpan := tracing.Start(ctx, "git.Clone")
+	cloneStart := time.Now()
 	r, err := git.PlainClone(dir, false, cloneOptions)
+	metrics.CloneDuration.WithLabelValues(metrics.Result(err)).Observe(time.Since(cloneStart).Seconds())
+	tracing.End(cloneSpan, err)
 	if err != nil {
-		return "", fmt.Errorf("failed to clone repository: %w", err)
+		return pkg.PushResult{}, fmt.Errorf("failed to clone repository: %w", err)
 	}
 
 	w, err := r.Worktree()
 	if err != nil {
-		return "", fmt.Errorf("failed to create a worktree: %w", err)
+		return pkg.PushResult{}, fmt.Errorf("failed to create a worktree: %w", err)
 	}
 
 	if opts.TargetBranch != opts.BaseBranch {
-		if err := w.Checkout(&git.CheckoutOptions{
-			Branch: plumbing.NewBranchReferenceName(opts.TargetBranch),
-			Create: true,
-		}); err != nil {
-			return "", fmt.Errorf("failed to checkout new branch: %w", err)
+		if err := g.checkoutTargetBranch(r, w, opts.TargetBranch, auth); err != nil {
+			return pkg.PushResult{}, err
 		}
 	}
 
-	dir = filepath.Join(dir, opts.SubPath)
+	var written int64
+
+	for _, content := range opts.Content {
+		n, err := g.extract(ctx, dir, content)
+		if err != nil {
+			return pkg.PushResult{}, err
+		}
+
+		written += n
+	}
+
+	metrics.PushBytes.Observe(float64(written))
+
+	commit, err := g.commit(ctx, w, opts)
+	if err != nil {
+		return pkg.PushResult{}, err
+	}
+
+	logger.V(v1alpha1.LevelDebug).Info("pushing commit", "commit", commit)
+	pushOptions := &git.PushOptions{
+		Prune: opts.Prune,
+		Auth:  auth,
+	}
+
+	_, pushSpan := tracing.Start(ctx, "git.PushCommit")
+	err = r.Push(pushOptions)
+	tracing.End(pushSpan, err)
+	if err != nil {
+		return pkg.PushResult{}, fmt.Errorf("failed to push new snapshot: %w", err)
+	}
+
+	return pkg.PushResult{Commit: commit.String()}, nil
+}
+
+// checkoutTargetBranch checks out the target branch on top of its remote state if it already exists, so the new
+// commit fast-forwards it. Otherwise, the branch is created from the cloned base branch.
+func (g *Git) checkoutTargetBranch(r *git.Repository, w *git.Worktree, branch string, auth transport.AuthMethod) error {
+	remoteRef := plumbing.NewRemoteReferenceName(git.DefaultRemoteName, branch)
+
+	err := r.Fetch(&git.FetchOptions{
+		RefSpecs: []config.RefSpec{
+			config.RefSpec(fmt.Sprintf("+%s:%s", plumbing.NewBranchReferenceName(branch), remoteRef)),
+		},
+		Depth: g.CloneDepth,
+		Auth:  auth,
+	})
+
+	checkout := &git.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName(branch),
+		Create: true,
+	}
+
+	switch {
+	case err == nil || errors.Is(err, git.NoErrAlreadyUpToDate):
+		ref, err := r.Reference(remoteRef, true)
+		if err != nil {
+			return fmt.Errorf("failed to find fetched branch '%s': %w", branch, err)
+		}
+
+		checkout.Hash = ref.Hash()
+	case errors.Is(err, git.NoMatchingRefSpecError{}):
+		// The branch doesn't exist yet and is created from the base branch.
+	default:
+		return fmt.Errorf("failed to fetch branch '%s': %w", branch, err)
+	}
+
+	if err := w.Checkout(checkout); err != nil {
+		return fmt.Errorf("failed to checkout branch '%s': %w", branch, err)
+	}
+
+	return nil
+}
+
+// extract writes content into its sub path of the worktree and returns the number of bytes written.
+func (g *Git) extract(ctx context.Context, worktree string, content pkg.Content) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "git.Extract", attribute.String("content.sub_path", content.SubPath))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	dir := filepath.Join(worktree, content.SubPath)
 	const perm = 0o777
 	if err := os.MkdirAll(dir, perm); err != nil {
-		return "", fmt.Errorf("failed to create subPath: %w", err)
+		return 0, fmt.Errorf("failed to create subPath: %w", err)
 	}
 
-	name, err := ocm.ConstructRepositoryName(opts.Snapshot.Spec.Identity)
+	switch {
+	case content.Snapshot != nil:
+		span.SetAttributes(
+			attribute.String("snapshot.name", content.Snapshot.Name),
+			attribute.String("snapshot.digest", content.Snapshot.Spec.Digest),
+		)
+
+		return g.extractSnapshot(ctx, dir, content.Snapshot)
+	case content.Artifact != nil:
+		span.SetAttributes(
+			attribute.String("artifact.url", content.Artifact.URL),
+			attribute.String("artifact.digest", content.Artifact.Digest),
+		)
+
+		return g.extractArtifact(ctx, dir, content.Artifact)
+	default:
+		return writeFiles(dir, content.Files)
+	}
+}
+
+// extractSnapshot fetches the blob of a snapshot from the OCI cache and unpacks it into dir.
+func (g *Git) extractSnapshot(ctx context.Context, dir string, snapshot *ocmv1.Snapshot) (int64, error) {
+	name, err := ocm.ConstructRepositoryName(snapshot.Spec.Identity)
+	if err != nil {
+		return 0, fmt.Errorf("failed to construct name: %w", err)
+	}
+
+	fetchCtx, fetchSpan := tracing.Start(ctx, "oci.FetchDataByDigest")
+	blob, err := g.OciCache.FetchDataByDigest(fetchCtx, name, snapshot.Spec.Digest)
+	tracing.End(fetchSpan, err)
 	if err != nil {
-		return "", fmt.Errorf("failed to construct name: %w", err)
+		return 0, fmt.Errorf("failed to fetch blob for digest: %w", err)
 	}
 
-	blob, err := g.OciCache.FetchDataByDigest(ctx, name, opts.Snapshot.Spec.Digest)
+	n, err := untar(ctx, blob, dir)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch blob for digest: %w", err)
+		return 0, fmt.Errorf("failed to untar content of snapshot %s: %w", snapshot.Name, err)
 	}
 
+	return n, nil
+}
+
+// extractArtifact downloads the tarball of a Flux source artifact and unpacks it into dir. The download is
+// rejected if it doesn't match the digest of the artifact.
+func (g *Git) extractArtifact(ctx context.Context, dir string, artifact *sourcev1.Artifact) (int64, error) {
+	downloadCtx, downloadSpan := tracing.Start(ctx, "http.DownloadArtifact")
+	data, err := g.download(downloadCtx, artifact.URL)
+	tracing.End(downloadSpan, err)
+	if err != nil {
+		return 0, fmt.Errorf("failed to download artifact: %w", err)
+	}
+
+	if artifact.Digest != "" {
+		expected, err := digest.Parse(artifact.Digest)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse artifact digest: %w", err)
+		}
+
+		if actual := expected.Algorithm().FromBytes(data); actual != expected {
+			return 0, fmt.Errorf("digest mismatch for artifact %s: expected %s but got %s", artifact.URL, expected, actual)
+		}
+	}
+
+	n, err := untar(ctx, io.NopCloser(bytes.NewReader(data)), dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to untar content of artifact %s: %w", artifact.URL, err)
+	}
+
+	return n, nil
+}
+
+func (g *Git) download(ctx context.Context, url string) ([]byte, error) {
+	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	client := g.HTTPClient
+	if client == nil {
+		client = nethttp.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != nethttp.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// untar decompresses blob if needed and writes the files of the tarball into dir.
+func untar(ctx context.Context, blob io.ReadCloser, dir string) (_ int64, err error) {
 	uncompressed, _, err := compression.AutoDecompress(blob)
 	if err != nil {
-		return "", fmt.Errorf("failed to auto decompress: %w", err)
+		return 0, fmt.Errorf("failed to auto decompress: %w", err)
 	}
 	defer uncompressed.Close()
 
-	// we only care about the error if it is NOT a header error. Otherwise, we assume the content
-	// wasn't compressed.
-	if err = Untar(uncompressed, dir); err != nil {
-		return "", fmt.Errorf("failed to untar content: %w", err)
+	_, span := tracing.Start(ctx, "git.Untar")
+	content := &countingReader{reader: uncompressed}
+	err = Untar(content, dir)
+	span.SetAttributes(attribute.Int64("content.bytes", content.count))
+	tracing.End(span, err)
+
+	return content.count, err
+}
+
+// writeFiles writes every file into dir and returns the number of bytes written.
+func writeFiles(dir string, files map[string][]byte) (int64, error) {
+	var written int64
+
+	for name, data := range files {
+		path, err := sanitizeArchivePath(dir, name)
+		if err != nil {
+			return 0, err
+		}
+
+		const perm = 0o644
+		if err := os.WriteFile(path, data, perm); err != nil {
+			return 0, fmt.Errorf("failed to write file %s: %w", name, err)
+		}
+
+		written += int64(len(data))
 	}
 
+	return written, nil
+}
+
+// commit adds all files of the worktree and commits them.
+func (g *Git) commit(ctx context.Context, w *git.Worktree, opts *pkg.PushOptions) (_ plumbing.Hash, err error) {
+	_, span := tracing.Start(ctx, "git.Commit")
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	// Add all extracted files.
 	if err := w.AddGlob("."); err != nil {
-		return "", fmt.Errorf("failed to add items to worktree: %w", err)
+		return plumbing.ZeroHash, fmt.Errorf("failed to add items to worktree: %w", err)
+	}
+
+	worktreeStatus, err := w.Status()
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to get worktree status: %w", err)
 	}
 
+	metrics.FilesChanged.Observe(float64(changedFiles(worktreeStatus)))
+
 	commitOpts := &git.CommitOptions{
 		Author: &object.Signature{
 			Name:  opts.Name,
@@ -139,16 +376,61 @@ func (g *Git) Push(ctx context.Context, opts *pkg.PushOptions) (string, error) {
 
 	commit, err := w.Commit("Uploading snapshot to location", commitOpts)
 	if err != nil {
-		return "", fmt.Errorf("failed to commit changes: %w", err)
+		return plumbing.ZeroHash, fmt.Errorf("failed to commit changes: %w", err)
 	}
-	g.Logger.V(v1alpha1.LevelDebug).Info("pushing commit", "commit", commit)
-	pushOptions := &git.PushOptions{
-		Prune: opts.Prune,
-		Auth:  auth,
+
+	span.SetAttributes(attribute.String("git.commit", commit.String()))
+
+	return commit, nil
+}
+
+// AuthMethod converts the authentication options to a go-git auth method. SSH takes precedence over basic auth.
+func AuthMethod(auth *pkg.Auth) (transport.AuthMethod, error) {
+	if auth == nil {
+		return nil, nil
 	}
-	if err := r.Push(pushOptions); err != nil {
-		return "", fmt.Errorf("failed to push new snapshot: %w", err)
+
+	if v := auth.SSH; v != nil {
+		pb, err := ssh.NewPublicKeys(v.User, v.PemBytes, v.Password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create public key authentication: %w", err)
+		}
+
+		return pb, nil
+	}
+
+	if v := auth.BasicAuth; v != nil {
+		return &http.BasicAuth{
+			Username: v.Username,
+			Password: v.Password,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.count += int64(n)
+
+	return n, err
+}
+
+// changedFiles returns the number of files with staged changes.
+func changedFiles(status git.Status) int {
+	changed := 0
+
+	for _, file := range status {
+		if file.Staging != git.Unmodified && file.Staging != git.Untracked {
+			changed++
+		}
 	}
 
-	return opts.Snapshot.Spec.Digest, nil
+	return changed
 }
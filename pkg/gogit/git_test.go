@@ -0,0 +1,412 @@
+package gogit
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-logr/logr"
+	ocmv1 "github.com/open-component-model/ocm-controller/api/v1alpha1"
+	ocmmetav1 "github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc/meta/v1"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/open-component-model/git-controller/pkg"
+	"github.com/open-component-model/git-controller/pkg/gitserver"
+)
+
+// fakeCache serves tarballs by digest.
+type fakeCache struct {
+	blobs map[string][]byte
+}
+
+func (c *fakeCache) IsCached(ctx context.Context, name, tag string) (bool, error) {
+	return false, nil
+}
+
+func (c *fakeCache) PushData(ctx context.Context, data io.ReadCloser, mediaType, name, tag string) (string, int64, error) {
+	return "", 0, fmt.Errorf("not implemented")
+}
+
+func (c *fakeCache) FetchDataByIdentity(ctx context.Context, name, tag string) (io.ReadCloser, string, int64, error) {
+	return nil, "", 0, fmt.Errorf("not implemented")
+}
+
+func (c *fakeCache) FetchDataByDigest(ctx context.Context, name, digest string) (io.ReadCloser, error) {
+	blob, ok := c.blobs[digest]
+	if !ok {
+		return nil, fmt.Errorf("digest '%s' not found", digest)
+	}
+
+	return io.NopCloser(bytes.NewReader(blob)), nil
+}
+
+func (c *fakeCache) DeleteData(ctx context.Context, name, tag string) error {
+	return nil
+}
+
+func TestPushIsSerializedPerBranch(t *testing.T) {
+	server := gitserver.New(t.TempDir())
+	defer server.Close()
+
+	repository, err := server.InitRepository("tenant/repository")
+	require.NoError(t, err)
+	pushInitialCommit(t, server.URL()+"/tenant/repository", "main")
+
+	const pushes = 5
+
+	cache := &fakeCache{blobs: map[string][]byte{}}
+	for i := 0; i < pushes; i++ {
+		cache.blobs[fmt.Sprintf("digest-%d", i)] = tarball(t, fmt.Sprintf("file-%d.yaml", i), "content")
+	}
+
+	g := &Git{
+		Logger:   logr.Discard(),
+		OciCache: cache,
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, pushes)
+	results := make([]pkg.PushResult, pushes)
+
+	for i := 0; i < pushes; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			results[i], errs[i] = g.Push(context.Background(), &pkg.PushOptions{
+				URL:          server.URL() + "/tenant/repository",
+				Name:         "test",
+				Email:        "test@example.com",
+				BaseBranch:   "main",
+				TargetBranch: "main",
+				Content: []pkg.Content{
+					{Snapshot: snapshot(fmt.Sprintf("snapshot-%d", i), fmt.Sprintf("digest-%d", i)), SubPath: "."},
+				},
+			})
+		}(i)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	ref, err := repository.Reference(plumbing.NewBranchReferenceName("main"), true)
+	require.NoError(t, err)
+
+	commits := map[string]bool{}
+	for _, result := range results {
+		_, err := repository.CommitObject(plumbing.NewHash(result.Commit))
+		assert.NoError(t, err)
+
+		commits[result.Commit] = true
+	}
+	assert.Len(t, commits, pushes)
+	assert.True(t, commits[ref.Hash().String()])
+
+	log, err := repository.Log(&git.LogOptions{From: ref.Hash()})
+	require.NoError(t, err)
+
+	count := 0
+	require.NoError(t, log.ForEach(func(*object.Commit) error {
+		count++
+
+		return nil
+	}))
+	assert.Equal(t, pushes+1, count)
+}
+
+func TestPushIntoExistingTargetBranch(t *testing.T) {
+	server := gitserver.New(t.TempDir())
+	defer server.Close()
+
+	repository, err := server.InitRepository("tenant/repository")
+	require.NoError(t, err)
+	pushInitialCommit(t, server.URL()+"/tenant/repository", "main")
+
+	const pushes = 2
+
+	cache := &fakeCache{blobs: map[string][]byte{}}
+	for i := 0; i < pushes; i++ {
+		cache.blobs[fmt.Sprintf("digest-%d", i)] = tarball(t, fmt.Sprintf("file-%d.yaml", i), "content")
+	}
+
+	g := &Git{
+		Logger:   logr.Discard(),
+		OciCache: cache,
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, pushes)
+
+	// Two Syncs push into the same target branch. Whichever comes second has to build on top of the first one.
+	for i := 0; i < pushes; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			_, errs[i] = g.Push(context.Background(), &pkg.PushOptions{
+				URL:          server.URL() + "/tenant/repository",
+				Name:         "test",
+				Email:        "test@example.com",
+				BaseBranch:   "main",
+				TargetBranch: "release",
+				Content: []pkg.Content{
+					{Snapshot: snapshot(fmt.Sprintf("snapshot-%d", i), fmt.Sprintf("digest-%d", i)), SubPath: "."},
+				},
+			})
+		}(i)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	ref, err := repository.Reference(plumbing.NewBranchReferenceName("release"), true)
+	require.NoError(t, err)
+
+	commit, err := repository.CommitObject(ref.Hash())
+	require.NoError(t, err)
+
+	tree, err := commit.Tree()
+	require.NoError(t, err)
+
+	for i := 0; i < pushes; i++ {
+		_, err := tree.File(fmt.Sprintf("file-%d.yaml", i))
+		assert.NoError(t, err)
+	}
+
+	log, err := repository.Log(&git.LogOptions{From: ref.Hash()})
+	require.NoError(t, err)
+
+	count := 0
+	require.NoError(t, log.ForEach(func(*object.Commit) error {
+		count++
+
+		return nil
+	}))
+	assert.Equal(t, pushes+1, count)
+}
+
+func TestPushSeveralSnapshotsInOneCommit(t *testing.T) {
+	server := gitserver.New(t.TempDir())
+	defer server.Close()
+
+	repository, err := server.InitRepository("tenant/repository")
+	require.NoError(t, err)
+	pushInitialCommit(t, server.URL()+"/tenant/repository", "main")
+
+	cache := &fakeCache{blobs: map[string][]byte{
+		"frontend-digest": tarball(t, "deployment.yaml", "frontend"),
+		"backend-digest":  tarball(t, "deployment.yaml", "backend"),
+	}}
+
+	g := &Git{
+		Logger:   logr.Discard(),
+		OciCache: cache,
+	}
+
+	result, err := g.Push(context.Background(), &pkg.PushOptions{
+		URL:          server.URL() + "/tenant/repository",
+		Name:         "test",
+		Email:        "test@example.com",
+		BaseBranch:   "main",
+		TargetBranch: "product",
+		Content: []pkg.Content{
+			{Snapshot: snapshot("frontend", "frontend-digest"), SubPath: "apps/frontend"},
+			{Snapshot: snapshot("backend", "backend-digest"), SubPath: "apps/backend"},
+		},
+	})
+	require.NoError(t, err)
+
+	ref, err := repository.Reference(plumbing.NewBranchReferenceName("product"), true)
+	require.NoError(t, err)
+	assert.Equal(t, ref.Hash().String(), result.Commit)
+
+	commit, err := repository.CommitObject(ref.Hash())
+	require.NoError(t, err)
+
+	for path, content := range map[string]string{
+		"apps/frontend/deployment.yaml": "frontend",
+		"apps/backend/deployment.yaml":  "backend",
+	} {
+		file, err := commit.File(path)
+		require.NoError(t, err, path)
+
+		contents, err := file.Contents()
+		require.NoError(t, err)
+		assert.Equal(t, content, contents)
+	}
+
+	parent, err := commit.Parent(0)
+	require.NoError(t, err)
+	assert.Zero(t, parent.NumParents(), "both snapshots must be part of a single commit on top of the initial one")
+}
+
+func TestPushSourceArtifactAndFiles(t *testing.T) {
+	server := gitserver.New(t.TempDir())
+	defer server.Close()
+
+	repository, err := server.InitRepository("tenant/repository")
+	require.NoError(t, err)
+	pushInitialCommit(t, server.URL()+"/tenant/repository", "main")
+
+	artifact := tarball(t, "deployment.yaml", "from-flux")
+	artifactServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(artifact)
+	}))
+	defer artifactServer.Close()
+
+	g := &Git{
+		Logger:     logr.Discard(),
+		HTTPClient: artifactServer.Client(),
+	}
+
+	result, err := g.Push(context.Background(), &pkg.PushOptions{
+		URL:          server.URL() + "/tenant/repository",
+		Name:         "test",
+		Email:        "test@example.com",
+		BaseBranch:   "main",
+		TargetBranch: "main",
+		Content: []pkg.Content{
+			{Artifact: &sourcev1.Artifact{URL: artifactServer.URL, Digest: digest.FromBytes(artifact).String()}, SubPath: "manifests"},
+			{Files: map[string][]byte{"values.yaml": []byte("replicas: 2")}, SubPath: "values"},
+		},
+	})
+	require.NoError(t, err)
+
+	commit, err := repository.CommitObject(plumbing.NewHash(result.Commit))
+	require.NoError(t, err)
+
+	for path, content := range map[string]string{
+		"manifests/deployment.yaml": "from-flux",
+		"values/values.yaml":        "replicas: 2",
+	} {
+		file, err := commit.File(path)
+		require.NoError(t, err, path)
+
+		contents, err := file.Contents()
+		require.NoError(t, err)
+		assert.Equal(t, content, contents)
+	}
+
+	_, err = g.Push(context.Background(), &pkg.PushOptions{
+		URL:          server.URL() + "/tenant/repository",
+		Name:         "test",
+		Email:        "test@example.com",
+		BaseBranch:   "main",
+		TargetBranch: "main",
+		Content: []pkg.Content{
+			{Artifact: &sourcev1.Artifact{URL: artifactServer.URL, Digest: digest.FromString("tampered").String()}},
+		},
+	})
+	assert.ErrorContains(t, err, "digest mismatch for artifact")
+}
+
+func TestKeyedMutex(t *testing.T) {
+	var m keyedMutex
+
+	unlock, err := m.Lock(context.Background(), "a")
+	require.NoError(t, err)
+
+	// A different key is not blocked.
+	unlockB, err := m.Lock(context.Background(), "b")
+	require.NoError(t, err)
+	unlockB()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = m.Lock(ctx, "a")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	unlock()
+
+	unlock, err = m.Lock(context.Background(), "a")
+	require.NoError(t, err)
+	unlock()
+
+	assert.Empty(t, m.locks)
+}
+
+func snapshot(name, digest string) *ocmv1.Snapshot {
+	return &ocmv1.Snapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: ocmv1.SnapshotSpec{
+			Identity: ocmmetav1.Identity{ocmv1.ComponentNameKey: "component"},
+			Digest:   digest,
+		},
+	}
+}
+
+func tarball(t *testing.T, name, content string) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{
+		Name: name,
+		Mode: 0o600,
+		Size: int64(len(content)),
+	}))
+	_, err := tw.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	return buf.Bytes()
+}
+
+func pushInitialCommit(t *testing.T, url, branch string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	r, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("readme"), 0o600))
+
+	w, err := r.Worktree()
+	require.NoError(t, err)
+	_, err = w.Add("README.md")
+	require.NoError(t, err)
+
+	commit, err := w.Commit("initial commit", &git.CommitOptions{
+		Author: &object.Signature{
+			Name:  "test",
+			Email: "test@example.com",
+			When:  time.Now(),
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = r.CreateRemote(&config.RemoteConfig{
+		Name: git.DefaultRemoteName,
+		URLs: []string{url},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, r.Push(&git.PushOptions{
+		RefSpecs: []config.RefSpec{config.RefSpec(commit.String() + ":refs/heads/" + branch)},
+	}))
+}
@@ -0,0 +1,79 @@
+package gogit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+
+	"github.com/open-component-model/git-controller/pkg"
+)
+
+// Import copies all branches and tags of the source repository into the target repository. Refs which already
+// exist in the target are overwritten.
+func Import(ctx context.Context, opts *pkg.ImportOptions) error {
+	sourceAuth, err := AuthMethod(opts.SourceAuth)
+	if err != nil {
+		return err
+	}
+
+	targetAuth, err := AuthMethod(opts.TargetAuth)
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "import")
+	if err != nil {
+		return fmt.Errorf("failed to initialize temp folder: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Fetching into an empty repository rather than cloning avoids having to resolve the HEAD of the source, which
+	// may point to a branch that does not exist.
+	r, err := git.PlainInit(dir, true)
+	if err != nil {
+		return fmt.Errorf("failed to initialize import repository: %w", err)
+	}
+
+	source, err := r.CreateRemote(&config.RemoteConfig{
+		Name: git.DefaultRemoteName,
+		URLs: []string{opts.SourceURL},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create source remote: %w", err)
+	}
+
+	if err := source.FetchContext(ctx, &git.FetchOptions{
+		Auth: sourceAuth,
+		RefSpecs: []config.RefSpec{
+			"+refs/heads/*:refs/heads/*",
+			"+refs/tags/*:refs/tags/*",
+		},
+	}); err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("failed to fetch source repository: %w", err)
+	}
+
+	remote, err := r.CreateRemote(&config.RemoteConfig{
+		Name: "target",
+		URLs: []string{opts.TargetURL},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create target remote: %w", err)
+	}
+
+	if err := remote.PushContext(ctx, &git.PushOptions{
+		RemoteName: "target",
+		Auth:       targetAuth,
+		RefSpecs: []config.RefSpec{
+			"+refs/heads/*:refs/heads/*",
+			"+refs/tags/*:refs/tags/*",
+		},
+	}); err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("failed to push to target repository: %w", err)
+	}
+
+	return nil
+}
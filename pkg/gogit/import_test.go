@@ -0,0 +1,77 @@
+package gogit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-component-model/git-controller/pkg"
+	"github.com/open-component-model/git-controller/pkg/gitserver"
+)
+
+func TestImport(t *testing.T) {
+	server := gitserver.New(t.TempDir())
+	defer server.Close()
+
+	_, err := server.InitRepository("tenant/source")
+	require.NoError(t, err)
+	target, err := server.InitRepository("tenant/target")
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	r, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	w, err := r.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("readme"), 0o600))
+	_, err = w.Add("README.md")
+	require.NoError(t, err)
+	commit, err := w.Commit("initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+	_, err = r.CreateTag("v1.0.0", commit, nil)
+	require.NoError(t, err)
+	_, err = r.CreateRemote(&config.RemoteConfig{
+		Name: git.DefaultRemoteName,
+		URLs: []string{server.URL() + "/tenant/source"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, r.Push(&git.PushOptions{
+		RefSpecs: []config.RefSpec{
+			config.RefSpec(commit.String() + ":refs/heads/main"),
+			config.RefSpec(commit.String() + ":refs/heads/release"),
+			"refs/tags/v1.0.0:refs/tags/v1.0.0",
+		},
+	}))
+
+	require.NoError(t, Import(context.Background(), &pkg.ImportOptions{
+		SourceURL: server.URL() + "/tenant/source",
+		TargetURL: server.URL() + "/tenant/target",
+	}))
+
+	for _, name := range []plumbing.ReferenceName{
+		plumbing.NewBranchReferenceName("main"),
+		plumbing.NewBranchReferenceName("release"),
+		plumbing.NewTagReferenceName("v1.0.0"),
+	} {
+		ref, err := target.Reference(name, true)
+		require.NoError(t, err, name)
+		assert.Equal(t, commit, ref.Hash())
+	}
+
+	// Importing again is a no-op.
+	require.NoError(t, Import(context.Background(), &pkg.ImportOptions{
+		SourceURL: server.URL() + "/tenant/source",
+		TargetURL: server.URL() + "/tenant/target",
+	}))
+}
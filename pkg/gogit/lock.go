@@ -0,0 +1,58 @@
+package gogit
+
+import (
+	"context"
+	"sync"
+)
+
+// keyedMutex serializes work with the same key while work for different keys runs in parallel. The zero value
+// is ready to use.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	ch chan struct{}
+	// refs counts the holder and the waiters, the lock is removed once nobody uses it.
+	refs int
+}
+
+// Lock blocks until the lock for key has been acquired or ctx is done. The returned function releases the lock.
+func (m *keyedMutex) Lock(ctx context.Context, key string) (func(), error) {
+	m.mu.Lock()
+	if m.locks == nil {
+		m.locks = make(map[string]*keyLock)
+	}
+
+	l, ok := m.locks[key]
+	if !ok {
+		l = &keyLock{ch: make(chan struct{}, 1)}
+		m.locks[key] = l
+	}
+
+	l.refs++
+	m.mu.Unlock()
+
+	select {
+	case l.ch <- struct{}{}:
+		return func() {
+			<-l.ch
+			m.release(key, l)
+		}, nil
+	case <-ctx.Done():
+		m.release(key, l)
+
+		return nil, ctx.Err()
+	}
+}
+
+func (m *keyedMutex) release(key string, l *keyLock) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	l.refs--
+	if l.refs == 0 {
+		delete(m.locks, key)
+	}
+}
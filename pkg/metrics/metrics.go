@@ -0,0 +1,107 @@
+// Package metrics defines the Prometheus metrics of the git-controller. They are registered with the
+// controller-runtime registry and served on the manager's metrics endpoint.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "git_controller"
+
+var (
+	// PushDuration observes how long pushing a snapshot takes, including the clone.
+	PushDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "push_duration_seconds",
+		Help:      "Duration of pushing a snapshot to a git repository.",
+		Buckets:   prometheus.ExponentialBuckets(0.25, 2, 10),
+	}, []string{"result"})
+
+	// PushBytes observes the size of the snapshot content written to the repository.
+	PushBytes = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "push_bytes",
+		Help:      "Size of the snapshot content written to a git repository.",
+		Buckets:   prometheus.ExponentialBuckets(1024, 4, 10),
+	})
+
+	// CloneDuration observes how long cloning the target repository takes.
+	CloneDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "clone_duration_seconds",
+		Help:      "Duration of cloning a git repository.",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 10),
+	}, []string{"result"})
+
+	// FilesChanged observes the number of files changed by a commit.
+	FilesChanged = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "commit_files_changed",
+		Help:      "Number of files changed per commit.",
+		Buckets:   prometheus.ExponentialBuckets(1, 4, 8),
+	})
+
+	// PullRequestsOpened counts the pull requests created by Syncs.
+	PullRequestsOpened = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pull_requests_opened_total",
+		Help:      "Number of pull requests opened.",
+	}, []string{"provider"})
+
+	// ProviderRequestDuration observes the latency of provider API calls.
+	ProviderRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "provider_request_duration_seconds",
+		Help:      "Duration of git provider API calls.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"provider", "operation"})
+
+	// ProviderRequestErrors counts failed provider API calls.
+	ProviderRequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "provider_request_errors_total",
+		Help:      "Number of failed git provider API calls.",
+	}, []string{"provider", "operation"})
+
+	// ProviderRateLimitRemaining reports the remaining API quota announced by the last response of a provider.
+	ProviderRateLimitRemaining = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "provider_rate_limit_remaining",
+		Help:      "Remaining API requests of the git provider's rate limit.",
+	}, []string{"provider"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		PushDuration,
+		PushBytes,
+		CloneDuration,
+		FilesChanged,
+		PullRequestsOpened,
+		ProviderRequestDuration,
+		ProviderRequestErrors,
+		ProviderRateLimitRemaining,
+	)
+}
+
+// Result returns the result label for an operation which returned err.
+func Result(err error) string {
+	if err != nil {
+		return "failure"
+	}
+
+	return "success"
+}
+
+// ObserveProviderRequest records the duration of a provider API call started at start and counts it as an error if
+// err is set.
+func ObserveProviderRequest(provider, operation string, start time.Time, err error) {
+	ProviderRequestDuration.WithLabelValues(provider, operation).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		ProviderRequestErrors.WithLabelValues(provider, operation).Inc()
+	}
+}
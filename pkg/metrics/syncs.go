@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	deliveryv1alpha1 "github.com/open-component-model/git-controller/apis/delivery/v1alpha1"
+)
+
+const listTimeout = 5 * time.Second
+
+var syncsDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "syncs"),
+	"Number of Syncs per status of their Ready condition.",
+	[]string{"ready"},
+	nil,
+)
+
+// SyncCollector reports the number of Syncs per status of their Ready condition. The Syncs are counted on every
+// scrape, so the reader should be backed by the manager's cache.
+type SyncCollector struct {
+	reader client.Reader
+}
+
+// NewSyncCollector creates a collector counting the Syncs found through reader.
+func NewSyncCollector(reader client.Reader) *SyncCollector {
+	return &SyncCollector{
+		reader: reader,
+	}
+}
+
+var _ prometheus.Collector = &SyncCollector{}
+
+func (c *SyncCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- syncsDesc
+}
+
+func (c *SyncCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), listTimeout)
+	defer cancel()
+
+	syncs := &deliveryv1alpha1.SyncList{}
+	if err := c.reader.List(ctx, syncs); err != nil {
+		ch <- prometheus.NewInvalidMetric(syncsDesc, err)
+
+		return
+	}
+
+	counts := map[metav1.ConditionStatus]int{
+		metav1.ConditionTrue:    0,
+		metav1.ConditionFalse:   0,
+		metav1.ConditionUnknown: 0,
+	}
+
+	for i := range syncs.Items {
+		ready := metav1.ConditionUnknown
+		if condition := conditions.Get(&syncs.Items[i], meta.ReadyCondition); condition != nil {
+			ready = condition.Status
+		}
+
+		counts[ready]++
+	}
+
+	for ready, count := range counts {
+		ch <- prometheus.MustNewConstMetric(syncsDesc, prometheus.GaugeValue, float64(count), string(ready))
+	}
+}
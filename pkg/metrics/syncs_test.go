@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	deliveryv1alpha1 "github.com/open-component-model/git-controller/apis/delivery/v1alpha1"
+)
+
+func TestSyncCollector(t *testing.T) {
+	ready := &deliveryv1alpha1.Sync{ObjectMeta: metav1.ObjectMeta{Name: "ready", Namespace: "default"}}
+	conditions.MarkTrue(ready, meta.ReadyCondition, meta.SucceededReason, "done")
+	failed := &deliveryv1alpha1.Sync{ObjectMeta: metav1.ObjectMeta{Name: "failed", Namespace: "default"}}
+	conditions.MarkFalse(failed, meta.ReadyCondition, meta.FailedReason, "push failed")
+	pending := &deliveryv1alpha1.Sync{ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "default"}}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, deliveryv1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ready, failed, pending).Build()
+
+	expected := `
+# HELP git_controller_syncs Number of Syncs per status of their Ready condition.
+# TYPE git_controller_syncs gauge
+git_controller_syncs{ready="False"} 1
+git_controller_syncs{ready="True"} 1
+git_controller_syncs{ready="Unknown"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(NewSyncCollector(c), strings.NewReader(expected)))
+}
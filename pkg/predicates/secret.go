@@ -0,0 +1,38 @@
+// Package predicates contains event filters shared by the controllers.
+package predicates
+
+import (
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// SecretDataChangedPredicate passes new Secrets and updates which change the data of a Secret. Changes to the
+// metadata only, like the periodic resync, are filtered out.
+type SecretDataChangedPredicate struct {
+	predicate.Funcs
+}
+
+func (SecretDataChangedPredicate) Update(e event.UpdateEvent) bool {
+	oldSecret, ok := e.ObjectOld.(*corev1.Secret)
+	if !ok {
+		return false
+	}
+
+	newSecret, ok := e.ObjectNew.(*corev1.Secret)
+	if !ok {
+		return false
+	}
+
+	return !reflect.DeepEqual(oldSecret.Data, newSecret.Data) || !reflect.DeepEqual(oldSecret.StringData, newSecret.StringData)
+}
+
+func (SecretDataChangedPredicate) Delete(e event.DeleteEvent) bool {
+	return false
+}
+
+func (SecretDataChangedPredicate) Generic(e event.GenericEvent) bool {
+	return false
+}
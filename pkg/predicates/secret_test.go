@@ -0,0 +1,31 @@
+package predicates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestSecretDataChangedPredicate(t *testing.T) {
+	oldSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "secret", ResourceVersion: "1"},
+		Data:       map[string][]byte{"password": []byte("old")},
+	}
+
+	p := SecretDataChangedPredicate{}
+
+	assert.True(t, p.Create(event.CreateEvent{Object: oldSecret}))
+	assert.False(t, p.Delete(event.DeleteEvent{Object: oldSecret}))
+
+	relabeled := oldSecret.DeepCopy()
+	relabeled.ResourceVersion = "2"
+	relabeled.Labels = map[string]string{"team": "a"}
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: oldSecret, ObjectNew: relabeled}))
+
+	rotated := oldSecret.DeepCopy()
+	rotated.Data["password"] = []byte("new")
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: oldSecret, ObjectNew: rotated}))
+}
@@ -0,0 +1,140 @@
+package providers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	deliveryv1alpha1 "github.com/open-component-model/git-controller/apis/delivery/v1alpha1"
+	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+)
+
+// Names of the branch protection settings as they appear in the Repository spec.
+const (
+	RequiredStatusChecksSetting         = "requiredStatusChecks"
+	StrictStatusChecksSetting           = "strictStatusChecks"
+	RequiredApprovingReviewCountSetting = "requiredApprovingReviewCount"
+	DismissStaleReviewsSetting          = "dismissStaleReviews"
+	EnforceAdminsSetting                = "enforceAdmins"
+	RestrictPushesSetting               = "restrictPushes"
+	RequireLinearHistorySetting         = "requireLinearHistory"
+	RequireSignedCommitsSetting         = "requireSignedCommits"
+)
+
+// UnsupportedSettingsError is returned when a provider applied everything except the listed settings.
+type UnsupportedSettingsError struct {
+	Settings []string
+}
+
+func (e *UnsupportedSettingsError) Error() string {
+	return fmt.Sprintf("settings not supported by provider: %s", strings.Join(e.Settings, ", "))
+}
+
+// BranchProtectionRules returns the branch protection rules of the Repository. Without any rules, the default branch
+// requires the MPAS status check, so pull requests can't be merged before they have been validated.
+func BranchProtectionRules(obj mpasv1alpha1.Repository) []mpasv1alpha1.BranchProtectionRule {
+	if len(obj.Spec.BranchProtection) > 0 {
+		return obj.Spec.BranchProtection
+	}
+
+	branch := obj.Spec.DefaultBranch
+	if branch == "" {
+		branch = DefaultBaseBranch
+	}
+
+	return []mpasv1alpha1.BranchProtectionRule{
+		{
+			Pattern:              branch,
+			RequiredStatusChecks: []string{deliveryv1alpha1.StatusCheckName},
+			StrictStatusChecks:   true,
+		},
+	}
+}
+
+// UnsupportedSettings lists the settings which are configured in rule but are not in supported.
+func UnsupportedSettings(rule mpasv1alpha1.BranchProtectionRule, supported ...string) []string {
+	settings := []struct {
+		name       string
+		configured bool
+	}{
+		{RequiredStatusChecksSetting, len(rule.RequiredStatusChecks) > 0},
+		{StrictStatusChecksSetting, rule.StrictStatusChecks},
+		{RequiredApprovingReviewCountSetting, rule.RequiredApprovingReviewCount > 0},
+		{DismissStaleReviewsSetting, rule.DismissStaleReviews},
+		{EnforceAdminsSetting, rule.EnforceAdmins},
+		{RestrictPushesSetting, rule.RestrictPushes != nil},
+		{RequireLinearHistorySetting, rule.RequireLinearHistory},
+		{RequireSignedCommitsSetting, rule.RequireSignedCommits},
+	}
+
+	var unsupported []string
+
+	for _, setting := range settings {
+		if setting.configured && !slices.Contains(supported, setting.name) {
+			unsupported = append(unsupported, fmt.Sprintf("%s for '%s'", setting.name, rule.Pattern))
+		}
+	}
+
+	return unsupported
+}
+
+// DifferentSettings lists the settings in which the rules differ. Status checks, users and teams are compared
+// ignoring their order.
+func DifferentSettings(a, b mpasv1alpha1.BranchProtectionRule) []string {
+	settings := []struct {
+		name  string
+		equal bool
+	}{
+		{RequiredStatusChecksSetting, sameNames(a.RequiredStatusChecks, b.RequiredStatusChecks)},
+		{StrictStatusChecksSetting, a.StrictStatusChecks == b.StrictStatusChecks},
+		{RequiredApprovingReviewCountSetting, a.RequiredApprovingReviewCount == b.RequiredApprovingReviewCount},
+		{DismissStaleReviewsSetting, a.DismissStaleReviews == b.DismissStaleReviews},
+		{EnforceAdminsSetting, a.EnforceAdmins == b.EnforceAdmins},
+		{RestrictPushesSetting, samePushRestrictions(a.RestrictPushes, b.RestrictPushes)},
+		{RequireLinearHistorySetting, a.RequireLinearHistory == b.RequireLinearHistory},
+		{RequireSignedCommitsSetting, a.RequireSignedCommits == b.RequireSignedCommits},
+	}
+
+	var different []string
+
+	for _, setting := range settings {
+		if !setting.equal {
+			different = append(different, setting.name)
+		}
+	}
+
+	return different
+}
+
+func samePushRestrictions(a, b *mpasv1alpha1.PushRestrictions) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return sameNames(a.Users, b.Users) && sameNames(a.Teams, b.Teams)
+}
+
+// sameNames compares names ignoring their order and case.
+func sameNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	normalize := func(names []string) []string {
+		result := make([]string, 0, len(names))
+		for _, name := range names {
+			result = append(result, strings.ToLower(name))
+		}
+
+		slices.Sort(result)
+
+		return result
+	}
+
+	return slices.Equal(normalize(a), normalize(b))
+}
+
+// HasWildcard returns true if the pattern matches more than a single branch.
+func HasWildcard(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
@@ -0,0 +1,56 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	deliveryv1alpha1 "github.com/open-component-model/git-controller/apis/delivery/v1alpha1"
+	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+)
+
+func TestBranchProtectionRules(t *testing.T) {
+	repository := mpasv1alpha1.Repository{
+		Spec: mpasv1alpha1.RepositorySpec{
+			DefaultBranch: "trunk",
+		},
+	}
+
+	assert.Equal(t, []mpasv1alpha1.BranchProtectionRule{
+		{
+			Pattern:              "trunk",
+			RequiredStatusChecks: []string{deliveryv1alpha1.StatusCheckName},
+			StrictStatusChecks:   true,
+		},
+	}, BranchProtectionRules(repository))
+
+	repository.Spec.BranchProtection = []mpasv1alpha1.BranchProtectionRule{
+		{
+			Pattern:       "release/*",
+			EnforceAdmins: true,
+		},
+	}
+
+	assert.Equal(t, repository.Spec.BranchProtection, BranchProtectionRules(repository))
+}
+
+func TestUnsupportedSettings(t *testing.T) {
+	rule := mpasv1alpha1.BranchProtectionRule{
+		Pattern:                      "main",
+		RequiredStatusChecks:         []string{"ci"},
+		RequiredApprovingReviewCount: 2,
+		EnforceAdmins:                true,
+		RestrictPushes:               &mpasv1alpha1.PushRestrictions{Users: []string{"bot"}},
+	}
+
+	assert.Equal(t, []string{
+		"requiredApprovingReviewCount for 'main'",
+		"enforceAdmins for 'main'",
+	}, UnsupportedSettings(rule, RequiredStatusChecksSetting, RestrictPushesSetting))
+	assert.Empty(t, UnsupportedSettings(rule,
+		RequiredStatusChecksSetting,
+		RequiredApprovingReviewCountSetting,
+		EnforceAdminsSetting,
+		RestrictPushesSetting,
+	))
+}
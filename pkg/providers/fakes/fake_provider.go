@@ -10,35 +10,58 @@ import (
 )
 
 type Provider struct {
-	CreateRepositoryErr         error
-	CreateRepositoryCalledWith  map[int][]any
-	CreateRepositoryCallCount   int
-	CreatePullRequestErr        error
-	CreatePullRequestID         int
-	CreatePullRequestCalledWith map[int][]any
-	CreatePullRequestCallCount  int
+	CreateRepositoryErr                error
+	CreateRepositoryCalledWith         map[int][]any
+	CreateRepositoryCallCount          int
+	RepositoryInfo                     providers.RepositoryInfo
+	CreatePullRequestErr               error
+	PullRequest                        providers.PullRequest
+	CreatePullRequestCalledWith        map[int][]any
+	CreatePullRequestCallCount         int
+	CreateBranchProtectionErr          error
+	CreateBranchProtectionCallCount    int
+	CreateCommitStatusErr              error
+	CreateCommitStatusCalledWith       map[int][]any
+	CreateCommitStatusCallCount        int
+	ArchiveRepositoryErr               error
+	ArchiveRepositoryCallCount         int
+	DeleteRepositoryErr                error
+	DeleteRepositoryCallCount          int
+	RepositorySettings                 providers.RepositorySettings
+	GetRepositorySettingsErr           error
+	UpdateRepositorySettingsErr        error
+	UpdateRepositorySettingsCalledWith []providers.RepositorySettings
+	ReconcilePermissionsErr            error
+	ReconcilePermissionsCallCount      int
+	ReconcileWebhooksErr               error
+	ReconcileWebhooksCalledWith        [][]providers.Webhook
+	DeployKeyID                        string
+	AddDeployKeyErr                    error
+	AddDeployKeyCalledWith             []providers.DeployKey
+	DeleteDeployKeyErr                 error
+	DeleteDeployKeyCalledWith          []string
 }
 
 var _ providers.Provider = &Provider{}
 
-func (p *Provider) CreateRepository(ctx context.Context, obj mpasv1alpha1.Repository) error {
+func (p *Provider) CreateRepository(ctx context.Context, obj mpasv1alpha1.Repository) (providers.RepositoryInfo, error) {
 	if p.CreateRepositoryCalledWith == nil {
 		p.CreateRepositoryCalledWith = make(map[int][]any)
 	}
 	p.CreateRepositoryCalledWith[p.CreateRepositoryCallCount] = append(p.CreateRepositoryCalledWith[p.CreateRepositoryCallCount], obj)
 	p.CreateRepositoryCallCount++
 
-	return p.CreateRepositoryErr
+	return p.RepositoryInfo, p.CreateRepositoryErr
 }
 
-func (p *Provider) CreatePullRequest(ctx context.Context, branch string, sync deliveryv1alpha1.Sync, repository mpasv1alpha1.Repository) (int, error) {
+func (p *Provider) CreatePullRequest(ctx context.Context, branch string, sync deliveryv1alpha1.Sync, repository mpasv1alpha1.Repository) (providers.PullRequest, error) {
 	if p.CreatePullRequestCalledWith == nil {
 		p.CreatePullRequestCalledWith = make(map[int][]any)
 	}
 	p.CreatePullRequestCalledWith[p.CreatePullRequestCallCount] = append(p.CreatePullRequestCalledWith[p.CreatePullRequestCallCount], branch, sync, repository)
 	p.CreatePullRequestCallCount++
 
-	return p.CreatePullRequestID, p.CreatePullRequestErr
+	return p.PullRequest, p.CreatePullRequestErr
 }
 
 func (p *Provider) CreatePullRequestCallArgsForNumber(i int) ([]any, error) {
@@ -60,7 +83,78 @@ func (p *Provider) CreateRepositoryCallArgsForNumber(i int) ([]any, error) {
 }
 
 func (p *Provider) CreateBranchProtection(ctx context.Context, obj mpasv1alpha1.Repository) error {
-	return nil
+	p.CreateBranchProtectionCallCount++
+
+	return p.CreateBranchProtectionErr
+}
+
+func (p *Provider) CreateCommitStatus(ctx context.Context, repository mpasv1alpha1.Repository, pullRequestID int, status deliveryv1alpha1.CommitStatus) error {
+	if p.CreateCommitStatusCalledWith == nil {
+		p.CreateCommitStatusCalledWith = make(map[int][]any)
+	}
+	p.CreateCommitStatusCalledWith[p.CreateCommitStatusCallCount] = append(p.CreateCommitStatusCalledWith[p.CreateCommitStatusCallCount], repository, pullRequestID, status)
+	p.CreateCommitStatusCallCount++
+
+	return p.CreateCommitStatusErr
+}
+
+func (p *Provider) CreateCommitStatusCallArgsForNumber(i int) ([]any, error) {
+	args, ok := p.CreateCommitStatusCalledWith[i]
+	if !ok {
+		return nil, fmt.Errorf("arguments for cal number %d not found", i)
+	}
+
+	return args, nil
+}
+
+func (p *Provider) ArchiveRepository(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	p.ArchiveRepositoryCallCount++
+
+	return p.ArchiveRepositoryErr
+}
+
+func (p *Provider) DeleteRepository(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	p.DeleteRepositoryCallCount++
+
+	return p.DeleteRepositoryErr
+}
+
+func (p *Provider) GetRepositorySettings(ctx context.Context, obj mpasv1alpha1.Repository) (providers.RepositorySettings, error) {
+	return p.RepositorySettings, p.GetRepositorySettingsErr
+}
+
+func (p *Provider) UpdateRepositorySettings(ctx context.Context, obj mpasv1alpha1.Repository, settings providers.RepositorySettings) error {
+	p.UpdateRepositorySettingsCalledWith = append(p.UpdateRepositorySettingsCalledWith, settings)
+
+	return p.UpdateRepositorySettingsErr
+}
+
+func (p *Provider) ReconcilePermissions(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	p.ReconcilePermissionsCallCount++
+
+	return p.ReconcilePermissionsErr
+}
+
+func (p *Provider) ReconcileWebhooks(ctx context.Context, obj mpasv1alpha1.Repository, hooks []providers.Webhook) error {
+	p.ReconcileWebhooksCalledWith = append(p.ReconcileWebhooksCalledWith, hooks)
+
+	return p.ReconcileWebhooksErr
+}
+
+func (p *Provider) AddDeployKey(ctx context.Context, obj mpasv1alpha1.Repository, key providers.DeployKey) (string, error) {
+	p.AddDeployKeyCalledWith = append(p.AddDeployKeyCalledWith, key)
+
+	return p.DeployKeyID, p.AddDeployKeyErr
+}
+
+func (p *Provider) DeleteDeployKey(ctx context.Context, obj mpasv1alpha1.Repository, id string) error {
+	p.DeleteDeployKeyCalledWith = append(p.DeleteDeployKeyCalledWith, id)
+
+	return p.DeleteDeployKeyErr
+}
+
+func (p *Provider) CommitURL(repository mpasv1alpha1.Repository, commit string) string {
+	return providers.JoinWebURL(repository, "commit", commit)
 }
 
 func NewProvider() *Provider {
@@ -1,12 +1,14 @@
 package gitea
 
 import (
-	"bytes"
 	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 
 	"code.gitea.io/sdk/gitea"
 	deliveryv1alpha1 "github.com/open-component-model/git-controller/apis/delivery/v1alpha1"
@@ -18,57 +20,48 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// ProviderType is the name under which this provider is registered.
+const ProviderType = "gitea"
+
 const (
-	tokenKey     = "password"
-	providerType = "gitea"
+	tokenKey = "password"
 )
 
 // Client gitea.
 type Client struct {
-	client client.Client
-	next   providers.Provider
+	client  client.Client
+	content providers.ContentLoader
 }
 
-// NewClient creates a new Gitea client.
-func NewClient(client client.Client, next providers.Provider) *Client {
+// NewClient creates a new Gitea client. The content loader provides the files of newly created repositories.
+func NewClient(client client.Client, content providers.ContentLoader) *Client {
 	return &Client{
-		client: client,
-		next:   next,
+		client:  client,
+		content: content,
 	}
 }
 
 var _ providers.Provider = &Client{}
 
-func (c *Client) CreateRepository(ctx context.Context, obj mpasv1alpha1.Repository) error {
-	if obj.Spec.Provider != providerType {
-		if c.next == nil {
-			return fmt.Errorf("can't handle provider type '%s' and no next provider is configured", obj.Spec.Provider)
-		}
-
-		return c.next.CreateRepository(ctx, obj)
+func (c *Client) CreateRepository(ctx context.Context, obj mpasv1alpha1.Repository) (providers.RepositoryInfo, error) {
+	client, err := c.newClient(ctx, obj)
+	if err != nil {
+		return providers.RepositoryInfo{}, err
 	}
 
-	secret := &v1.Secret{}
-	if err := c.client.Get(ctx, types.NamespacedName{
-		Name:      obj.Spec.Credentials.SecretRef.Name,
-		Namespace: obj.Namespace,
-	}, secret); err != nil {
-		return fmt.Errorf("failed to get secret: %w", err)
+	existing, resp, err := client.GetRepo(obj.Spec.Owner, obj.GetName())
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return providers.RepositoryInfo{}, fmt.Errorf("failed to get repository: %w", err)
 	}
 
-	token, ok := secret.Data[tokenKey]
-	if !ok {
-		return fmt.Errorf("token '%s' not found in secret", tokenKey)
-	}
+	if existing != nil && err == nil {
+		if obj.Spec.ExistingRepositoryPolicy == mpasv1alpha1.ExistingRepositoryPolicyFail {
+			return providers.RepositoryInfo{}, fmt.Errorf("repository '%s/%s' already exists", obj.Spec.Owner, obj.GetName())
+		}
 
-	domain, err := c.getDomain(obj)
-	if err != nil {
-		return fmt.Errorf("failed to generate domain url: %w", err)
-	}
+		log.FromContext(ctx).Info("using existing repository", "repository", obj.GetName())
 
-	client, err := gitea.NewClient(domain, gitea.SetToken(string(token)))
-	if err != nil {
-		return fmt.Errorf("failed to create gitea client: %w", err)
+		return repositoryInfo(existing, false), nil
 	}
 
 	private := true
@@ -76,44 +69,98 @@ func (c *Client) CreateRepository(ctx context.Context, obj mpasv1alpha1.Reposito
 		private = false
 	}
 
-	if _, _, err := client.CreateRepo(gitea.CreateRepoOption{
+	if obj.Spec.Source != nil {
+		return c.importRepository(ctx, client, obj, private)
+	}
+
+	repo, _, err := client.CreateRepo(gitea.CreateRepoOption{
 		Name:          obj.GetName(),
-		Description:   "Created by git-controller",
+		Description:   obj.Spec.Description,
 		Private:       private,
 		AutoInit:      true,
-		DefaultBranch: "main",
+		DefaultBranch: obj.Spec.DefaultBranch,
 		TrustModel:    gitea.TrustModelDefault,
-	}); err != nil {
-		return fmt.Errorf("failed to create repositroy: %w", err)
+	})
+	if err != nil {
+		return providers.RepositoryInfo{}, fmt.Errorf("failed to create repositroy: %w", err)
+	}
+
+	files, err := c.content.Load(ctx, obj)
+	if err != nil {
+		if _, derr := client.DeleteRepo(obj.Spec.Owner, obj.GetName()); derr != nil {
+			err = errors.Join(err, derr)
+		}
+
+		return providers.RepositoryInfo{}, fmt.Errorf("failed to load initial content: %w", err)
 	}
 
 	f := &fileCommitter{}
 
-	if len(obj.Spec.Maintainers) != 0 {
-		var content []byte
-		buffer := bytes.NewBuffer(content)
+	for _, file := range files {
+		f.commitFile(client, obj, file.Path, base64.StdEncoding.EncodeToString(file.Content))
+	}
 
-		for _, m := range obj.Spec.Maintainers {
-			if _, err := buffer.WriteString(fmt.Sprintf("%s\n", m)); err != nil {
-				return fmt.Errorf("failed to write content to buffer: %w", err)
-			}
-		}
+	if f.err != nil {
+		return providers.RepositoryInfo{}, fmt.Errorf("failed to set up project folder structure: %w", f.err)
+	}
 
-		encoded := base64.StdEncoding.EncodeToString(buffer.Bytes())
+	return repositoryInfo(repo, true), nil
+}
 
-		f.commitFile(client, obj, "CODEOWNERS", encoded)
+// importRepository creates the repository through Gitea's migration, optionally as a pull mirror of the source.
+func (c *Client) importRepository(ctx context.Context, client *gitea.Client, obj mpasv1alpha1.Repository, private bool) (providers.RepositoryInfo, error) {
+	auth, err := providers.SourceAuth(ctx, c.client, obj)
+	if err != nil {
+		return providers.RepositoryInfo{}, err
 	}
 
-	f.commitFile(client, obj, "generators/.keep", "")
-	f.commitFile(client, obj, "products/.keep", "")
-	f.commitFile(client, obj, "subscriptions/.keep", "")
-	f.commitFile(client, obj, "targets/.keep", "")
+	username, password, err := providers.MigrationCredentials(auth)
+	if err != nil {
+		return providers.RepositoryInfo{}, err
+	}
 
-	if f.err != nil {
-		return fmt.Errorf("failed to set up project folder structure: %w", f.err)
+	opts := gitea.MigrateRepoOption{
+		RepoName:     obj.GetName(),
+		RepoOwner:    obj.Spec.Owner,
+		CloneAddr:    obj.Spec.Source.URL,
+		Service:      gitea.GitServicePlain,
+		AuthUsername: username,
+		AuthPassword: password,
+		Mirror:       obj.Spec.Source.Mirror,
+		Private:      private,
+		Description:  obj.Spec.Description,
 	}
 
-	return nil
+	if obj.Spec.Source.Mirror && obj.Spec.Source.MirrorInterval != nil {
+		opts.MirrorInterval = obj.Spec.Source.MirrorInterval.Duration.String()
+	}
+
+	repo, _, err := client.MigrateRepo(opts)
+	if err != nil {
+		return providers.RepositoryInfo{}, fmt.Errorf("failed to migrate repository: %w", err)
+	}
+
+	log.FromContext(ctx).Info("successfully imported repository", "repository", obj.GetName(), "source", obj.Spec.Source.URL)
+
+	return repositoryInfo(repo, true), nil
+}
+
+// repositoryInfo converts a Gitea repository into the provider independent RepositoryInfo.
+func repositoryInfo(repo *gitea.Repository, created bool) providers.RepositoryInfo {
+	visibility := "public"
+	if repo.Private {
+		visibility = "private"
+	}
+
+	return providers.RepositoryInfo{
+		ID:            strconv.FormatInt(repo.ID, 10),
+		HTTPSURL:      repo.CloneURL,
+		SSHURL:        repo.SSHURL,
+		WebURL:        repo.HTMLURL,
+		DefaultBranch: repo.DefaultBranch,
+		Visibility:    visibility,
+		Created:       created,
+	}
 }
 
 type fileCommitter struct {
@@ -141,36 +188,10 @@ func (f *fileCommitter) commitFile(client *gitea.Client, obj mpasv1alpha1.Reposi
 	}
 }
 
-func (c *Client) CreatePullRequest(ctx context.Context, branch string, sync deliveryv1alpha1.Sync, repository mpasv1alpha1.Repository) (int, error) {
-	if repository.Spec.Provider != providerType {
-		if c.next == nil {
-			return -1, fmt.Errorf("can't handle provider type '%s' and no next provider is configured", repository.Spec.Provider)
-		}
-
-		return c.next.CreatePullRequest(ctx, branch, sync, repository)
-	}
-
-	secret := &v1.Secret{}
-	if err := c.client.Get(ctx, types.NamespacedName{
-		Name:      repository.Spec.Credentials.SecretRef.Name,
-		Namespace: repository.Namespace,
-	}, secret); err != nil {
-		return -1, fmt.Errorf("failed to get secret: %w", err)
-	}
-
-	token, ok := secret.Data[tokenKey]
-	if !ok {
-		return -1, fmt.Errorf("token '%s' not found in secret", tokenKey)
-	}
-
-	domain, err := c.getDomain(repository)
+func (c *Client) CreatePullRequest(ctx context.Context, branch string, sync deliveryv1alpha1.Sync, repository mpasv1alpha1.Repository) (providers.PullRequest, error) {
+	gclient, err := c.newClient(ctx, repository)
 	if err != nil {
-		return -1, fmt.Errorf("failed to generate domain url: %w", err)
-	}
-
-	gclient, err := gitea.NewClient(domain, gitea.SetToken(string(token)))
-	if err != nil {
-		return -1, fmt.Errorf("failed to create gitea client: %w", err)
+		return providers.PullRequest{}, err
 	}
 
 	var (
@@ -198,73 +219,572 @@ func (c *Client) CreatePullRequest(ctx context.Context, branch string, sync deli
 		Body:  description,
 	})
 	if err != nil {
-		return -1, fmt.Errorf("failed to create pull request: %w", err)
+		return providers.PullRequest{}, fmt.Errorf("failed to create pull request: %w", err)
 	}
 
-	return int(pr.ID), nil
+	return providers.PullRequest{ID: int(pr.Index), URL: pr.HTMLURL}, nil
 }
 
+// CreateBranchProtection creates or updates a branch protection for every rule of the Repository.
 func (c *Client) CreateBranchProtection(ctx context.Context, repository mpasv1alpha1.Repository) error {
 	logger := log.FromContext(ctx)
 
-	logger.Info("using gitea provider to set up branch protection")
+	gclient, err := c.newClient(ctx, repository)
+	if err != nil {
+		return err
+	}
 
-	if repository.Spec.Provider != providerType {
-		if c.next == nil {
-			return fmt.Errorf("can't handle provider type '%s' and no next provider is configured", repository.Spec.Provider)
+	var unsupported []string
+
+	for _, rule := range providers.BranchProtectionRules(repository) {
+		unsupported = append(unsupported, providers.UnsupportedSettings(
+			rule,
+			providers.RequiredStatusChecksSetting,
+			providers.StrictStatusChecksSetting,
+			providers.RequiredApprovingReviewCountSetting,
+			providers.DismissStaleReviewsSetting,
+			providers.RestrictPushesSetting,
+			providers.RequireSignedCommitsSetting,
+		)...)
+
+		opts := branchProtectionOption(rule)
+
+		_, resp, err := gclient.GetBranchProtection(repository.Spec.Owner, repository.Name, rule.Pattern)
+		if err != nil {
+			if resp == nil || resp.StatusCode != http.StatusNotFound {
+				return fmt.Errorf("failed to get branch protection for '%s': %w", rule.Pattern, err)
+			}
+
+			logger.Info("creating branch protection", "pattern", rule.Pattern)
+
+			if _, _, err := gclient.CreateBranchProtection(repository.Spec.Owner, repository.Name, opts); err != nil {
+				return fmt.Errorf("failed to create branch protection for '%s': %w", rule.Pattern, err)
+			}
+
+			continue
 		}
 
-		return c.next.CreateBranchProtection(ctx, repository)
+		logger.Info("updating branch protection", "pattern", rule.Pattern)
+
+		if _, _, err := gclient.EditBranchProtection(repository.Spec.Owner, repository.Name, rule.Pattern, gitea.EditBranchProtectionOption{
+			EnablePush:             &opts.EnablePush,
+			EnablePushWhitelist:    &opts.EnablePushWhitelist,
+			PushWhitelistUsernames: opts.PushWhitelistUsernames,
+			PushWhitelistTeams:     opts.PushWhitelistTeams,
+			EnableStatusCheck:      &opts.EnableStatusCheck,
+			StatusCheckContexts:    opts.StatusCheckContexts,
+			RequiredApprovals:      &opts.RequiredApprovals,
+			BlockOnOutdatedBranch:  &opts.BlockOnOutdatedBranch,
+			DismissStaleApprovals:  &opts.DismissStaleApprovals,
+			RequireSignedCommits:   &opts.RequireSignedCommits,
+		}); err != nil {
+			return fmt.Errorf("failed to update branch protection for '%s': %w", rule.Pattern, err)
+		}
 	}
 
-	//nolint:godox // ignore todo
-	// TODO: use safe auth strategy post MVP
-	secret := &v1.Secret{}
-	if err := c.client.Get(ctx, types.NamespacedName{
-		Name:      repository.Spec.Credentials.SecretRef.Name,
-		Namespace: repository.Namespace,
-	}, secret); err != nil {
-		return fmt.Errorf("failed to get secret: %w", err)
+	if len(unsupported) > 0 {
+		return &providers.UnsupportedSettingsError{Settings: unsupported}
 	}
 
-	token, ok := secret.Data[tokenKey]
-	if !ok {
-		return fmt.Errorf("token '%s' not found in secret", tokenKey)
+	return nil
+}
+
+// branchProtectionOption translates a rule into Gitea's branch protection settings.
+func branchProtectionOption(rule mpasv1alpha1.BranchProtectionRule) gitea.CreateBranchProtectionOption {
+	opts := gitea.CreateBranchProtectionOption{
+		BranchName:            rule.Pattern,
+		EnablePush:            true,
+		EnableStatusCheck:     len(rule.RequiredStatusChecks) > 0,
+		StatusCheckContexts:   rule.RequiredStatusChecks,
+		RequiredApprovals:     int64(rule.RequiredApprovingReviewCount),
+		BlockOnOutdatedBranch: rule.StrictStatusChecks,
+		DismissStaleApprovals: rule.DismissStaleReviews,
+		RequireSignedCommits:  rule.RequireSignedCommits,
 	}
 
-	logger.Info("got secret")
+	if rule.RestrictPushes != nil {
+		opts.EnablePushWhitelist = true
+		opts.PushWhitelistUsernames = rule.RestrictPushes.Users
+		opts.PushWhitelistTeams = rule.RestrictPushes.Teams
+	}
 
-	domain, err := c.getDomain(repository)
-	if err != nil {
-		return fmt.Errorf("failed to generate domain url: %w", err)
+	return opts
+}
+
+// protectionRule translates Gitea's branch protection settings back into a rule. Gitea has no equivalent to
+// enforceAdmins and requireLinearHistory, so they are taken from the rule and never drift.
+func protectionRule(rule mpasv1alpha1.BranchProtectionRule, protection *gitea.BranchProtection) *mpasv1alpha1.BranchProtectionRule {
+	actual := rule
+	actual.RequiredStatusChecks = nil
+	actual.StrictStatusChecks = protection.BlockOnOutdatedBranch
+	actual.RequiredApprovingReviewCount = int(protection.RequiredApprovals)
+	actual.DismissStaleReviews = protection.DismissStaleApprovals
+	actual.RequireSignedCommits = protection.RequireSignedCommits
+	actual.RestrictPushes = nil
+
+	if protection.EnableStatusCheck {
+		actual.RequiredStatusChecks = protection.StatusCheckContexts
 	}
 
-	logger.Info("default domain set", "domain", domain)
+	if protection.EnablePushWhitelist {
+		actual.RestrictPushes = &mpasv1alpha1.PushRestrictions{
+			Users: protection.PushWhitelistUsernames,
+			Teams: protection.PushWhitelistTeams,
+		}
+	}
+
+	return &actual
+}
+
+func (c *Client) CreateCommitStatus(ctx context.Context, repository mpasv1alpha1.Repository, pullRequestID int, status deliveryv1alpha1.CommitStatus) error {
+	gclient, err := c.newClient(ctx, repository)
+	if err != nil {
+		return err
+	}
 
-	gclient, err := gitea.NewClient(domain, gitea.SetToken(string(token)))
+	pr, _, err := gclient.GetPullRequest(repository.Spec.Owner, repository.GetName(), int64(pullRequestID))
 	if err != nil {
-		return fmt.Errorf("failed to create gitea client: %w", err)
+		return fmt.Errorf("failed to find pull request: %w", err)
 	}
 
-	defaultBranch := "main"
-	if repository.Spec.DefaultBranch != "" {
-		defaultBranch = repository.Spec.DefaultBranch
+	if _, _, err := gclient.CreateStatus(repository.Spec.Owner, repository.GetName(), pr.Head.Sha, gitea.CreateStatusOption{
+		State:       gitea.StatusState(status.Spec.State),
+		TargetURL:   status.Spec.TargetURL,
+		Description: status.Spec.Description,
+		Context:     status.GetContext(),
+	}); err != nil {
+		return fmt.Errorf("failed to create commit status: %w", err)
 	}
 
-	logger.Info("using default branch", "branch", defaultBranch)
+	return nil
+}
+
+func (c *Client) ArchiveRepository(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	gclient, err := c.newClient(ctx, obj)
+	if err != nil {
+		return err
+	}
 
-	if _, _, err := gclient.CreateBranchProtection(repository.Spec.Owner, repository.Name, gitea.CreateBranchProtectionOption{
-		BranchName:          defaultBranch,
-		EnablePush:          true,
-		EnableStatusCheck:   true,
-		StatusCheckContexts: []string{deliveryv1alpha1.StatusCheckName},
+	archived := true
+	if _, resp, err := gclient.EditRepo(obj.Spec.Owner, obj.GetName(), gitea.EditRepoOption{
+		Archived: &archived,
 	}); err != nil {
-		return fmt.Errorf("failed to create branch protection: %w", err)
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil
+		}
+
+		return fmt.Errorf("failed to archive repository: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) DeleteRepository(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	gclient, err := c.newClient(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	if resp, err := gclient.DeleteRepo(obj.Spec.Owner, obj.GetName()); err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil
+		}
+
+		return fmt.Errorf("failed to delete repository: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) GetRepositorySettings(ctx context.Context, obj mpasv1alpha1.Repository) (providers.RepositorySettings, error) {
+	gclient, err := c.newClient(ctx, obj)
+	if err != nil {
+		return providers.RepositorySettings{}, err
+	}
+
+	repo, _, err := gclient.GetRepo(obj.Spec.Owner, obj.GetName())
+	if err != nil {
+		return providers.RepositorySettings{}, fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	visibility := "public"
+	if repo.Private {
+		visibility = "private"
+	}
+
+	topics, _, err := gclient.ListRepoTopics(obj.Spec.Owner, obj.GetName(), gitea.ListRepoTopicsOptions{})
+	if err != nil {
+		return providers.RepositorySettings{}, fmt.Errorf("failed to get topics: %w", err)
+	}
+
+	if topics == nil {
+		topics = []string{}
+	}
+
+	settings := providers.RepositorySettings{
+		Visibility:       visibility,
+		DefaultBranch:    repo.DefaultBranch,
+		Description:      repo.Description,
+		Homepage:         repo.Website,
+		Topics:           topics,
+		HasIssues:        &repo.HasIssues,
+		HasWiki:          &repo.HasWiki,
+		HasProjects:      &repo.HasProjects,
+		AllowMergeCommit: &repo.AllowMerge,
+		AllowSquashMerge: &repo.AllowSquash,
+		AllowRebaseMerge: &repo.AllowRebase,
+	}
+
+	settings.BranchProtection = map[string]*mpasv1alpha1.BranchProtectionRule{}
+
+	for _, rule := range providers.BranchProtectionRules(obj) {
+		protection, resp, err := gclient.GetBranchProtection(obj.Spec.Owner, obj.GetName(), rule.Pattern)
+		if err != nil {
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				settings.BranchProtection[rule.Pattern] = nil
+
+				continue
+			}
+
+			return providers.RepositorySettings{}, fmt.Errorf("failed to get branch protection for '%s': %w", rule.Pattern, err)
+		}
+
+		settings.BranchProtection[rule.Pattern] = protectionRule(rule, protection)
+	}
+
+	return settings, nil
+}
+
+func (c *Client) UpdateRepositorySettings(ctx context.Context, obj mpasv1alpha1.Repository, settings providers.RepositorySettings) error {
+	gclient, err := c.newClient(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	opts := gitea.EditRepoOption{}
+
+	if settings.Visibility != "" {
+		private := settings.Visibility != "public"
+		opts.Private = &private
+	}
+
+	if settings.DefaultBranch != "" {
+		opts.DefaultBranch = &settings.DefaultBranch
+	}
+
+	if settings.Description != "" {
+		opts.Description = &settings.Description
+	}
+
+	if settings.Homepage != "" {
+		opts.Website = &settings.Homepage
+	}
+
+	opts.HasIssues = settings.HasIssues
+	opts.HasWiki = settings.HasWiki
+	opts.HasProjects = settings.HasProjects
+	opts.AllowMerge = settings.AllowMergeCommit
+	opts.AllowSquash = settings.AllowSquashMerge
+	opts.AllowRebase = settings.AllowRebaseMerge
+
+	if _, _, err := gclient.EditRepo(obj.Spec.Owner, obj.GetName(), opts); err != nil {
+		return fmt.Errorf("failed to update repository: %w", err)
+	}
+
+	if settings.Topics != nil {
+		if _, err := gclient.SetRepoTopics(obj.Spec.Owner, obj.GetName(), settings.Topics); err != nil {
+			return fmt.Errorf("failed to update topics: %w", err)
+		}
 	}
 
 	return nil
 }
 
+// giteaAccessModes maps roles to Gitea's access modes, which have no triage or maintain level.
+var giteaAccessModes = map[mpasv1alpha1.Role]gitea.AccessMode{
+	mpasv1alpha1.RoleRead:     gitea.AccessModeRead,
+	mpasv1alpha1.RoleTriage:   gitea.AccessModeRead,
+	mpasv1alpha1.RoleWrite:    gitea.AccessModeWrite,
+	mpasv1alpha1.RoleMaintain: gitea.AccessModeWrite,
+	mpasv1alpha1.RoleAdmin:    gitea.AccessModeAdmin,
+}
+
+// ReconcilePermissions manages collaborators and organization teams. Gitea doesn't report the access mode of a
+// collaborator, so declared users are granted their role on every reconciliation. Teams are granted the permission
+// configured on the team itself, their declared role is ignored.
+func (c *Client) ReconcilePermissions(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	if obj.Spec.Permissions == nil {
+		return nil
+	}
+
+	gclient, err := c.newClient(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	if err := reconcileCollaborators(gclient, obj); err != nil {
+		return err
+	}
+
+	return reconcileTeams(gclient, obj)
+}
+
+func reconcileCollaborators(gclient *gitea.Client, obj mpasv1alpha1.Repository) error {
+	const pageSize = 50
+
+	current := map[string]string{}
+
+	for page := 1; ; page++ {
+		users, _, err := gclient.ListCollaborators(obj.Spec.Owner, obj.GetName(), gitea.ListCollaboratorsOptions{
+			ListOptions: gitea.ListOptions{Page: page, PageSize: pageSize},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list collaborators: %w", err)
+		}
+
+		for _, user := range users {
+			current[strings.ToLower(user.UserName)] = ""
+		}
+
+		if len(users) < pageSize {
+			break
+		}
+	}
+
+	self, _, err := gclient.GetMyUserInfo()
+	if err != nil {
+		return fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	desired := make(map[string]string, len(obj.Spec.Permissions.Users))
+	for _, p := range obj.Spec.Permissions.Users {
+		desired[strings.ToLower(p.Name)] = string(giteaAccessModes[p.Role])
+	}
+
+	grant, revoke := providers.DiffPermissions(desired, current, obj.Spec.Permissions.Prune, self.UserName, obj.Spec.Owner)
+
+	for _, name := range grant {
+		mode := gitea.AccessMode(desired[name])
+		if _, err := gclient.AddCollaborator(obj.Spec.Owner, obj.GetName(), name, gitea.AddCollaboratorOption{
+			Permission: &mode,
+		}); err != nil {
+			return fmt.Errorf("failed to add collaborator '%s': %w", name, err)
+		}
+	}
+
+	for _, name := range revoke {
+		if _, err := gclient.DeleteCollaborator(obj.Spec.Owner, obj.GetName(), name); err != nil {
+			return fmt.Errorf("failed to remove collaborator '%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func reconcileTeams(gclient *gitea.Client, obj mpasv1alpha1.Repository) error {
+	teams, resp, err := gclient.GetRepoTeams(obj.Spec.Owner, obj.GetName())
+	if err != nil {
+		// Repositories owned by users have no teams.
+		if resp != nil && resp.StatusCode == http.StatusNotFound && len(obj.Spec.Permissions.Teams) == 0 {
+			return nil
+		}
+
+		return fmt.Errorf("failed to list teams: %w", err)
+	}
+
+	current := map[string]string{}
+	for _, team := range teams {
+		current[strings.ToLower(team.Name)] = ""
+	}
+
+	desired := make(map[string]string, len(obj.Spec.Permissions.Teams))
+	for _, p := range obj.Spec.Permissions.Teams {
+		desired[strings.ToLower(p.Name)] = ""
+	}
+
+	grant, revoke := providers.DiffPermissions(desired, current, obj.Spec.Permissions.Prune)
+
+	for _, name := range grant {
+		if _, err := gclient.AddRepoTeam(obj.Spec.Owner, obj.GetName(), name); err != nil {
+			return fmt.Errorf("failed to add team '%s': %w", name, err)
+		}
+	}
+
+	for _, name := range revoke {
+		if _, err := gclient.RemoveRepoTeam(obj.Spec.Owner, obj.GetName(), name); err != nil {
+			return fmt.Errorf("failed to remove team '%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// giteaEvents maps webhook events to Gitea's event names.
+var giteaEvents = map[mpasv1alpha1.WebhookEvent]string{
+	mpasv1alpha1.WebhookEventPush:        "push",
+	mpasv1alpha1.WebhookEventPullRequest: "pull_request",
+	mpasv1alpha1.WebhookEventTag:         "create",
+	mpasv1alpha1.WebhookEventRelease:     "release",
+	mpasv1alpha1.WebhookEventIssues:      "issues",
+}
+
+// ReconcileWebhooks creates missing webhooks and updates existing ones. Gitea doesn't return the secret of a
+// webhook, so existing webhooks are always updated to pick up a changed secret.
+func (c *Client) ReconcileWebhooks(ctx context.Context, obj mpasv1alpha1.Repository, hooks []providers.Webhook) error {
+	const pageSize = 50
+
+	gclient, err := c.newClient(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	existing := map[string]int64{}
+
+	for page := 1; ; page++ {
+		list, _, err := gclient.ListRepoHooks(obj.Spec.Owner, obj.GetName(), gitea.ListHooksOptions{
+			ListOptions: gitea.ListOptions{Page: page, PageSize: pageSize},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list webhooks: %w", err)
+		}
+
+		for _, hook := range list {
+			existing[hook.Config["url"]] = hook.ID
+		}
+
+		if len(list) < pageSize {
+			break
+		}
+	}
+
+	for _, hook := range hooks {
+		var events []string
+		for _, event := range providers.WebhookEvents(hook) {
+			events = append(events, giteaEvents[event])
+		}
+
+		config := map[string]string{
+			"url":          hook.URL,
+			"content_type": hook.ContentType,
+			"secret":       hook.Secret,
+		}
+
+		if id, ok := existing[hook.URL]; ok {
+			active := true
+			if _, err := gclient.EditRepoHook(obj.Spec.Owner, obj.GetName(), id, gitea.EditHookOption{
+				Config: config,
+				Events: events,
+				Active: &active,
+			}); err != nil {
+				return fmt.Errorf("failed to update webhook '%s': %w", hook.URL, err)
+			}
+
+			continue
+		}
+
+		if _, _, err := gclient.CreateRepoHook(obj.Spec.Owner, obj.GetName(), gitea.CreateHookOption{
+			Type:   gitea.HookTypeGitea,
+			Config: config,
+			Events: events,
+			Active: true,
+		}); err != nil {
+			return fmt.Errorf("failed to create webhook '%s': %w", hook.URL, err)
+		}
+	}
+
+	for _, url := range providers.StaleWebhooks(hooks, obj.Status.Webhooks) {
+		id, ok := existing[url]
+		if !ok {
+			continue
+		}
+
+		if _, err := gclient.DeleteRepoHook(obj.Spec.Owner, obj.GetName(), id); err != nil {
+			return fmt.Errorf("failed to delete webhook '%s': %w", url, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) AddDeployKey(ctx context.Context, obj mpasv1alpha1.Repository, key providers.DeployKey) (string, error) {
+	gclient, err := c.newClient(ctx, obj)
+	if err != nil {
+		return "", err
+	}
+
+	created, _, err := gclient.CreateDeployKey(obj.Spec.Owner, obj.GetName(), gitea.CreateKeyOption{
+		Title:    key.Title,
+		Key:      strings.TrimSpace(string(key.PublicKey)),
+		ReadOnly: key.ReadOnly,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create deploy key: %w", err)
+	}
+
+	return strconv.FormatInt(created.ID, 10), nil
+}
+
+func (c *Client) DeleteDeployKey(ctx context.Context, obj mpasv1alpha1.Repository, id string) error {
+	keyID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid deploy key id '%s': %w", id, err)
+	}
+
+	gclient, err := c.newClient(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	if resp, err := gclient.DeleteDeployKey(obj.Spec.Owner, obj.GetName(), keyID); err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil
+		}
+
+		return fmt.Errorf("failed to delete deploy key: %w", err)
+	}
+
+	return nil
+}
+
+// CommitURL links to the commit on Gitea.
+func (c *Client) CommitURL(repository mpasv1alpha1.Repository, commit string) string {
+	return providers.JoinWebURL(repository, "commit", commit)
+}
+
+// newClient creates a gitea client using the token from the Repository's secret.
+func (c *Client) newClient(ctx context.Context, obj mpasv1alpha1.Repository) (*gitea.Client, error) {
+	secret := &v1.Secret{}
+	if err := c.client.Get(ctx, types.NamespacedName{
+		Name:      obj.Spec.Credentials.SecretRef.Name,
+		Namespace: obj.Namespace,
+	}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	token, ok := secret.Data[tokenKey]
+	if !ok {
+		return nil, fmt.Errorf("token '%s' not found in secret", tokenKey)
+	}
+
+	domain, err := c.getDomain(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate domain url: %w", err)
+	}
+
+	client, err := gitea.NewClient(
+		domain,
+		gitea.SetToken(string(token)),
+		gitea.SetHTTPClient(&http.Client{
+			Transport: providers.NewRateLimitTransport(ProviderType, http.DefaultTransport),
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gitea client: %w", err)
+	}
+
+	return client, nil
+}
+
 func (c *Client) getDomain(obj mpasv1alpha1.Repository) (string, error) {
 	u, err := url.Parse(obj.GetRepositoryURL())
 	if err != nil {
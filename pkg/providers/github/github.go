@@ -2,7 +2,11 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/fluxcd/go-git-providers/github"
 	"github.com/fluxcd/go-git-providers/gitprovider"
@@ -11,47 +15,53 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	deliveryv1alpha1 "github.com/open-component-model/git-controller/apis/delivery/v1alpha1"
 	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+	"github.com/open-component-model/git-controller/pkg"
+	pkggogit "github.com/open-component-model/git-controller/pkg/gogit"
 	"github.com/open-component-model/git-controller/pkg/providers"
 	"github.com/open-component-model/git-controller/pkg/providers/gogit"
 )
 
+// ProviderType is the name under which this provider is registered.
+const ProviderType = "github"
+
 const (
 	tokenKey      = "password"
-	providerType  = "github"
 	defaultDomain = github.DefaultDomain
 )
 
+// rateLimitHook detects rate limiting in clients created through go-git-providers.
+var rateLimitHook = gitprovider.WithPreChainTransportHook(func(in http.RoundTripper) http.RoundTripper {
+	return providers.NewRateLimitTransport(ProviderType, in)
+})
+
 // Client github.
 type Client struct {
-	client client.Client
-	next   providers.Provider
+	client  client.Client
+	content providers.ContentLoader
 }
 
-// NewClient creates a new GitHub client.
-func NewClient(client client.Client, next providers.Provider) *Client {
+// NewClient creates a new GitHub client. The content loader provides the files of newly created repositories.
+func NewClient(client client.Client, content providers.ContentLoader) *Client {
 	return &Client{
-		client: client,
-		next:   next,
+		client:  client,
+		content: content,
 	}
 }
 
 var _ providers.Provider = &Client{}
 
-func (c *Client) CreateRepository(ctx context.Context, obj mpasv1alpha1.Repository) error {
-	if obj.Spec.Provider != providerType {
-		if c.next == nil {
-			return fmt.Errorf("can't handle provider type '%s' and no next provider is configured", obj.Spec.Provider)
-		}
-
-		return c.next.CreateRepository(ctx, obj)
+func (c *Client) CreateRepository(ctx context.Context, obj mpasv1alpha1.Repository) (providers.RepositoryInfo, error) {
+	if obj.Spec.Source != nil {
+		return c.importRepository(ctx, obj)
 	}
 
 	authenticationOption, err := c.constructAuthenticationOption(ctx, obj)
 	if err != nil {
-		return err
+		return providers.RepositoryInfo{}, err
 	}
 
 	domain := defaultDomain
@@ -59,52 +69,232 @@ func (c *Client) CreateRepository(ctx context.Context, obj mpasv1alpha1.Reposito
 		domain = obj.Spec.Domain
 	}
 
-	gc, err := github.NewClient(authenticationOption, gitprovider.WithDomain(domain))
+	gc, err := github.NewClient(authenticationOption, gitprovider.WithDomain(domain), rateLimitHook)
 	if err != nil {
-		return fmt.Errorf("failed to create github client: %w", err)
+		return providers.RepositoryInfo{}, fmt.Errorf("failed to create github client: %w", err)
 	}
 
 	if obj.Spec.IsOrganization {
-		return gogit.CreateOrganizationRepository(ctx, gc, domain, obj)
+		return gogit.CreateOrganizationRepository(ctx, gc, domain, c.content, obj)
 	}
 
-	return gogit.CreateUserRepository(ctx, gc, domain, obj)
+	return gogit.CreateUserRepository(ctx, gc, domain, c.content, obj)
 }
 
-func (c *Client) CreateBranchProtection(ctx context.Context, obj mpasv1alpha1.Repository) error {
-	if obj.Spec.Provider != providerType {
-		if c.next == nil {
-			return fmt.Errorf("can't handle provider type '%s' and no next provider is configured", obj.Spec.Provider)
-		}
+// importRepository creates an empty repository and pushes the branches and tags of the source into it. GitHub has
+// no pull mirrors, so mirroring is not supported.
+func (c *Client) importRepository(ctx context.Context, obj mpasv1alpha1.Repository) (providers.RepositoryInfo, error) {
+	logger := log.FromContext(ctx)
 
-		return c.next.CreateBranchProtection(ctx, obj)
+	if obj.Spec.Source.Mirror {
+		return providers.RepositoryInfo{}, fmt.Errorf("mirroring repositories: %w", providers.ErrNotSupported)
 	}
 
 	token, err := c.retrieveAccessToken(ctx, obj)
 	if err != nil {
-		return fmt.Errorf("failed to retrieve token: %w", err)
+		return providers.RepositoryInfo{}, fmt.Errorf("failed to retrieve token: %w", err)
 	}
 
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: string(token)})
-	tc := oauth2.NewClient(ctx, ts)
-	g := ggithub.NewClient(tc)
-
-	if _, _, err := g.Repositories.UpdateBranchProtection(ctx, obj.Spec.Owner, obj.Name, obj.Spec.DefaultBranch, &ggithub.ProtectionRequest{
-		RequiredStatusChecks: &ggithub.RequiredStatusChecks{
-			Strict: true,
-			Checks: []*ggithub.RequiredStatusCheck{
-				{
-					Context: deliveryv1alpha1.StatusCheckName,
-				},
+	g, err := c.newGitHubClient(ctx, obj)
+	if err != nil {
+		return providers.RepositoryInfo{}, err
+	}
+
+	existing, resp, err := g.Repositories.Get(ctx, obj.Spec.Owner, obj.Name)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return providers.RepositoryInfo{}, fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	if existing != nil {
+		if obj.Spec.ExistingRepositoryPolicy == mpasv1alpha1.ExistingRepositoryPolicyFail {
+			return providers.RepositoryInfo{}, fmt.Errorf("repository '%s/%s' already exists", obj.Spec.Owner, obj.Name)
+		}
+
+		logger.Info("using existing repository", "repository", obj.GetName())
+
+		return repositoryInfo(existing, false), nil
+	}
+
+	auth, err := providers.SourceAuth(ctx, c.client, obj)
+	if err != nil {
+		return providers.RepositoryInfo{}, err
+	}
+
+	org := ""
+	if obj.Spec.IsOrganization {
+		org = obj.Spec.Owner
+	}
+
+	repo, _, err := g.Repositories.Create(ctx, org, &ggithub.Repository{
+		Name:        ggithub.String(obj.Name),
+		Description: ggithub.String(obj.Spec.Description),
+		Visibility:  ggithub.String(obj.Spec.Visibility),
+		AutoInit:    ggithub.Bool(false),
+	})
+	if err != nil {
+		return providers.RepositoryInfo{}, fmt.Errorf("failed to create repository: %w", err)
+	}
+
+	if err := pkggogit.Import(ctx, &pkg.ImportOptions{
+		SourceURL:  obj.Spec.Source.URL,
+		SourceAuth: auth,
+		TargetURL:  repo.GetCloneURL(),
+		TargetAuth: &pkg.Auth{
+			BasicAuth: &pkg.BasicAuth{
+				Username: "x-access-token",
+				Password: string(token),
 			},
 		},
 	}); err != nil {
-		return fmt.Errorf("failed to update branch protection rules: %w", err)
+		if _, derr := g.Repositories.Delete(ctx, obj.Spec.Owner, obj.Name); derr != nil {
+			err = errors.Join(err, derr)
+		}
+
+		return providers.RepositoryInfo{}, fmt.Errorf("failed to import repository: %w", err)
+	}
+
+	// The default branch is only known once the branches have been pushed.
+	if imported, _, err := g.Repositories.Get(ctx, obj.Spec.Owner, obj.Name); err == nil {
+		repo = imported
+	}
+
+	logger.Info("successfully imported repository", "repository", obj.GetName(), "source", obj.Spec.Source.URL)
+
+	return repositoryInfo(repo, true), nil
+}
+
+// repositoryInfo converts a GitHub repository into the provider independent RepositoryInfo.
+func repositoryInfo(repo *ggithub.Repository, created bool) providers.RepositoryInfo {
+	return providers.RepositoryInfo{
+		ID:            strconv.FormatInt(repo.GetID(), 10),
+		HTTPSURL:      repo.GetCloneURL(),
+		SSHURL:        repo.GetSSHURL(),
+		WebURL:        repo.GetHTMLURL(),
+		DefaultBranch: repo.GetDefaultBranch(),
+		Visibility:    repo.GetVisibility(),
+		Created:       created,
+	}
+}
+
+// CreateBranchProtection applies the Repository's branch protection rules. The REST API only protects
+// single branches, so rules with wildcard patterns are reported as unsupported.
+func (c *Client) CreateBranchProtection(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	g, err := c.newGitHubClient(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	var unsupported []string
+
+	for _, rule := range providers.BranchProtectionRules(obj) {
+		if providers.HasWildcard(rule.Pattern) {
+			unsupported = append(unsupported, fmt.Sprintf("pattern '%s'", rule.Pattern))
+
+			continue
+		}
+
+		if _, _, err := g.Repositories.UpdateBranchProtection(ctx, obj.Spec.Owner, obj.Name, rule.Pattern, protectionRequest(rule)); err != nil {
+			return fmt.Errorf("failed to update branch protection rules for '%s': %w", rule.Pattern, err)
+		}
+
+		if rule.RequireSignedCommits {
+			if _, _, err := g.Repositories.RequireSignaturesOnProtectedBranch(ctx, obj.Spec.Owner, obj.Name, rule.Pattern); err != nil {
+				return fmt.Errorf("failed to require signed commits for '%s': %w", rule.Pattern, err)
+			}
+		}
+	}
+
+	if len(unsupported) > 0 {
+		return &providers.UnsupportedSettingsError{Settings: unsupported}
 	}
 
 	return nil
 }
 
+// protectionRequest translates a rule into GitHub's branch protection settings.
+func protectionRequest(rule mpasv1alpha1.BranchProtectionRule) *ggithub.ProtectionRequest {
+	request := &ggithub.ProtectionRequest{
+		EnforceAdmins:        rule.EnforceAdmins,
+		RequireLinearHistory: ggithub.Bool(rule.RequireLinearHistory),
+	}
+
+	if len(rule.RequiredStatusChecks) > 0 {
+		checks := make([]*ggithub.RequiredStatusCheck, 0, len(rule.RequiredStatusChecks))
+		for _, check := range rule.RequiredStatusChecks {
+			checks = append(checks, &ggithub.RequiredStatusCheck{
+				Context: check,
+			})
+		}
+
+		request.RequiredStatusChecks = &ggithub.RequiredStatusChecks{
+			Strict: rule.StrictStatusChecks,
+			Checks: checks,
+		}
+	}
+
+	if rule.RequiredApprovingReviewCount > 0 || rule.DismissStaleReviews {
+		request.RequiredPullRequestReviews = &ggithub.PullRequestReviewsEnforcementRequest{
+			RequiredApprovingReviewCount: rule.RequiredApprovingReviewCount,
+			DismissStaleReviews:          rule.DismissStaleReviews,
+		}
+	}
+
+	if rule.RestrictPushes != nil {
+		request.Restrictions = &ggithub.BranchRestrictionsRequest{
+			Users: append([]string{}, rule.RestrictPushes.Users...),
+			Teams: append([]string{}, rule.RestrictPushes.Teams...),
+		}
+	}
+
+	return request
+}
+
+// protectionRule translates GitHub's branch protection settings back into a rule.
+func protectionRule(pattern string, protection *ggithub.Protection) *mpasv1alpha1.BranchProtectionRule {
+	rule := &mpasv1alpha1.BranchProtectionRule{
+		Pattern:              pattern,
+		RequireSignedCommits: protection.GetRequiredSignatures().GetEnabled(),
+	}
+
+	if admins := protection.GetEnforceAdmins(); admins != nil {
+		rule.EnforceAdmins = admins.Enabled
+	}
+
+	if history := protection.GetRequireLinearHistory(); history != nil {
+		rule.RequireLinearHistory = history.Enabled
+	}
+
+	if checks := protection.GetRequiredStatusChecks(); checks != nil {
+		rule.StrictStatusChecks = checks.Strict
+		rule.RequiredStatusChecks = append(rule.RequiredStatusChecks, checks.Contexts...)
+
+		if len(checks.Contexts) == 0 {
+			for _, check := range checks.Checks {
+				rule.RequiredStatusChecks = append(rule.RequiredStatusChecks, check.Context)
+			}
+		}
+	}
+
+	if reviews := protection.GetRequiredPullRequestReviews(); reviews != nil {
+		rule.RequiredApprovingReviewCount = reviews.RequiredApprovingReviewCount
+		rule.DismissStaleReviews = reviews.DismissStaleReviews
+	}
+
+	if restrictions := protection.GetRestrictions(); restrictions != nil {
+		rule.RestrictPushes = &mpasv1alpha1.PushRestrictions{}
+
+		for _, user := range restrictions.Users {
+			rule.RestrictPushes.Users = append(rule.RestrictPushes.Users, user.GetLogin())
+		}
+
+		for _, team := range restrictions.Teams {
+			rule.RestrictPushes.Teams = append(rule.RestrictPushes.Teams, team.GetSlug())
+		}
+	}
+
+	return rule
+}
+
 // constructAuthenticationOption will take the object and construct an authentication option.
 // For now, only token secret is supported, this will be extended in the future.
 func (c *Client) constructAuthenticationOption(ctx context.Context, obj mpasv1alpha1.Repository) (gitprovider.ClientOption, error) {
@@ -116,6 +306,39 @@ func (c *Client) constructAuthenticationOption(ctx context.Context, obj mpasv1al
 	return gitprovider.WithOAuth2Token(string(token)), nil
 }
 
+// newGitHubClient creates a client for the GitHub API using the token from the Repository's secret. If a domain
+// is set, the client talks to the API of that GitHub Enterprise server instead of github.com.
+func (c *Client) newGitHubClient(ctx context.Context, obj mpasv1alpha1.Repository) (*ggithub.Client, error) {
+	token, err := c.retrieveAccessToken(ctx, obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve token: %w", err)
+	}
+
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: string(token)})
+	tc := oauth2.NewClient(ctx, ts)
+	tc.Transport = providers.NewRateLimitTransport(ProviderType, tc.Transport)
+
+	if obj.Spec.Domain == "" {
+		return ggithub.NewClient(tc), nil
+	}
+
+	scheme := "https"
+	if obj.Spec.Insecure {
+		scheme = "http"
+	}
+
+	g, err := ggithub.NewEnterpriseClient(
+		fmt.Sprintf("%s://%s/api/v3/", scheme, obj.Spec.Domain),
+		fmt.Sprintf("%s://%s/api/uploads/", scheme, obj.Spec.Domain),
+		tc,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create github enterprise client: %w", err)
+	}
+
+	return g, nil
+}
+
 func (c *Client) retrieveAccessToken(ctx context.Context, obj mpasv1alpha1.Repository) ([]byte, error) {
 	secret := &v1.Secret{}
 	if err := c.client.Get(ctx, types.NamespacedName{
@@ -133,18 +356,10 @@ func (c *Client) retrieveAccessToken(ctx context.Context, obj mpasv1alpha1.Repos
 	return token, nil
 }
 
-func (c *Client) CreatePullRequest(ctx context.Context, branch string, sync deliveryv1alpha1.Sync, repository mpasv1alpha1.Repository) (int, error) {
-	if repository.Spec.Provider != providerType {
-		if c.next == nil {
-			return -1, fmt.Errorf("can't handle provider type '%s' and no next provider is configured", repository.Spec.Provider)
-		}
-
-		return c.next.CreatePullRequest(ctx, branch, sync, repository)
-	}
-
+func (c *Client) CreatePullRequest(ctx context.Context, branch string, sync deliveryv1alpha1.Sync, repository mpasv1alpha1.Repository) (providers.PullRequest, error) {
 	authenticationOption, err := c.constructAuthenticationOption(ctx, repository)
 	if err != nil {
-		return -1, err
+		return providers.PullRequest{}, err
 	}
 
 	domain := defaultDomain
@@ -152,13 +367,13 @@ func (c *Client) CreatePullRequest(ctx context.Context, branch string, sync deli
 		domain = repository.Spec.Domain
 	}
 
-	gc, err := github.NewClient(authenticationOption, gitprovider.WithDomain(domain))
+	gc, err := github.NewClient(authenticationOption, gitprovider.WithDomain(domain), rateLimitHook)
 	if err != nil {
-		return -1, fmt.Errorf("failed to create github client: %w", err)
+		return providers.PullRequest{}, fmt.Errorf("failed to create github client: %w", err)
 	}
 
 	var (
-		id                    int
+		pr                    providers.PullRequest
 		createPullRequestFunc = gogit.CreateUserPullRequest
 	)
 
@@ -166,42 +381,511 @@ func (c *Client) CreatePullRequest(ctx context.Context, branch string, sync deli
 		createPullRequestFunc = gogit.CreateOrganizationPullRequest
 	}
 
-	if id, err = createPullRequestFunc(ctx, gc, domain, branch, sync.Spec.PullRequestTemplate, repository); err != nil {
-		return 0, fmt.Errorf("failed to create pull request: %w", err)
+	if pr, err = createPullRequestFunc(ctx, gc, domain, branch, sync.Spec.PullRequestTemplate, repository); err != nil {
+		return providers.PullRequest{}, fmt.Errorf("failed to create pull request: %w", err)
 	}
 
-	if err := c.createCheckRun(ctx, repository, id); err != nil {
-		return 0, fmt.Errorf("failed to create check run: %w", err)
+	if err := c.createCheckRun(ctx, repository, pr.ID); err != nil {
+		return providers.PullRequest{}, fmt.Errorf("failed to create check run: %w", err)
 	}
 
-	return id, nil
+	return pr, nil
 }
 
 func (c *Client) createCheckRun(ctx context.Context, repository mpasv1alpha1.Repository, prID int) error {
-	token, err := c.retrieveAccessToken(ctx, repository)
-	if err != nil {
-		return fmt.Errorf("failed to retrieve token: %w", err)
+	return c.setStatus(ctx, repository, prID, &ggithub.RepoStatus{
+		State:       ggithub.String(string(deliveryv1alpha1.CommitStatusStatePending)),
+		Description: ggithub.String("MPAS Validation Check"),
+		Context:     ggithub.String(deliveryv1alpha1.StatusCheckName),
+	})
+}
+
+func (c *Client) CreateCommitStatus(ctx context.Context, repository mpasv1alpha1.Repository, pullRequestID int, status deliveryv1alpha1.CommitStatus) error {
+	repoStatus := &ggithub.RepoStatus{
+		State:   ggithub.String(string(status.Spec.State)),
+		Context: ggithub.String(status.GetContext()),
 	}
 
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: string(token)})
-	tc := oauth2.NewClient(ctx, ts)
+	if status.Spec.Description != "" {
+		repoStatus.Description = ggithub.String(status.Spec.Description)
+	}
+
+	if status.Spec.TargetURL != "" {
+		repoStatus.TargetURL = ggithub.String(status.Spec.TargetURL)
+	}
+
+	return c.setStatus(ctx, repository, pullRequestID, repoStatus)
+}
+
+// setStatus sets the status on the head commit of the given pull request.
+func (c *Client) setStatus(ctx context.Context, repository mpasv1alpha1.Repository, prID int, status *ggithub.RepoStatus) error {
+	g, err := c.newGitHubClient(ctx, repository)
+	if err != nil {
+		return err
+	}
 
-	g := ggithub.NewClient(tc)
-	//
 	pr, _, err := g.PullRequests.Get(ctx, repository.Spec.Owner, repository.Name, prID)
 	if err != nil {
 		return fmt.Errorf("failed to find PR: %w", err)
 	}
 
-	_, _, err = g.Repositories.CreateStatus(ctx, repository.Spec.Owner, repository.Name, *pr.Head.SHA, &ggithub.RepoStatus{
-		State:       ggithub.String("pending"),
-		Description: ggithub.String("MPAS Validation Check"),
-		Context:     ggithub.String(deliveryv1alpha1.StatusCheckName),
+	if _, _, err := g.Repositories.CreateStatus(ctx, repository.Spec.Owner, repository.Name, *pr.Head.SHA, status); err != nil {
+		return fmt.Errorf("failed to create status for pr: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) ArchiveRepository(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	g, err := c.newGitHubClient(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	if _, resp, err := g.Repositories.Edit(ctx, obj.Spec.Owner, obj.Name, &ggithub.Repository{
+		Archived: ggithub.Bool(true),
+	}); err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil
+		}
+
+		return fmt.Errorf("failed to archive repository: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) DeleteRepository(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	g, err := c.newGitHubClient(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	if resp, err := g.Repositories.Delete(ctx, obj.Spec.Owner, obj.Name); err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil
+		}
+
+		return fmt.Errorf("failed to delete repository: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) GetRepositorySettings(ctx context.Context, obj mpasv1alpha1.Repository) (providers.RepositorySettings, error) {
+	g, err := c.newGitHubClient(ctx, obj)
+	if err != nil {
+		return providers.RepositorySettings{}, err
+	}
+
+	repo, _, err := g.Repositories.Get(ctx, obj.Spec.Owner, obj.Name)
+	if err != nil {
+		return providers.RepositorySettings{}, fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	settings := providers.RepositorySettings{
+		Visibility:       repo.GetVisibility(),
+		DefaultBranch:    repo.GetDefaultBranch(),
+		Description:      repo.GetDescription(),
+		Homepage:         repo.GetHomepage(),
+		Topics:           repo.Topics,
+		HasIssues:        repo.HasIssues,
+		HasWiki:          repo.HasWiki,
+		HasProjects:      repo.HasProjects,
+		AllowMergeCommit: repo.AllowMergeCommit,
+		AllowSquashMerge: repo.AllowSquashMerge,
+		AllowRebaseMerge: repo.AllowRebaseMerge,
+	}
+
+	if settings.Topics == nil {
+		settings.Topics = []string{}
+	}
+
+	settings.BranchProtection = map[string]*mpasv1alpha1.BranchProtectionRule{}
+
+	for _, rule := range providers.BranchProtectionRules(obj) {
+		// Wildcard patterns can't be protected and are reported as unsupported instead.
+		if providers.HasWildcard(rule.Pattern) {
+			continue
+		}
+
+		protection, resp, err := g.Repositories.GetBranchProtection(ctx, obj.Spec.Owner, obj.Name, rule.Pattern)
+		if err != nil {
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				settings.BranchProtection[rule.Pattern] = nil
+
+				continue
+			}
+
+			return providers.RepositorySettings{}, fmt.Errorf("failed to get branch protection for '%s': %w", rule.Pattern, err)
+		}
+
+		settings.BranchProtection[rule.Pattern] = protectionRule(rule.Pattern, protection)
+	}
+
+	return settings, nil
+}
+
+func (c *Client) UpdateRepositorySettings(ctx context.Context, obj mpasv1alpha1.Repository, settings providers.RepositorySettings) error {
+	g, err := c.newGitHubClient(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	repo := &ggithub.Repository{}
+
+	if settings.Visibility != "" {
+		repo.Visibility = ggithub.String(settings.Visibility)
+	}
+
+	if settings.DefaultBranch != "" {
+		repo.DefaultBranch = ggithub.String(settings.DefaultBranch)
+	}
+
+	if settings.Description != "" {
+		repo.Description = ggithub.String(settings.Description)
+	}
+
+	if settings.Homepage != "" {
+		repo.Homepage = ggithub.String(settings.Homepage)
+	}
+
+	repo.HasIssues = settings.HasIssues
+	repo.HasWiki = settings.HasWiki
+	repo.HasProjects = settings.HasProjects
+	repo.AllowMergeCommit = settings.AllowMergeCommit
+	repo.AllowSquashMerge = settings.AllowSquashMerge
+	repo.AllowRebaseMerge = settings.AllowRebaseMerge
+
+	if _, _, err := g.Repositories.Edit(ctx, obj.Spec.Owner, obj.Name, repo); err != nil {
+		return fmt.Errorf("failed to update repository: %w", err)
+	}
+
+	if settings.Topics != nil {
+		if _, _, err := g.Repositories.ReplaceAllTopics(ctx, obj.Spec.Owner, obj.Name, settings.Topics); err != nil {
+			return fmt.Errorf("failed to update topics: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// githubPermissions maps roles to the permission names used when granting access.
+var githubPermissions = map[mpasv1alpha1.Role]string{
+	mpasv1alpha1.RoleRead:     "pull",
+	mpasv1alpha1.RoleTriage:   "triage",
+	mpasv1alpha1.RoleWrite:    "push",
+	mpasv1alpha1.RoleMaintain: "maintain",
+	mpasv1alpha1.RoleAdmin:    "admin",
+}
+
+// ReconcilePermissions manages direct collaborators and organization teams. Pending invitations count as access,
+// so users who haven't accepted their invitation yet aren't invited again.
+func (c *Client) ReconcilePermissions(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	if obj.Spec.Permissions == nil {
+		return nil
+	}
+
+	g, err := c.newGitHubClient(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	if err := c.reconcileCollaborators(ctx, g, obj); err != nil {
+		return err
+	}
+
+	return c.reconcileTeams(ctx, g, obj)
+}
+
+func (c *Client) reconcileCollaborators(ctx context.Context, g *ggithub.Client, obj mpasv1alpha1.Repository) error {
+	current := map[string]string{}
+	opts := &ggithub.ListCollaboratorsOptions{
+		Affiliation: "direct",
+		ListOptions: ggithub.ListOptions{PerPage: 100},
+	}
+
+	for {
+		users, resp, err := g.Repositories.ListCollaborators(ctx, obj.Spec.Owner, obj.Name, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list collaborators: %w", err)
+		}
+
+		for _, user := range users {
+			current[strings.ToLower(user.GetLogin())] = githubPermissions[mpasv1alpha1.Role(user.GetRoleName())]
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	invitations, err := c.listInvitations(ctx, g, obj, current)
+	if err != nil {
+		return err
+	}
+
+	keep := []string{obj.Spec.Owner}
+
+	// GitHub App installation tokens have no authenticated user, which then simply can't be excluded from pruning.
+	if self, _, err := g.Users.Get(ctx, ""); err == nil {
+		keep = append(keep, self.GetLogin())
+	} else {
+		log.FromContext(ctx).V(deliveryv1alpha1.LevelDebug).Info("failed to get authenticated user", "error", err)
+	}
+
+	desired := desiredPermissions(obj.Spec.Permissions.Users)
+	grant, revoke := providers.DiffPermissions(desired, current, obj.Spec.Permissions.Prune, keep...)
+
+	for _, name := range grant {
+		if id, ok := invitations[name]; ok {
+			if _, _, err := g.Repositories.UpdateInvitation(ctx, obj.Spec.Owner, obj.Name, id, invitationPermissions[desired[name]]); err != nil {
+				return fmt.Errorf("failed to update invitation of '%s': %w", name, err)
+			}
+
+			continue
+		}
+
+		if _, _, err := g.Repositories.AddCollaborator(ctx, obj.Spec.Owner, obj.Name, name, &ggithub.RepositoryAddCollaboratorOptions{
+			Permission: desired[name],
+		}); err != nil {
+			return fmt.Errorf("failed to add collaborator '%s': %w", name, err)
+		}
+	}
+
+	for _, name := range revoke {
+		if id, ok := invitations[name]; ok {
+			if _, err := g.Repositories.DeleteInvitation(ctx, obj.Spec.Owner, obj.Name, id); err != nil {
+				return fmt.Errorf("failed to delete invitation of '%s': %w", name, err)
+			}
+
+			continue
+		}
+
+		if _, err := g.Repositories.RemoveCollaborator(ctx, obj.Spec.Owner, obj.Name, name); err != nil {
+			return fmt.Errorf("failed to remove collaborator '%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// invitationPermissions maps collaborator permissions to the names used by repository invitations.
+var invitationPermissions = map[string]string{
+	"pull":     "read",
+	"triage":   "triage",
+	"push":     "write",
+	"maintain": "maintain",
+	"admin":    "admin",
+}
+
+// listInvitations adds the users with a pending invitation to current and returns the IDs of their invitations.
+func (c *Client) listInvitations(ctx context.Context, g *ggithub.Client, obj mpasv1alpha1.Repository, current map[string]string) (map[string]int64, error) {
+	invitations := map[string]int64{}
+	opts := &ggithub.ListOptions{PerPage: 100}
+
+	for {
+		list, resp, err := g.Repositories.ListInvitations(ctx, obj.Spec.Owner, obj.Name, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list invitations: %w", err)
+		}
+
+		for _, invitation := range list {
+			name := strings.ToLower(invitation.GetInvitee().GetLogin())
+			current[name] = githubPermissions[mpasv1alpha1.Role(invitation.GetPermissions())]
+			invitations[name] = invitation.GetID()
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	return invitations, nil
+}
+
+func (c *Client) reconcileTeams(ctx context.Context, g *ggithub.Client, obj mpasv1alpha1.Repository) error {
+	current := map[string]string{}
+	opts := &ggithub.ListOptions{PerPage: 100}
+
+	for {
+		teams, resp, err := g.Repositories.ListTeams(ctx, obj.Spec.Owner, obj.Name, opts)
+		if err != nil {
+			// Repositories owned by users have no teams.
+			if resp != nil && resp.StatusCode == http.StatusNotFound && len(obj.Spec.Permissions.Teams) == 0 {
+				return nil
+			}
+
+			return fmt.Errorf("failed to list teams: %w", err)
+		}
+
+		for _, team := range teams {
+			current[strings.ToLower(team.GetSlug())] = team.GetPermission()
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	desired := desiredPermissions(obj.Spec.Permissions.Teams)
+	grant, revoke := providers.DiffPermissions(desired, current, obj.Spec.Permissions.Prune)
+
+	for _, name := range grant {
+		if _, err := g.Teams.AddTeamRepoBySlug(ctx, obj.Spec.Owner, name, obj.Spec.Owner, obj.Name, &ggithub.TeamAddTeamRepoOptions{
+			Permission: desired[name],
+		}); err != nil {
+			return fmt.Errorf("failed to add team '%s': %w", name, err)
+		}
+	}
+
+	for _, name := range revoke {
+		if _, err := g.Teams.RemoveTeamRepoBySlug(ctx, obj.Spec.Owner, name, obj.Spec.Owner, obj.Name); err != nil {
+			return fmt.Errorf("failed to remove team '%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func desiredPermissions(permissions []mpasv1alpha1.Permission) map[string]string {
+	result := make(map[string]string, len(permissions))
+	for _, p := range permissions {
+		result[strings.ToLower(p.Name)] = githubPermissions[p.Role]
+	}
+
+	return result
+}
+
+// githubEvents maps webhook events to GitHub's event names.
+var githubEvents = map[mpasv1alpha1.WebhookEvent]string{
+	mpasv1alpha1.WebhookEventPush:        "push",
+	mpasv1alpha1.WebhookEventPullRequest: "pull_request",
+	mpasv1alpha1.WebhookEventTag:         "create",
+	mpasv1alpha1.WebhookEventRelease:     "release",
+	mpasv1alpha1.WebhookEventIssues:      "issues",
+}
+
+// ReconcileWebhooks creates missing webhooks and updates existing ones. GitHub doesn't return the secret of a
+// webhook, so existing webhooks are always updated to pick up a changed secret.
+func (c *Client) ReconcileWebhooks(ctx context.Context, obj mpasv1alpha1.Repository, hooks []providers.Webhook) error {
+	g, err := c.newGitHubClient(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	existing := map[string]int64{}
+	opts := &ggithub.ListOptions{PerPage: 100}
+
+	for {
+		list, resp, err := g.Repositories.ListHooks(ctx, obj.Spec.Owner, obj.Name, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list webhooks: %w", err)
+		}
+
+		for _, hook := range list {
+			if url, ok := hook.Config["url"].(string); ok {
+				existing[url] = hook.GetID()
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	for _, hook := range hooks {
+		var events []string
+		for _, event := range providers.WebhookEvents(hook) {
+			events = append(events, githubEvents[event])
+		}
+
+		request := &ggithub.Hook{
+			Config: map[string]interface{}{
+				"url":          hook.URL,
+				"content_type": hook.ContentType,
+				"secret":       hook.Secret,
+			},
+			Events: events,
+			Active: ggithub.Bool(true),
+		}
+
+		if id, ok := existing[hook.URL]; ok {
+			if _, _, err := g.Repositories.EditHook(ctx, obj.Spec.Owner, obj.Name, id, request); err != nil {
+				return fmt.Errorf("failed to update webhook '%s': %w", hook.URL, err)
+			}
+
+			continue
+		}
+
+		if _, _, err := g.Repositories.CreateHook(ctx, obj.Spec.Owner, obj.Name, request); err != nil {
+			return fmt.Errorf("failed to create webhook '%s': %w", hook.URL, err)
+		}
+	}
+
+	for _, url := range providers.StaleWebhooks(hooks, obj.Status.Webhooks) {
+		id, ok := existing[url]
+		if !ok {
+			continue
+		}
+
+		if _, err := g.Repositories.DeleteHook(ctx, obj.Spec.Owner, obj.Name, id); err != nil {
+			return fmt.Errorf("failed to delete webhook '%s': %w", url, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) AddDeployKey(ctx context.Context, obj mpasv1alpha1.Repository, key providers.DeployKey) (string, error) {
+	g, err := c.newGitHubClient(ctx, obj)
+	if err != nil {
+		return "", err
+	}
+
+	created, _, err := g.Repositories.CreateKey(ctx, obj.Spec.Owner, obj.Name, &ggithub.Key{
+		Title:    ggithub.String(key.Title),
+		Key:      ggithub.String(strings.TrimSpace(string(key.PublicKey))),
+		ReadOnly: ggithub.Bool(key.ReadOnly),
 	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create deploy key: %w", err)
+	}
 
+	return strconv.FormatInt(created.GetID(), 10), nil
+}
+
+func (c *Client) DeleteDeployKey(ctx context.Context, obj mpasv1alpha1.Repository, id string) error {
+	keyID, err := strconv.ParseInt(id, 10, 64)
 	if err != nil {
-		return fmt.Errorf("failed to create status for pr: %w", err)
+		return fmt.Errorf("invalid deploy key id '%s': %w", id, err)
+	}
+
+	g, err := c.newGitHubClient(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	if resp, err := g.Repositories.DeleteKey(ctx, obj.Spec.Owner, obj.Name, keyID); err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil
+		}
+
+		return fmt.Errorf("failed to delete deploy key: %w", err)
 	}
 
 	return nil
 }
+
+// CommitURL links to the commit on GitHub.
+func (c *Client) CommitURL(repository mpasv1alpha1.Repository, commit string) string {
+	return providers.JoinWebURL(repository, "commit", commit)
+}
@@ -0,0 +1,155 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+)
+
+func TestEnterpriseDomainIsUsed(t *testing.T) {
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		if r.Method == http.MethodPatch {
+			_, _ = w.Write([]byte(`{"archived": true}`))
+
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "auth-secret",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"password": []byte("token"),
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	c := NewClient(fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build(), nil)
+
+	repository := mpasv1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "repository",
+			Namespace: "default",
+		},
+		Spec: mpasv1alpha1.RepositorySpec{
+			Provider: ProviderType,
+			Owner:    "owner",
+			Credentials: mpasv1alpha1.Credentials{
+				SecretRef: corev1.LocalObjectReference{Name: secret.Name},
+			},
+			Domain:   strings.TrimPrefix(server.URL, "http://"),
+			Insecure: true,
+		},
+	}
+
+	require.NoError(t, c.ArchiveRepository(context.Background(), repository))
+	require.NoError(t, c.DeleteRepository(context.Background(), repository))
+
+	assert.Equal(t, []string{
+		"PATCH /api/v3/repos/owner/repository",
+		"DELETE /api/v3/repos/owner/repository",
+	}, requests)
+}
+
+func TestPendingInvitationsAreNotRepeated(t *testing.T) {
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		switch r.URL.Path {
+		case "/api/v3/repos/owner/repository/collaborators":
+			_, _ = w.Write([]byte(`[{"login": "alice", "role_name": "write"}]`))
+		case "/api/v3/repos/owner/repository/invitations":
+			_, _ = w.Write([]byte(`[{"id": 1, "invitee": {"login": "bob"}, "permissions": "read"}]`))
+		case "/api/v3/repos/owner/repository/invitations/1":
+			_, _ = w.Write([]byte(`{"id": 1}`))
+		case "/api/v3/repos/owner/repository/teams":
+			_, _ = w.Write([]byte(`[]`))
+		case "/api/v3/user":
+			// GitHub App installation tokens have no authenticated user.
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message": "Resource not accessible by integration"}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "auth-secret",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"password": []byte("token"),
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	c := NewClient(fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build(), nil)
+
+	repository := mpasv1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "repository",
+			Namespace: "default",
+		},
+		Spec: mpasv1alpha1.RepositorySpec{
+			Provider: ProviderType,
+			Owner:    "owner",
+			Credentials: mpasv1alpha1.Credentials{
+				SecretRef: corev1.LocalObjectReference{Name: secret.Name},
+			},
+			Domain:   strings.TrimPrefix(server.URL, "http://"),
+			Insecure: true,
+			Permissions: &mpasv1alpha1.Permissions{
+				Users: []mpasv1alpha1.Permission{
+					{Name: "alice", Role: mpasv1alpha1.RoleWrite},
+					{Name: "bob", Role: mpasv1alpha1.RoleRead},
+					{Name: "carol", Role: mpasv1alpha1.RoleWrite},
+				},
+			},
+		},
+	}
+
+	require.NoError(t, c.ReconcilePermissions(context.Background(), repository))
+
+	assert.Equal(t, []string{
+		"GET /api/v3/repos/owner/repository/collaborators",
+		"GET /api/v3/repos/owner/repository/invitations",
+		"GET /api/v3/user",
+		"PUT /api/v3/repos/owner/repository/collaborators/carol",
+		"GET /api/v3/repos/owner/repository/teams",
+	}, requests)
+
+	requests = nil
+	repository.Spec.Permissions.Users[1].Role = mpasv1alpha1.RoleWrite
+
+	require.NoError(t, c.ReconcilePermissions(context.Background(), repository))
+	assert.Contains(t, requests, "PATCH /api/v3/repos/owner/repository/invitations/1")
+	assert.NotContains(t, requests, "PUT /api/v3/repos/owner/repository/collaborators/bob")
+}
@@ -3,113 +3,176 @@ package gitlab
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/fluxcd/go-git-providers/gitlab"
 	"github.com/fluxcd/go-git-providers/gitprovider"
+	gogitlab "github.com/xanzy/go-gitlab"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	deliveryv1alpha1 "github.com/open-component-model/git-controller/apis/delivery/v1alpha1"
 	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+	"github.com/open-component-model/git-controller/pkg"
 	"github.com/open-component-model/git-controller/pkg/providers"
 	"github.com/open-component-model/git-controller/pkg/providers/gogit"
 )
 
+// ProviderType is the name under which this provider is registered.
+const ProviderType = "gitlab"
+
 const (
 	tokenKey      = "password"
-	providerType  = "gitlab"
 	defaultDomain = gitlab.DefaultDomain
 )
 
 // tokenType for now, only personal tokens are supported.
 var tokenType = "personal"
 
+// rateLimitHook detects rate limiting by the GitLab API.
+var rateLimitHook = gitprovider.WithPreChainTransportHook(func(in http.RoundTripper) http.RoundTripper {
+	return providers.NewRateLimitTransport(ProviderType, in)
+})
+
 // Client gitlab.
 type Client struct {
-	client client.Client
-	next   providers.Provider
+	client  client.Client
+	content providers.ContentLoader
 }
 
-// NewClient creates a new Gitlab client.
-func NewClient(client client.Client, next providers.Provider) *Client {
+// NewClient creates a new Gitlab client. The content loader provides the files of newly created repositories.
+func NewClient(client client.Client, content providers.ContentLoader) *Client {
 	return &Client{
-		client: client,
-		next:   next,
+		client:  client,
+		content: content,
 	}
 }
 
 var _ providers.Provider = &Client{}
 
-func (c *Client) CreateRepository(ctx context.Context, obj mpasv1alpha1.Repository) error {
-	if obj.Spec.Provider != providerType {
-		if c.next == nil {
-			return fmt.Errorf("can't handle provider type '%s' and no next provider is configured", obj.Spec.Provider)
-		}
+func (c *Client) CreateRepository(ctx context.Context, obj mpasv1alpha1.Repository) (providers.RepositoryInfo, error) {
+	gc, domain, err := c.newClient(ctx, obj)
+	if err != nil {
+		return providers.RepositoryInfo{}, err
+	}
 
-		return c.next.CreateRepository(ctx, obj)
+	if obj.Spec.Source != nil {
+		return c.importRepository(ctx, gc, obj)
 	}
 
-	secret := &v1.Secret{}
-	if err := c.client.Get(ctx, types.NamespacedName{
-		Name:      obj.Spec.Credentials.SecretRef.Name,
-		Namespace: obj.Namespace,
-	}, secret); err != nil {
-		return fmt.Errorf("failed to get secret: %w", err)
+	if obj.Spec.IsOrganization {
+		return gogit.CreateOrganizationRepository(ctx, gc, domain, c.content, obj)
 	}
 
-	token, ok := secret.Data[tokenKey]
-	if !ok {
-		return fmt.Errorf("token '%s' not found in secret", tokenKey)
+	return gogit.CreateUserRepository(ctx, gc, domain, c.content, obj)
+}
+
+// importRepository creates the project through GitLab's repository import, optionally as a pull mirror of the
+// source.
+func (c *Client) importRepository(ctx context.Context, gc gitprovider.Client, obj mpasv1alpha1.Repository) (providers.RepositoryInfo, error) {
+	logger := log.FromContext(ctx)
+
+	raw, err := rawClient(gc)
+	if err != nil {
+		return providers.RepositoryInfo{}, err
 	}
 
-	domain := defaultDomain
-	if obj.Spec.Domain != "" {
-		domain = obj.Spec.Domain
+	existing, resp, err := raw.Projects.GetProject(projectID(obj), nil, gogitlab.WithContext(ctx))
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return providers.RepositoryInfo{}, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	if existing != nil && err == nil {
+		if obj.Spec.ExistingRepositoryPolicy == mpasv1alpha1.ExistingRepositoryPolicyFail {
+			return providers.RepositoryInfo{}, fmt.Errorf("project '%s' already exists", projectID(obj))
+		}
+
+		logger.Info("using existing project", "project", projectID(obj))
+
+		return repositoryInfo(existing, false), nil
 	}
 
-	gc, err := gitlab.NewClient(string(token), tokenType, gitprovider.WithDomain(domain))
+	auth, err := providers.SourceAuth(ctx, c.client, obj)
 	if err != nil {
-		return fmt.Errorf("failed to create gitlab client: %w", err)
+		return providers.RepositoryInfo{}, err
 	}
 
-	if obj.Spec.IsOrganization {
-		return gogit.CreateOrganizationRepository(ctx, gc, domain, obj)
+	importURL, err := sourceURL(obj.Spec.Source.URL, auth)
+	if err != nil {
+		return providers.RepositoryInfo{}, err
+	}
+
+	namespace, _, err := raw.Namespaces.GetNamespace(obj.Spec.Owner, gogitlab.WithContext(ctx))
+	if err != nil {
+		return providers.RepositoryInfo{}, fmt.Errorf("failed to get namespace '%s': %w", obj.Spec.Owner, err)
 	}
 
-	return gogit.CreateUserRepository(ctx, gc, domain, obj)
+	project, _, err := raw.Projects.CreateProject(&gogitlab.CreateProjectOptions{
+		Name:        gogitlab.String(obj.Name),
+		Path:        gogitlab.String(obj.Name),
+		NamespaceID: gogitlab.Int(namespace.ID),
+		Description: gogitlab.String(obj.Spec.Description),
+		Visibility:  gogitlab.Visibility(gogitlab.VisibilityValue(obj.Spec.Visibility)),
+		ImportURL:   gogitlab.String(importURL),
+		Mirror:      gogitlab.Bool(obj.Spec.Source.Mirror),
+	}, gogitlab.WithContext(ctx))
+	if err != nil {
+		return providers.RepositoryInfo{}, fmt.Errorf("failed to import project: %w", err)
+	}
+
+	logger.Info("successfully started project import", "project", projectID(obj), "source", obj.Spec.Source.URL)
+
+	return repositoryInfo(project, true), nil
 }
 
-func (c *Client) CreatePullRequest(ctx context.Context, branch string, sync deliveryv1alpha1.Sync, repository mpasv1alpha1.Repository) (int, error) {
-	if repository.Spec.Provider != providerType {
-		if c.next == nil {
-			return -1, fmt.Errorf("can't handle provider type '%s' and no next provider is configured", repository.Spec.Provider)
-		}
+// sourceURL embeds the source credentials into the import URL as GitLab's import doesn't take them separately.
+func sourceURL(source string, auth *pkg.Auth) (string, error) {
+	username, password, err := providers.MigrationCredentials(auth)
+	if err != nil {
+		return "", err
+	}
 
-		return c.next.CreatePullRequest(ctx, branch, sync, repository)
+	if username == "" && password == "" {
+		return source, nil
 	}
 
-	secret := &v1.Secret{}
-	if err := c.client.Get(ctx, types.NamespacedName{
-		Name:      repository.Spec.Credentials.SecretRef.Name,
-		Namespace: sync.Namespace,
-	}, secret); err != nil {
-		return -1, fmt.Errorf("failed to get secret: %w", err)
+	u, err := url.Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse source url: %w", err)
 	}
 
-	token, ok := secret.Data[tokenKey]
-	if !ok {
-		return -1, fmt.Errorf("token '%s' not found in secret", tokenKey)
+	if username == "" {
+		username = "oauth2"
 	}
 
-	domain := defaultDomain
-	if repository.Spec.Domain != "" {
-		domain = repository.Spec.Domain
+	u.User = url.UserPassword(username, password)
+
+	return u.String(), nil
+}
+
+// repositoryInfo converts a GitLab project into the provider independent RepositoryInfo.
+func repositoryInfo(project *gogitlab.Project, created bool) providers.RepositoryInfo {
+	return providers.RepositoryInfo{
+		ID:            strconv.Itoa(project.ID),
+		HTTPSURL:      project.HTTPURLToRepo,
+		SSHURL:        project.SSHURLToRepo,
+		WebURL:        project.WebURL,
+		DefaultBranch: project.DefaultBranch,
+		Visibility:    string(project.Visibility),
+		Created:       created,
 	}
+}
 
-	gc, err := gitlab.NewClient(string(token), tokenType, gitprovider.WithDomain(domain))
+func (c *Client) CreatePullRequest(ctx context.Context, branch string, sync deliveryv1alpha1.Sync, repository mpasv1alpha1.Repository) (providers.PullRequest, error) {
+	gc, domain, err := c.newClient(ctx, repository)
 	if err != nil {
-		return -1, fmt.Errorf("failed to create gitlab client: %w", err)
+		return providers.PullRequest{}, err
 	}
 
 	if repository.Spec.IsOrganization {
@@ -119,14 +182,783 @@ func (c *Client) CreatePullRequest(ctx context.Context, branch string, sync deli
 	return gogit.CreateUserPullRequest(ctx, gc, domain, branch, sync.Spec.PullRequestTemplate, repository)
 }
 
+// CreateBranchProtection protects the branches matching each rule of the Repository. GitLab's protected branches
+// only control who may push and merge, every other setting is reported as unsupported. Without explicit rules,
+// branches are left alone, because GitLab can't require the MPAS status check.
 func (c *Client) CreateBranchProtection(ctx context.Context, obj mpasv1alpha1.Repository) error {
-	if obj.Spec.Provider != providerType {
-		if c.next == nil {
-			return fmt.Errorf("can't handle provider type '%s' and no next provider is configured", obj.Spec.Provider)
+	if len(obj.Spec.BranchProtection) == 0 {
+		return providers.ErrNotSupported
+	}
+
+	gc, _, err := c.newClient(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	raw, err := rawClient(gc)
+	if err != nil {
+		return err
+	}
+
+	pid := projectID(obj)
+
+	var unsupported []string
+
+	for _, rule := range obj.Spec.BranchProtection {
+		unsupported = append(unsupported, providers.UnsupportedSettings(rule, providers.RestrictPushesSetting)...)
+
+		push, merge, err := accessLevels(ctx, raw, rule)
+		if err != nil {
+			return err
+		}
+
+		existing, resp, err := raw.ProtectedBranches.GetProtectedBranch(pid, rule.Pattern, gogitlab.WithContext(ctx))
+		if err != nil {
+			if resp == nil || resp.StatusCode != http.StatusNotFound {
+				return fmt.Errorf("failed to get protected branch '%s': %w", rule.Pattern, err)
+			}
+
+			opts := &gogitlab.ProtectRepositoryBranchesOptions{
+				Name:           gogitlab.String(rule.Pattern),
+				AllowedToPush:  &push,
+				AllowedToMerge: &merge,
+			}
+
+			if _, _, err := raw.ProtectedBranches.ProtectRepositoryBranches(pid, opts, gogitlab.WithContext(ctx)); err != nil {
+				return fmt.Errorf("failed to protect branch '%s': %w", rule.Pattern, err)
+			}
+
+			continue
+		}
+
+		// The existing protection is updated in place, so the branch is never unprotected in between.
+		opts := &gogitlab.UpdateProtectedBranchOptions{
+			AllowedToPush:  accessLevelChanges(existing.PushAccessLevels, push),
+			AllowedToMerge: accessLevelChanges(existing.MergeAccessLevels, merge),
+		}
+
+		if opts.AllowedToPush == nil && opts.AllowedToMerge == nil {
+			continue
+		}
+
+		if _, _, err := raw.ProtectedBranches.UpdateProtectedBranch(pid, rule.Pattern, opts, gogitlab.WithContext(ctx)); err != nil {
+			return fmt.Errorf("failed to update protected branch '%s': %w", rule.Pattern, err)
+		}
+	}
+
+	if len(unsupported) > 0 {
+		return &providers.UnsupportedSettingsError{Settings: unsupported}
+	}
+
+	return nil
+}
+
+// accessLevels returns who may push and merge into the branches matching the rule. Without push restrictions,
+// maintainers may push.
+func accessLevels(ctx context.Context, raw *gogitlab.Client, rule mpasv1alpha1.BranchProtectionRule) (push, merge []*gogitlab.BranchPermissionOptions, err error) {
+	merge = []*gogitlab.BranchPermissionOptions{
+		{AccessLevel: gogitlab.AccessLevel(gogitlab.MaintainerPermissions)},
+	}
+
+	if rule.RestrictPushes == nil {
+		push = []*gogitlab.BranchPermissionOptions{
+			{AccessLevel: gogitlab.AccessLevel(gogitlab.MaintainerPermissions)},
+		}
+
+		return push, merge, nil
+	}
+
+	allowed, err := pushAllowances(ctx, raw, *rule.RestrictPushes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	push = append(allowed, &gogitlab.BranchPermissionOptions{
+		AccessLevel: gogitlab.AccessLevel(gogitlab.NoPermissions),
+	})
+
+	return push, merge, nil
+}
+
+// accessLevelChanges returns the entries to add and to remove, so the existing access levels of a protected branch
+// match the desired ones. It returns nil if nothing has to change.
+func accessLevelChanges(existing []*gogitlab.BranchAccessDescription, desired []*gogitlab.BranchPermissionOptions) *[]*gogitlab.BranchPermissionOptions {
+	matches := func(e *gogitlab.BranchAccessDescription, d *gogitlab.BranchPermissionOptions) bool {
+		switch {
+		case d.UserID != nil:
+			return e.UserID == *d.UserID
+		case d.GroupID != nil:
+			return e.GroupID == *d.GroupID
+		default:
+			return e.UserID == 0 && e.GroupID == 0 && d.AccessLevel != nil && e.AccessLevel == *d.AccessLevel
+		}
+	}
+
+	var changes []*gogitlab.BranchPermissionOptions
+
+	for _, d := range desired {
+		if !slices.ContainsFunc(existing, func(e *gogitlab.BranchAccessDescription) bool { return matches(e, d) }) {
+			changes = append(changes, d)
+		}
+	}
+
+	for _, e := range existing {
+		if !slices.ContainsFunc(desired, func(d *gogitlab.BranchPermissionOptions) bool { return matches(e, d) }) {
+			changes = append(changes, &gogitlab.BranchPermissionOptions{
+				ID:      gogitlab.Int(e.ID),
+				Destroy: gogitlab.Bool(true),
+			})
+		}
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	return &changes
+}
+
+// pushAllowances looks up the IDs of the users and groups which are allowed to push.
+func pushAllowances(ctx context.Context, raw *gogitlab.Client, restrictions mpasv1alpha1.PushRestrictions) ([]*gogitlab.BranchPermissionOptions, error) {
+	allowed := make([]*gogitlab.BranchPermissionOptions, 0, len(restrictions.Users)+len(restrictions.Teams))
+
+	for _, username := range restrictions.Users {
+		id, err := userID(ctx, raw, username)
+		if err != nil {
+			return nil, err
+		}
+
+		allowed = append(allowed, &gogitlab.BranchPermissionOptions{
+			UserID: gogitlab.Int(id),
+		})
+	}
+
+	for _, team := range restrictions.Teams {
+		group, _, err := raw.Groups.GetGroup(team, nil, gogitlab.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to find group '%s': %w", team, err)
+		}
+
+		allowed = append(allowed, &gogitlab.BranchPermissionOptions{
+			GroupID: gogitlab.Int(group.ID),
+		})
+	}
+
+	return allowed, nil
+}
+
+func (c *Client) CreateCommitStatus(ctx context.Context, repository mpasv1alpha1.Repository, pullRequestID int, status deliveryv1alpha1.CommitStatus) error {
+	gc, _, err := c.newClient(ctx, repository)
+	if err != nil {
+		return err
+	}
+
+	raw, err := rawClient(gc)
+	if err != nil {
+		return err
+	}
+
+	pid := projectID(repository)
+
+	mr, _, err := raw.MergeRequests.GetMergeRequest(pid, pullRequestID, nil, gogitlab.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to find merge request: %w", err)
+	}
+
+	opts := &gogitlab.SetCommitStatusOptions{
+		State: commitStatusState(status.Spec.State),
+		Name:  gogitlab.String(status.GetContext()),
+	}
+
+	if status.Spec.Description != "" {
+		opts.Description = gogitlab.String(status.Spec.Description)
+	}
+
+	if status.Spec.TargetURL != "" {
+		opts.TargetURL = gogitlab.String(status.Spec.TargetURL)
+	}
+
+	if _, _, err := raw.Commits.SetCommitStatus(pid, mr.SHA, opts, gogitlab.WithContext(ctx)); err != nil {
+		return fmt.Errorf("failed to set commit status: %w", err)
+	}
+
+	return nil
+}
+
+// commitStatusState maps the state to GitLab's build states. GitLab has no distinct error state, so
+// errors are reported as failures.
+func commitStatusState(state deliveryv1alpha1.CommitStatusState) gogitlab.BuildStateValue {
+	switch state {
+	case deliveryv1alpha1.CommitStatusStateSuccess:
+		return gogitlab.Success
+	case deliveryv1alpha1.CommitStatusStateFailure, deliveryv1alpha1.CommitStatusStateError:
+		return gogitlab.Failed
+	default:
+		return gogitlab.Pending
+	}
+}
+
+func (c *Client) ArchiveRepository(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	gc, _, err := c.newClient(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	raw, err := rawClient(gc)
+	if err != nil {
+		return err
+	}
+
+	if _, resp, err := raw.Projects.ArchiveProject(projectID(obj), gogitlab.WithContext(ctx)); err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil
+		}
+
+		return fmt.Errorf("failed to archive project: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) DeleteRepository(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	gc, _, err := c.newClient(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	raw, err := rawClient(gc)
+	if err != nil {
+		return err
+	}
+
+	if resp, err := raw.Projects.DeleteProject(projectID(obj), gogitlab.WithContext(ctx)); err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil
+		}
+
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) GetRepositorySettings(ctx context.Context, obj mpasv1alpha1.Repository) (providers.RepositorySettings, error) {
+	gc, _, err := c.newClient(ctx, obj)
+	if err != nil {
+		return providers.RepositorySettings{}, err
+	}
+
+	raw, err := rawClient(gc)
+	if err != nil {
+		return providers.RepositorySettings{}, err
+	}
+
+	project, _, err := raw.Projects.GetProject(projectID(obj), nil, gogitlab.WithContext(ctx))
+	if err != nil {
+		return providers.RepositorySettings{}, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	topics := project.Topics
+	if topics == nil {
+		topics = []string{}
+	}
+
+	issues := project.IssuesAccessLevel != gogitlab.DisabledAccessControl
+	wiki := project.WikiAccessLevel != gogitlab.DisabledAccessControl
+	squash := project.SquashOption != gogitlab.SquashOptionNever
+
+	protection, err := branchProtection(ctx, raw, obj)
+	if err != nil {
+		return providers.RepositorySettings{}, err
+	}
+
+	// GitLab has no homepage, projects or switches for merge commits and rebasing.
+	return providers.RepositorySettings{
+		Visibility:       string(project.Visibility),
+		DefaultBranch:    project.DefaultBranch,
+		Description:      project.Description,
+		Topics:           topics,
+		HasIssues:        &issues,
+		HasWiki:          &wiki,
+		AllowSquashMerge: &squash,
+		BranchProtection: protection,
+	}, nil
+}
+
+// branchProtection reads the protection of the branches matching the Repository's rules. Only who may push and
+// merge can drift, every other setting is unsupported and taken from the rule. Without explicit rules, branches
+// aren't managed and the result is nil.
+func branchProtection(ctx context.Context, raw *gogitlab.Client, obj mpasv1alpha1.Repository) (map[string]*mpasv1alpha1.BranchProtectionRule, error) {
+	if len(obj.Spec.BranchProtection) == 0 {
+		return nil, nil
+	}
+
+	result := map[string]*mpasv1alpha1.BranchProtectionRule{}
+
+	for _, rule := range obj.Spec.BranchProtection {
+		existing, resp, err := raw.ProtectedBranches.GetProtectedBranch(projectID(obj), rule.Pattern, gogitlab.WithContext(ctx))
+		if err != nil {
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				result[rule.Pattern] = nil
+
+				continue
+			}
+
+			return nil, fmt.Errorf("failed to get protected branch '%s': %w", rule.Pattern, err)
+		}
+
+		push, merge, err := accessLevels(ctx, raw, rule)
+		if err != nil {
+			return nil, err
+		}
+
+		actual := rule
+
+		// GitLab lists access levels by user and group IDs, so differences are detected but not translated back.
+		if accessLevelChanges(existing.PushAccessLevels, push) != nil || accessLevelChanges(existing.MergeAccessLevels, merge) != nil {
+			actual.RestrictPushes = &mpasv1alpha1.PushRestrictions{}
+			if rule.RestrictPushes != nil {
+				actual.RestrictPushes = nil
+			}
+		}
+
+		result[rule.Pattern] = &actual
+	}
+
+	return result, nil
+}
+
+func (c *Client) UpdateRepositorySettings(ctx context.Context, obj mpasv1alpha1.Repository, settings providers.RepositorySettings) error {
+	gc, _, err := c.newClient(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	raw, err := rawClient(gc)
+	if err != nil {
+		return err
+	}
+
+	opts := &gogitlab.EditProjectOptions{}
+
+	if settings.Visibility != "" {
+		opts.Visibility = gogitlab.Visibility(gogitlab.VisibilityValue(settings.Visibility))
+	}
+
+	if settings.DefaultBranch != "" {
+		opts.DefaultBranch = gogitlab.String(settings.DefaultBranch)
+	}
+
+	if settings.Description != "" {
+		opts.Description = gogitlab.String(settings.Description)
+	}
+
+	if settings.Topics != nil {
+		opts.Topics = &settings.Topics
+	}
+
+	if settings.HasIssues != nil {
+		opts.IssuesAccessLevel = gogitlab.AccessControl(accessControl(*settings.HasIssues))
+	}
+
+	if settings.HasWiki != nil {
+		opts.WikiAccessLevel = gogitlab.AccessControl(accessControl(*settings.HasWiki))
+	}
+
+	if settings.AllowSquashMerge != nil {
+		option := gogitlab.SquashOptionNever
+		if *settings.AllowSquashMerge {
+			option = gogitlab.SquashOptionDefaultOff
+		}
+
+		opts.SquashOption = gogitlab.SquashOption(option)
+	}
+
+	if _, _, err := raw.Projects.EditProject(projectID(obj), opts, gogitlab.WithContext(ctx)); err != nil {
+		return fmt.Errorf("failed to update project: %w", err)
+	}
+
+	return nil
+}
+
+// gitlabAccessLevels maps roles to GitLab's access levels. GitLab has no triage level, so reporter is used for it,
+// and owner can't be granted to project members or group shares, so admin becomes maintainer.
+var gitlabAccessLevels = map[mpasv1alpha1.Role]gogitlab.AccessLevelValue{
+	mpasv1alpha1.RoleRead:     gogitlab.ReporterPermissions,
+	mpasv1alpha1.RoleTriage:   gogitlab.ReporterPermissions,
+	mpasv1alpha1.RoleWrite:    gogitlab.DeveloperPermissions,
+	mpasv1alpha1.RoleMaintain: gogitlab.MaintainerPermissions,
+	mpasv1alpha1.RoleAdmin:    gogitlab.MaintainerPermissions,
+}
+
+// ReconcilePermissions manages the direct members of the project and the groups the project is shared with.
+func (c *Client) ReconcilePermissions(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	if obj.Spec.Permissions == nil {
+		return nil
+	}
+
+	gc, _, err := c.newClient(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	raw, err := rawClient(gc)
+	if err != nil {
+		return err
+	}
+
+	if err := reconcileMembers(ctx, raw, obj); err != nil {
+		return err
+	}
+
+	return reconcileGroups(ctx, raw, obj)
+}
+
+func reconcileMembers(ctx context.Context, raw *gogitlab.Client, obj mpasv1alpha1.Repository) error {
+	pid := projectID(obj)
+	current := map[string]string{}
+	ids := map[string]int{}
+	opts := &gogitlab.ListProjectMembersOptions{
+		ListOptions: gogitlab.ListOptions{PerPage: 100},
+	}
+
+	for {
+		members, resp, err := raw.ProjectMembers.ListProjectMembers(pid, opts, gogitlab.WithContext(ctx))
+		if err != nil {
+			return fmt.Errorf("failed to list project members: %w", err)
+		}
+
+		for _, member := range members {
+			name := strings.ToLower(member.Username)
+			current[name] = strconv.Itoa(int(member.AccessLevel))
+			ids[name] = member.ID
 		}
 
-		return c.next.CreateBranchProtection(ctx, obj)
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	self, _, err := raw.Users.CurrentUser(gogitlab.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	desired := desiredAccessLevels(obj.Spec.Permissions.Users)
+	grant, revoke := providers.DiffPermissions(desired, current, obj.Spec.Permissions.Prune, self.Username)
+
+	for _, name := range grant {
+		level := gitlabAccessLevels[roleOf(obj.Spec.Permissions.Users, name)]
+
+		if id, ok := ids[name]; ok {
+			if _, _, err := raw.ProjectMembers.EditProjectMember(pid, id, &gogitlab.EditProjectMemberOptions{
+				AccessLevel: gogitlab.AccessLevel(level),
+			}, gogitlab.WithContext(ctx)); err != nil {
+				return fmt.Errorf("failed to update member '%s': %w", name, err)
+			}
+
+			continue
+		}
+
+		id, err := userID(ctx, raw, name)
+		if err != nil {
+			return err
+		}
+
+		if _, _, err := raw.ProjectMembers.AddProjectMember(pid, &gogitlab.AddProjectMemberOptions{
+			UserID:      id,
+			AccessLevel: gogitlab.AccessLevel(level),
+		}, gogitlab.WithContext(ctx)); err != nil {
+			return fmt.Errorf("failed to add member '%s': %w", name, err)
+		}
+	}
+
+	for _, name := range revoke {
+		if _, err := raw.ProjectMembers.DeleteProjectMember(pid, ids[name], gogitlab.WithContext(ctx)); err != nil {
+			return fmt.Errorf("failed to remove member '%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// reconcileGroups shares the project with the declared groups. GitLab can't change the access level of a shared
+// group, so the share is removed and created again instead.
+func reconcileGroups(ctx context.Context, raw *gogitlab.Client, obj mpasv1alpha1.Repository) error {
+	pid := projectID(obj)
+
+	project, _, err := raw.Projects.GetProject(pid, nil, gogitlab.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to get project: %w", err)
+	}
+
+	current := map[string]string{}
+	ids := map[string]int{}
+
+	for _, group := range project.SharedWithGroups {
+		name := strings.ToLower(group.GroupFullPath)
+		current[name] = strconv.Itoa(group.GroupAccessLevel)
+		ids[name] = group.GroupID
+	}
+
+	desired := desiredAccessLevels(obj.Spec.Permissions.Teams)
+	grant, revoke := providers.DiffPermissions(desired, current, obj.Spec.Permissions.Prune)
+
+	for _, name := range grant {
+		id, ok := ids[name]
+		if ok {
+			if _, err := raw.Projects.DeleteSharedProjectFromGroup(pid, id, gogitlab.WithContext(ctx)); err != nil {
+				return fmt.Errorf("failed to unshare project with group '%s': %w", name, err)
+			}
+		} else {
+			group, _, err := raw.Groups.GetGroup(name, nil, gogitlab.WithContext(ctx))
+			if err != nil {
+				return fmt.Errorf("failed to find group '%s': %w", name, err)
+			}
+
+			id = group.ID
+		}
+
+		if _, err := raw.Projects.ShareProjectWithGroup(pid, &gogitlab.ShareWithGroupOptions{
+			GroupID:     gogitlab.Int(id),
+			GroupAccess: gogitlab.AccessLevel(gitlabAccessLevels[roleOf(obj.Spec.Permissions.Teams, name)]),
+		}, gogitlab.WithContext(ctx)); err != nil {
+			return fmt.Errorf("failed to share project with group '%s': %w", name, err)
+		}
+	}
+
+	for _, name := range revoke {
+		if _, err := raw.Projects.DeleteSharedProjectFromGroup(pid, ids[name], gogitlab.WithContext(ctx)); err != nil {
+			return fmt.Errorf("failed to unshare project with group '%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func desiredAccessLevels(permissions []mpasv1alpha1.Permission) map[string]string {
+	result := make(map[string]string, len(permissions))
+	for _, p := range permissions {
+		result[strings.ToLower(p.Name)] = strconv.Itoa(int(gitlabAccessLevels[p.Role]))
+	}
+
+	return result
+}
+
+// roleOf returns the role declared for the lower-case name.
+func roleOf(permissions []mpasv1alpha1.Permission, name string) mpasv1alpha1.Role {
+	for _, p := range permissions {
+		if strings.EqualFold(p.Name, name) {
+			return p.Role
+		}
+	}
+
+	return ""
+}
+
+// userID looks up the numeric ID of a user by name.
+func userID(ctx context.Context, raw *gogitlab.Client, username string) (int, error) {
+	users, _, err := raw.Users.ListUsers(&gogitlab.ListUsersOptions{
+		Username: gogitlab.String(username),
+	}, gogitlab.WithContext(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to find user '%s': %w", username, err)
+	}
+
+	if len(users) == 0 {
+		return 0, fmt.Errorf("user '%s' not found", username)
+	}
+
+	return users[0].ID, nil
+}
+
+// ReconcileWebhooks creates missing project hooks and updates existing ones. GitLab doesn't return the token of a
+// hook, so existing hooks are always updated to pick up a changed secret.
+func (c *Client) ReconcileWebhooks(ctx context.Context, obj mpasv1alpha1.Repository, hooks []providers.Webhook) error {
+	gc, _, err := c.newClient(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	raw, err := rawClient(gc)
+	if err != nil {
+		return err
+	}
+
+	pid := projectID(obj)
+	existing := map[string]int{}
+	opts := &gogitlab.ListProjectHooksOptions{PerPage: 100}
+
+	for {
+		list, resp, err := raw.Projects.ListProjectHooks(pid, opts, gogitlab.WithContext(ctx))
+		if err != nil {
+			return fmt.Errorf("failed to list project hooks: %w", err)
+		}
+
+		for _, hook := range list {
+			existing[hook.URL] = hook.ID
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	for _, hook := range hooks {
+		events := map[mpasv1alpha1.WebhookEvent]bool{}
+		for _, event := range providers.WebhookEvents(hook) {
+			events[event] = true
+		}
+
+		if id, ok := existing[hook.URL]; ok {
+			if _, _, err := raw.Projects.EditProjectHook(pid, id, &gogitlab.EditProjectHookOptions{
+				URL:                 gogitlab.String(hook.URL),
+				Token:               gogitlab.String(hook.Secret),
+				PushEvents:          gogitlab.Bool(events[mpasv1alpha1.WebhookEventPush]),
+				MergeRequestsEvents: gogitlab.Bool(events[mpasv1alpha1.WebhookEventPullRequest]),
+				TagPushEvents:       gogitlab.Bool(events[mpasv1alpha1.WebhookEventTag]),
+				ReleasesEvents:      gogitlab.Bool(events[mpasv1alpha1.WebhookEventRelease]),
+				IssuesEvents:        gogitlab.Bool(events[mpasv1alpha1.WebhookEventIssues]),
+			}, gogitlab.WithContext(ctx)); err != nil {
+				return fmt.Errorf("failed to update project hook '%s': %w", hook.URL, err)
+			}
+
+			continue
+		}
+
+		if _, _, err := raw.Projects.AddProjectHook(pid, &gogitlab.AddProjectHookOptions{
+			URL:                 gogitlab.String(hook.URL),
+			Token:               gogitlab.String(hook.Secret),
+			PushEvents:          gogitlab.Bool(events[mpasv1alpha1.WebhookEventPush]),
+			MergeRequestsEvents: gogitlab.Bool(events[mpasv1alpha1.WebhookEventPullRequest]),
+			TagPushEvents:       gogitlab.Bool(events[mpasv1alpha1.WebhookEventTag]),
+			ReleasesEvents:      gogitlab.Bool(events[mpasv1alpha1.WebhookEventRelease]),
+			IssuesEvents:        gogitlab.Bool(events[mpasv1alpha1.WebhookEventIssues]),
+		}, gogitlab.WithContext(ctx)); err != nil {
+			return fmt.Errorf("failed to create project hook '%s': %w", hook.URL, err)
+		}
+	}
+
+	for _, url := range providers.StaleWebhooks(hooks, obj.Status.Webhooks) {
+		id, ok := existing[url]
+		if !ok {
+			continue
+		}
+
+		if _, err := raw.Projects.DeleteProjectHook(pid, id, gogitlab.WithContext(ctx)); err != nil {
+			return fmt.Errorf("failed to delete project hook '%s': %w", url, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) AddDeployKey(ctx context.Context, obj mpasv1alpha1.Repository, key providers.DeployKey) (string, error) {
+	gc, _, err := c.newClient(ctx, obj)
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := rawClient(gc)
+	if err != nil {
+		return "", err
+	}
+
+	created, _, err := raw.DeployKeys.AddDeployKey(projectID(obj), &gogitlab.AddDeployKeyOptions{
+		Title:   gogitlab.String(key.Title),
+		Key:     gogitlab.String(strings.TrimSpace(string(key.PublicKey))),
+		CanPush: gogitlab.Bool(!key.ReadOnly),
+	}, gogitlab.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed to add deploy key: %w", err)
+	}
+
+	return strconv.Itoa(created.ID), nil
+}
+
+func (c *Client) DeleteDeployKey(ctx context.Context, obj mpasv1alpha1.Repository, id string) error {
+	keyID, err := strconv.Atoi(id)
+	if err != nil {
+		return fmt.Errorf("invalid deploy key id '%s': %w", id, err)
+	}
+
+	gc, _, err := c.newClient(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	raw, err := rawClient(gc)
+	if err != nil {
+		return err
+	}
+
+	if resp, err := raw.DeployKeys.DeleteDeployKey(projectID(obj), keyID, gogitlab.WithContext(ctx)); err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil
+		}
+
+		return fmt.Errorf("failed to delete deploy key: %w", err)
+	}
+
+	return nil
+}
+
+// CommitURL links to the commit on GitLab.
+func (c *Client) CommitURL(repository mpasv1alpha1.Repository, commit string) string {
+	return providers.JoinWebURL(repository, "-", "commit", commit)
+}
+
+func accessControl(enabled bool) gogitlab.AccessControlValue {
+	if enabled {
+		return gogitlab.EnabledAccessControl
+	}
+
+	return gogitlab.DisabledAccessControl
+}
+
+// projectID returns the path of the project which GitLab accepts instead of the numeric ID.
+func projectID(obj mpasv1alpha1.Repository) string {
+	return fmt.Sprintf("%s/%s", obj.Spec.Owner, obj.GetName())
+}
+
+// rawClient returns the underlying go-gitlab client for functionality not covered by go-git-providers.
+func rawClient(gc gitprovider.Client) (*gogitlab.Client, error) {
+	raw, ok := gc.Raw().(*gogitlab.Client)
+	if !ok {
+		return nil, fmt.Errorf("unexpected raw gitlab client type %T", gc.Raw())
+	}
+
+	return raw, nil
+}
+
+// newClient creates a gitlab client using the token from the Repository's secret.
+func (c *Client) newClient(ctx context.Context, obj mpasv1alpha1.Repository) (gitprovider.Client, string, error) {
+	secret := &v1.Secret{}
+	if err := c.client.Get(ctx, types.NamespacedName{
+		Name:      obj.Spec.Credentials.SecretRef.Name,
+		Namespace: obj.Namespace,
+	}, secret); err != nil {
+		return nil, "", fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	token, ok := secret.Data[tokenKey]
+	if !ok {
+		return nil, "", fmt.Errorf("token '%s' not found in secret", tokenKey)
+	}
+
+	domain := defaultDomain
+	if obj.Spec.Domain != "" {
+		domain = obj.Spec.Domain
+	}
+
+	gc, err := gitlab.NewClient(string(token), tokenType, gitprovider.WithDomain(domain), rateLimitHook)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create gitlab client: %w", err)
 	}
 
-	return providers.ErrNotSupported
+	return gc, domain, nil
 }
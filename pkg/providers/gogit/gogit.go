@@ -4,9 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
+	"strconv"
 
 	"github.com/fluxcd/go-git-providers/gitprovider"
+	gogitlab "github.com/xanzy/go-gitlab"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	deliveryv1alpha1 "github.com/open-component-model/git-controller/apis/delivery/v1alpha1"
@@ -17,13 +18,13 @@ import (
 // CreateOrganizationRepository creates a repository for an authenticated organization.
 //
 //nolint:dupl // unfortunately it is the same but using a different interface with different parameter types.
-func CreateOrganizationRepository(ctx context.Context, gc gitprovider.Client, domain string, obj mpasv1alpha1.Repository) error {
+func CreateOrganizationRepository(ctx context.Context, gc gitprovider.Client, domain string, content providers.ContentLoader, obj mpasv1alpha1.Repository) (providers.RepositoryInfo, error) {
 	logger := log.FromContext(ctx)
 
 	visibility := gitprovider.RepositoryVisibility(obj.Spec.Visibility)
 
 	if err := gitprovider.ValidateRepositoryVisibility(visibility); err != nil {
-		return fmt.Errorf("failed to validate visibility: %w", err)
+		return providers.RepositoryInfo{}, fmt.Errorf("failed to validate visibility: %w", err)
 	}
 
 	ref := gitprovider.OrgRepositoryRef{
@@ -38,63 +39,73 @@ func CreateOrganizationRepository(ctx context.Context, gc gitprovider.Client, do
 		Visibility:    &visibility,
 	}
 
+	if obj.Spec.Description != "" {
+		info.Description = gitprovider.StringVar(obj.Spec.Description)
+	}
+
 	createOpts, err := gitprovider.MakeRepositoryCreateOptions(&gitprovider.RepositoryCreateOptions{AutoInit: gitprovider.BoolVar(true)})
 	if err != nil {
-		return fmt.Errorf("failed to create _create_ options for repository: %w", err)
+		return providers.RepositoryInfo{}, fmt.Errorf("failed to create _create_ options for repository: %w", err)
 	}
 
+	var result providers.RepositoryInfo
+
 	switch obj.Spec.ExistingRepositoryPolicy {
 	case mpasv1alpha1.ExistingRepositoryPolicyFail:
 		repo, err := gc.OrgRepositories().Create(ctx, ref, info, &createOpts)
 		if err != nil {
-			return fmt.Errorf("failed to create repository: %w", err)
+			return providers.RepositoryInfo{}, fmt.Errorf("failed to create repository: %w", err)
 		}
 
-		if err := setupProjectStructure(ctx, repo, obj.Spec.Maintainers); err != nil {
+		if err := setupProjectStructure(ctx, repo, content, obj); err != nil {
 			if cerr := repo.Delete(ctx); cerr != nil {
 				err = errors.Join(err, cerr)
 			}
 
-			return fmt.Errorf("failed to create initial project structure: %w", err)
+			return providers.RepositoryInfo{}, fmt.Errorf("failed to create initial project structure: %w", err)
 		}
 
+		result = repositoryInfo(repo, true)
+
 		logger.Info("successfully created organization repository", "domain", domain, "repository", obj.GetName())
 	case mpasv1alpha1.ExistingRepositoryPolicyAdopt:
 		repo, created, err := gc.OrgRepositories().Reconcile(ctx, ref, info, &createOpts)
 		if err != nil {
-			return fmt.Errorf("failed to reconcile repository: %w", err)
+			return providers.RepositoryInfo{}, fmt.Errorf("failed to reconcile repository: %w", err)
 		}
 
+		result = repositoryInfo(repo, created)
+
 		if !created {
 			logger.Info("using existing repository", "domain", domain, "repository", obj.GetName())
 		} else {
-			if err := setupProjectStructure(ctx, repo, obj.Spec.Maintainers); err != nil {
+			if err := setupProjectStructure(ctx, repo, content, obj); err != nil {
 				if cerr := repo.Delete(ctx); cerr != nil {
 					err = errors.Join(err, cerr)
 				}
 
-				return fmt.Errorf("failed to create initial project structure: %w", err)
+				return providers.RepositoryInfo{}, fmt.Errorf("failed to create initial project structure: %w", err)
 			}
 
 			logger.Info("successfully created organization repository", "domain", domain, "repository", obj.GetName())
 		}
 	default:
-		return fmt.Errorf("unknown repository policy '%s'", obj.Spec.ExistingRepositoryPolicy)
+		return providers.RepositoryInfo{}, fmt.Errorf("unknown repository policy '%s'", obj.Spec.ExistingRepositoryPolicy)
 	}
 
-	return nil
+	return result, nil
 }
 
 // CreateUserRepository creates a repository for an authenticated user.
 //
 //nolint:dupl // unfortunately it is the same but using a different interface with different parameter types.
-func CreateUserRepository(ctx context.Context, gc gitprovider.Client, domain string, obj mpasv1alpha1.Repository) error {
+func CreateUserRepository(ctx context.Context, gc gitprovider.Client, domain string, content providers.ContentLoader, obj mpasv1alpha1.Repository) (providers.RepositoryInfo, error) {
 	logger := log.FromContext(ctx)
 
 	visibility := gitprovider.RepositoryVisibility(obj.Spec.Visibility)
 
 	if err := gitprovider.ValidateRepositoryVisibility(visibility); err != nil {
-		return fmt.Errorf("failed to validate visibility: %w", err)
+		return providers.RepositoryInfo{}, fmt.Errorf("failed to validate visibility: %w", err)
 	}
 
 	ref := gitprovider.UserRepositoryRef{
@@ -105,55 +116,101 @@ func CreateUserRepository(ctx context.Context, gc gitprovider.Client, domain str
 		RepositoryName: obj.GetName(),
 	}
 	info := gitprovider.RepositoryInfo{
-		DefaultBranch: gitprovider.StringVar("main"),
+		DefaultBranch: gitprovider.StringVar(obj.Spec.DefaultBranch),
 		Visibility:    &visibility,
 	}
 
+	if obj.Spec.Description != "" {
+		info.Description = gitprovider.StringVar(obj.Spec.Description)
+	}
+
 	createOpts, err := gitprovider.MakeRepositoryCreateOptions(&gitprovider.RepositoryCreateOptions{AutoInit: gitprovider.BoolVar(true)})
 	if err != nil {
-		return fmt.Errorf("failed to create _create_ options for repository: %w", err)
+		return providers.RepositoryInfo{}, fmt.Errorf("failed to create _create_ options for repository: %w", err)
 	}
 
+	var result providers.RepositoryInfo
+
 	switch obj.Spec.ExistingRepositoryPolicy {
 	case mpasv1alpha1.ExistingRepositoryPolicyFail:
 		repo, err := gc.UserRepositories().Create(ctx, ref, info, &createOpts)
 		if err != nil {
-			return fmt.Errorf("failed to create repository: %w", err)
+			return providers.RepositoryInfo{}, fmt.Errorf("failed to create repository: %w", err)
 		}
 
-		if err := setupProjectStructure(ctx, repo, obj.Spec.Maintainers); err != nil {
+		if err := setupProjectStructure(ctx, repo, content, obj); err != nil {
 			if cerr := repo.Delete(ctx); cerr != nil {
 				err = errors.Join(err, cerr)
 			}
 
-			return fmt.Errorf("failed to create initial project structure: %w", err)
+			return providers.RepositoryInfo{}, fmt.Errorf("failed to create initial project structure: %w", err)
 		}
 
+		result = repositoryInfo(repo, true)
+
 		logger.Info("successfully created user repository", "domain", domain, "repository", obj.GetName())
 	case mpasv1alpha1.ExistingRepositoryPolicyAdopt:
 		repo, created, err := gc.UserRepositories().Reconcile(ctx, ref, info, &createOpts)
 		if err != nil {
-			return fmt.Errorf("failed to reconcile repository: %w", err)
+			return providers.RepositoryInfo{}, fmt.Errorf("failed to reconcile repository: %w", err)
 		}
 
+		result = repositoryInfo(repo, created)
+
 		if !created {
 			logger.Info("using existing repository", "domain", domain, "repository", obj.GetName())
 		} else {
-			if err := setupProjectStructure(ctx, repo, obj.Spec.Maintainers); err != nil {
+			if err := setupProjectStructure(ctx, repo, content, obj); err != nil {
 				if cerr := repo.Delete(ctx); cerr != nil {
 					err = errors.Join(err, cerr)
 				}
 
-				return fmt.Errorf("failed to create initial project structure: %w", err)
+				return providers.RepositoryInfo{}, fmt.Errorf("failed to create initial project structure: %w", err)
 			}
 
 			logger.Info("successfully created user repository", "domain", domain, "repository", obj.GetName())
 		}
 	default:
-		return fmt.Errorf("unknown repository policy '%s'", obj.Spec.ExistingRepositoryPolicy)
+		return providers.RepositoryInfo{}, fmt.Errorf("unknown repository policy '%s'", obj.Spec.ExistingRepositoryPolicy)
 	}
 
-	return nil
+	return result, nil
+}
+
+// repositoryInfo describes a repository returned by go-git-providers.
+func repositoryInfo(repo gitprovider.UserRepository, created bool) providers.RepositoryInfo {
+	ref := repo.Repository()
+	info := repo.Get()
+
+	result := providers.RepositoryInfo{
+		ID:       repositoryID(repo.APIObject()),
+		HTTPSURL: ref.GetCloneURL(gitprovider.TransportTypeHTTPS),
+		SSHURL:   ref.GetCloneURL(gitprovider.TransportTypeSSH),
+		WebURL:   ref.String(),
+		Created:  created,
+	}
+
+	if info.DefaultBranch != nil {
+		result.DefaultBranch = *info.DefaultBranch
+	}
+
+	if info.Visibility != nil {
+		result.Visibility = string(*info.Visibility)
+	}
+
+	return result
+}
+
+// repositoryID returns the provider's identifier of the underlying API object.
+func repositoryID(obj any) string {
+	switch o := obj.(type) {
+	case interface{ GetID() int64 }:
+		return strconv.FormatInt(o.GetID(), 10)
+	case *gogitlab.Project:
+		return strconv.Itoa(o.ID)
+	}
+
+	return ""
 }
 
 // CreateOrganizationPullRequest creates a pull-request for an organization owned repository.
@@ -165,7 +222,7 @@ func CreateOrganizationPullRequest(
 	domain, branch string,
 	spec deliveryv1alpha1.PullRequestTemplate,
 	repository mpasv1alpha1.Repository,
-) (int, error) {
+) (providers.PullRequest, error) {
 	// find the repository
 	repo, err := gc.OrgRepositories().Get(ctx, gitprovider.OrgRepositoryRef{
 		OrganizationRef: gitprovider.OrganizationRef{
@@ -175,7 +232,7 @@ func CreateOrganizationPullRequest(
 		RepositoryName: repository.GetName(),
 	})
 	if err != nil {
-		return -1, fmt.Errorf("failed to find organization repository: %w", err)
+		return providers.PullRequest{}, fmt.Errorf("failed to find organization repository: %w", err)
 	}
 
 	var (
@@ -198,13 +255,13 @@ func CreateOrganizationPullRequest(
 
 	pr, err := repo.PullRequests().Create(ctx, title, branch, base, description)
 	if err != nil {
-		return -1, fmt.Errorf("failed to create pull request: %w", err)
+		return providers.PullRequest{}, fmt.Errorf("failed to create pull request: %w", err)
 	}
 
 	logger := log.FromContext(ctx)
 	logger.Info("created pull request for organization repository", "organization", repository.Spec.Owner, "pull-request", pr.Get().Number)
 
-	return pr.Get().Number, nil
+	return providers.PullRequest{ID: pr.Get().Number, URL: pr.Get().WebURL}, nil
 }
 
 // CreateUserPullRequest creates a pull-request for a user owned repository.
@@ -216,7 +273,7 @@ func CreateUserPullRequest(
 	domain, branch string,
 	spec deliveryv1alpha1.PullRequestTemplate,
 	repository mpasv1alpha1.Repository,
-) (int, error) {
+) (providers.PullRequest, error) {
 	// find the repository
 	repo, err := gc.UserRepositories().Get(ctx, gitprovider.UserRepositoryRef{
 		UserRef: gitprovider.UserRef{
@@ -226,7 +283,7 @@ func CreateUserPullRequest(
 		RepositoryName: repository.GetName(),
 	})
 	if err != nil {
-		return -1, fmt.Errorf("failed to find user repository: %w", err)
+		return providers.PullRequest{}, fmt.Errorf("failed to find user repository: %w", err)
 	}
 
 	var (
@@ -249,13 +306,13 @@ func CreateUserPullRequest(
 
 	pr, err := repo.PullRequests().Create(ctx, title, branch, base, description)
 	if err != nil {
-		return -1, fmt.Errorf("failed to create pull request: %w", err)
+		return providers.PullRequest{}, fmt.Errorf("failed to create pull request: %w", err)
 	}
 
 	logger := log.FromContext(ctx)
 	logger.Info("created pull request for user repository", "user", repository.Spec.Owner, "pull-request", pr.Get().Number)
 
-	return pr.Get().Number, nil
+	return providers.PullRequest{ID: pr.Get().Number, URL: pr.Get().WebURL}, nil
 }
 
 // Repositories groups together a common functionality of both repository types.
@@ -263,41 +320,23 @@ type Repositories interface {
 	Commits() gitprovider.CommitClient
 }
 
-func setupProjectStructure(ctx context.Context, repo Repositories, maintainers []string) error {
+func setupProjectStructure(ctx context.Context, repo Repositories, content providers.ContentLoader, obj mpasv1alpha1.Repository) error {
 	logger := log.FromContext(ctx)
 
-	var files []gitprovider.CommitFile
-
-	if len(maintainers) > 0 {
-		content := strings.Builder{}
-
-		for _, m := range maintainers {
-			if _, err := content.WriteString(fmt.Sprintf("%s\n", m)); err != nil {
-				return fmt.Errorf("failed to write content to buffer: %w", err)
-			}
-		}
+	initial, err := content.Load(ctx, obj)
+	if err != nil {
+		return fmt.Errorf("failed to load initial content: %w", err)
+	}
 
+	files := make([]gitprovider.CommitFile, 0, len(initial))
+	for _, f := range initial {
 		files = append(files, gitprovider.CommitFile{
-			Path:    gitprovider.StringVar("CODEOWNERS"),
-			Content: gitprovider.StringVar(content.String()),
+			Path:    gitprovider.StringVar(f.Path),
+			Content: gitprovider.StringVar(string(f.Content)),
 		})
 	}
 
-	files = append(files, gitprovider.CommitFile{
-		Path:    gitprovider.StringVar("generators/.keep"),
-		Content: gitprovider.StringVar(""),
-	}, gitprovider.CommitFile{
-		Path:    gitprovider.StringVar("products/.keep"),
-		Content: gitprovider.StringVar(""),
-	}, gitprovider.CommitFile{
-		Path:    gitprovider.StringVar("subscriptions/.keep"),
-		Content: gitprovider.StringVar(""),
-	}, gitprovider.CommitFile{
-		Path:    gitprovider.StringVar("targets/.keep"),
-		Content: gitprovider.StringVar(""),
-	})
-
-	commit, err := repo.Commits().Create(ctx, "main", "creating initial project structure", files)
+	commit, err := repo.Commits().Create(ctx, obj.Spec.DefaultBranch, "creating initial project structure", files)
 	if err != nil {
 		return fmt.Errorf("failed to create project structure: %w", err)
 	}
@@ -0,0 +1,42 @@
+package providers
+
+import (
+	"sort"
+	"strings"
+)
+
+// DiffPermissions compares the desired with the current access, both mapping lower-case names to the provider's
+// access level. It returns the names which need to be granted their desired level and the names whose access has
+// to be revoked. Access is only revoked if prune is set and never for the names in keep.
+func DiffPermissions(desired, current map[string]string, prune bool, keep ...string) (grant, revoke []string) {
+	for name, level := range desired {
+		if current[name] != level {
+			grant = append(grant, name)
+		}
+	}
+
+	if prune {
+		for name := range current {
+			if _, ok := desired[name]; ok || containsFold(keep, name) {
+				continue
+			}
+
+			revoke = append(revoke, name)
+		}
+	}
+
+	sort.Strings(grant)
+	sort.Strings(revoke)
+
+	return grant, revoke
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+
+	return false
+}
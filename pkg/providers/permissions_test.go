@@ -0,0 +1,29 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffPermissions(t *testing.T) {
+	desired := map[string]string{
+		"alice": "push",
+		"bob":   "admin",
+		"carol": "pull",
+	}
+	current := map[string]string{
+		"alice": "push",
+		"bob":   "pull",
+		"dave":  "push",
+		"owner": "admin",
+	}
+
+	grant, revoke := DiffPermissions(desired, current, false, "owner")
+	assert.Equal(t, []string{"bob", "carol"}, grant)
+	assert.Empty(t, revoke)
+
+	grant, revoke = DiffPermissions(desired, current, true, "Owner")
+	assert.Equal(t, []string{"bob", "carol"}, grant)
+	assert.Equal(t, []string{"dave"}, revoke)
+}
@@ -0,0 +1,267 @@
+package plaingit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/storage/memory"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	deliveryv1alpha1 "github.com/open-component-model/git-controller/apis/delivery/v1alpha1"
+	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+	"github.com/open-component-model/git-controller/pkg"
+	"github.com/open-component-model/git-controller/pkg/gogit"
+	"github.com/open-component-model/git-controller/pkg/providers"
+)
+
+// ProviderType is the name under which this provider is registered.
+const ProviderType = "git"
+
+const (
+	identityKey = "identity"
+	usernameKey = "username"
+	passwordKey = "password"
+)
+
+// Client works with any git server which has no management API. Repositories must already exist and
+// are only verified to be reachable.
+type Client struct {
+	client client.Client
+}
+
+// NewClient creates a new plain git client.
+func NewClient(client client.Client) *Client {
+	return &Client{
+		client: client,
+	}
+}
+
+var _ providers.Provider = &Client{}
+
+// CreateRepository can't create anything. It verifies that the remote repository exists and can be accessed
+// with the configured credentials. The Repository's source is imported into an existing empty repository.
+func (c *Client) CreateRepository(ctx context.Context, obj mpasv1alpha1.Repository) (providers.RepositoryInfo, error) {
+	logger := log.FromContext(ctx)
+
+	if obj.Spec.Domain == "" {
+		return providers.RepositoryInfo{}, fmt.Errorf("domain must be set when using the '%s' provider", ProviderType)
+	}
+
+	if obj.Spec.ExistingRepositoryPolicy == mpasv1alpha1.ExistingRepositoryPolicyFail {
+		return providers.RepositoryInfo{}, fmt.Errorf(
+			"existing repository policy '%s' is not supported by the '%s' provider, repositories must already exist",
+			mpasv1alpha1.ExistingRepositoryPolicyFail,
+			ProviderType,
+		)
+	}
+
+	auth, err := c.authentication(ctx, obj)
+	if err != nil {
+		return providers.RepositoryInfo{}, err
+	}
+
+	url := obj.GetRepositoryURL()
+	info := repositoryInfo(obj)
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: git.DefaultRemoteName,
+		URLs: []string{url},
+	})
+
+	refs, err := remote.ListContext(ctx, &git.ListOptions{
+		Auth: auth,
+	})
+	if err != nil {
+		if errors.Is(err, transport.ErrEmptyRemoteRepository) {
+			if obj.Spec.Source != nil {
+				return c.importSource(ctx, obj, info)
+			}
+
+			logger.Info("using existing empty repository", "url", url)
+
+			return info, nil
+		}
+
+		return providers.RepositoryInfo{}, fmt.Errorf("failed to list remote references for '%s': %w", url, err)
+	}
+
+	if obj.Spec.DefaultBranch != "" && !hasBranch(refs, obj.Spec.DefaultBranch) {
+		return providers.RepositoryInfo{}, fmt.Errorf("default branch '%s' not found in remote repository '%s'", obj.Spec.DefaultBranch, url)
+	}
+
+	logger.Info("using existing repository", "url", url)
+
+	return info, nil
+}
+
+// importSource pushes the branches and tags of the Repository's source into the existing empty repository.
+// Without an API, there is no way to set up a pull mirror.
+func (c *Client) importSource(ctx context.Context, obj mpasv1alpha1.Repository, info providers.RepositoryInfo) (providers.RepositoryInfo, error) {
+	if obj.Spec.Source.Mirror {
+		return providers.RepositoryInfo{}, fmt.Errorf("mirroring repositories: %w", providers.ErrNotSupported)
+	}
+
+	sourceAuth, err := providers.SourceAuth(ctx, c.client, obj)
+	if err != nil {
+		return providers.RepositoryInfo{}, err
+	}
+
+	targetAuth, err := c.credentials(ctx, obj)
+	if err != nil {
+		return providers.RepositoryInfo{}, err
+	}
+
+	if err := gogit.Import(ctx, &pkg.ImportOptions{
+		SourceURL:  obj.Spec.Source.URL,
+		SourceAuth: sourceAuth,
+		TargetURL:  obj.GetRepositoryURL(),
+		TargetAuth: targetAuth,
+	}); err != nil {
+		return providers.RepositoryInfo{}, fmt.Errorf("failed to import repository: %w", err)
+	}
+
+	log.FromContext(ctx).Info("imported source into existing empty repository", "url", obj.GetRepositoryURL(), "source", obj.Spec.Source.URL)
+
+	return info, nil
+}
+
+// CreatePullRequest is not supported. Syncs have to push directly to a target branch.
+func (c *Client) CreatePullRequest(ctx context.Context, branch string, sync deliveryv1alpha1.Sync, repository mpasv1alpha1.Repository) (providers.PullRequest, error) {
+	return providers.PullRequest{}, providers.ErrNotSupported
+}
+
+// CreateBranchProtection is not supported, there is no API to configure it through.
+func (c *Client) CreateBranchProtection(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	return providers.ErrNotSupported
+}
+
+// CreateCommitStatus is not supported, there are no pull requests to report a status for.
+func (c *Client) CreateCommitStatus(ctx context.Context, repository mpasv1alpha1.Repository, pullRequestID int, status deliveryv1alpha1.CommitStatus) error {
+	return providers.ErrNotSupported
+}
+
+// ArchiveRepository is not supported, there is no API to archive repositories through.
+func (c *Client) ArchiveRepository(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	return providers.ErrNotSupported
+}
+
+// DeleteRepository is not supported, there is no API to delete repositories through.
+func (c *Client) DeleteRepository(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	return providers.ErrNotSupported
+}
+
+// GetRepositorySettings is not supported, there is no API to read the settings from.
+func (c *Client) GetRepositorySettings(ctx context.Context, obj mpasv1alpha1.Repository) (providers.RepositorySettings, error) {
+	return providers.RepositorySettings{}, providers.ErrNotSupported
+}
+
+// UpdateRepositorySettings is not supported, there is no API to change the settings through.
+func (c *Client) UpdateRepositorySettings(ctx context.Context, obj mpasv1alpha1.Repository, settings providers.RepositorySettings) error {
+	return providers.ErrNotSupported
+}
+
+// ReconcilePermissions is not supported, there is no API to manage access through.
+func (c *Client) ReconcilePermissions(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	return providers.ErrNotSupported
+}
+
+// ReconcileWebhooks is not supported, there is no API to manage webhooks through.
+func (c *Client) ReconcileWebhooks(ctx context.Context, obj mpasv1alpha1.Repository, hooks []providers.Webhook) error {
+	return providers.ErrNotSupported
+}
+
+// AddDeployKey is not supported, there is no API to register keys through.
+func (c *Client) AddDeployKey(ctx context.Context, obj mpasv1alpha1.Repository, key providers.DeployKey) (string, error) {
+	return "", providers.ErrNotSupported
+}
+
+// DeleteDeployKey is not supported, there is no API to remove keys through.
+func (c *Client) DeleteDeployKey(ctx context.Context, obj mpasv1alpha1.Repository, id string) error {
+	return providers.ErrNotSupported
+}
+
+// CommitURL is always empty, a plain git server has no web interface.
+func (c *Client) CommitURL(repository mpasv1alpha1.Repository, commit string) string {
+	return ""
+}
+
+// authentication constructs the auth method from the Repository's secret using the same keys as the Sync
+// push does. An identity results in SSH authentication; otherwise, basic auth is used.
+func (c *Client) authentication(ctx context.Context, obj mpasv1alpha1.Repository) (transport.AuthMethod, error) {
+	credentials, err := c.credentials(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return gogit.AuthMethod(credentials)
+}
+
+// credentials reads the Repository's secret. It returns nil if the secret holds neither an identity nor a
+// username and password.
+func (c *Client) credentials(ctx context.Context, obj mpasv1alpha1.Repository) (*pkg.Auth, error) {
+	secret := &v1.Secret{}
+	if err := c.client.Get(ctx, types.NamespacedName{
+		Name:      obj.Spec.Credentials.SecretRef.Name,
+		Namespace: obj.Namespace,
+	}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	if identity, ok := secret.Data[identityKey]; ok {
+		return &pkg.Auth{
+			SSH: &pkg.SSH{
+				PemBytes: identity,
+				User:     string(secret.Data[usernameKey]),
+				Password: string(secret.Data[passwordKey]),
+			},
+		}, nil
+	}
+
+	username, password := secret.Data[usernameKey], secret.Data[passwordKey]
+	if len(username) == 0 && len(password) == 0 {
+		return nil, nil
+	}
+
+	return &pkg.Auth{
+		BasicAuth: &pkg.BasicAuth{
+			Username: string(username),
+			Password: string(password),
+		},
+	}, nil
+}
+
+// repositoryInfo describes the configured repository. Without an API, there is no ID or web URL and the
+// repository is always adopted.
+func repositoryInfo(obj mpasv1alpha1.Repository) providers.RepositoryInfo {
+	info := providers.RepositoryInfo{
+		DefaultBranch: obj.Spec.DefaultBranch,
+	}
+
+	url := obj.GetRepositoryURL()
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		info.HTTPSURL = url
+	} else {
+		info.SSHURL = url
+	}
+
+	return info
+}
+
+func hasBranch(refs []*plumbing.Reference, branch string) bool {
+	name := plumbing.NewBranchReferenceName(branch)
+	for _, ref := range refs {
+		if ref.Name() == name {
+			return true
+		}
+	}
+
+	return false
+}
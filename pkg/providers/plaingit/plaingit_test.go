@@ -0,0 +1,227 @@
+package plaingit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+	"github.com/open-component-model/git-controller/pkg/gitserver"
+	"github.com/open-component-model/git-controller/pkg/providers"
+)
+
+func TestCreateRepository(t *testing.T) {
+	server := gitserver.New(t.TempDir())
+	defer server.Close()
+
+	_, err := server.InitRepository("owner/existing")
+	require.NoError(t, err)
+	_, err = server.InitRepository("owner/empty")
+	require.NoError(t, err)
+	pushInitialCommit(t, server.URL()+"/owner/existing", "main")
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "auth-secret",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"username": []byte("username"),
+			"password": []byte("password"),
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	c := NewClient(fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build())
+
+	testCases := []struct {
+		name          string
+		repository    string
+		defaultBranch string
+		policy        mpasv1alpha1.ExistingRepositoryPolicy
+		err           string
+	}{
+		{
+			name:          "existing repository is adopted",
+			repository:    "existing",
+			defaultBranch: "main",
+			policy:        mpasv1alpha1.ExistingRepositoryPolicyAdopt,
+		},
+		{
+			name:          "empty repository is adopted",
+			repository:    "empty",
+			defaultBranch: "main",
+			policy:        mpasv1alpha1.ExistingRepositoryPolicyAdopt,
+		},
+		{
+			name:          "missing repository fails",
+			repository:    "missing",
+			defaultBranch: "main",
+			policy:        mpasv1alpha1.ExistingRepositoryPolicyAdopt,
+			err:           "repository not found",
+		},
+		{
+			name:          "missing default branch fails",
+			repository:    "existing",
+			defaultBranch: "develop",
+			policy:        mpasv1alpha1.ExistingRepositoryPolicyAdopt,
+			err:           "default branch 'develop' not found",
+		},
+		{
+			name:          "fail policy is not supported",
+			repository:    "existing",
+			defaultBranch: "main",
+			policy:        mpasv1alpha1.ExistingRepositoryPolicyFail,
+			err:           "not supported",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := mpasv1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      tc.repository,
+					Namespace: "default",
+				},
+				Spec: mpasv1alpha1.RepositorySpec{
+					Provider: ProviderType,
+					Owner:    "owner",
+					Credentials: mpasv1alpha1.Credentials{
+						SecretRef: corev1.LocalObjectReference{
+							Name: secret.Name,
+						},
+					},
+					DefaultBranch:            tc.defaultBranch,
+					Domain:                   strings.TrimPrefix(server.URL(), "http://"),
+					Insecure:                 true,
+					ExistingRepositoryPolicy: tc.policy,
+				},
+			}
+
+			info, err := c.CreateRepository(context.Background(), obj)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, obj.GetRepositoryURL(), info.HTTPSURL)
+			assert.False(t, info.Created)
+		})
+	}
+}
+
+func TestCreateRepositoryImportsSource(t *testing.T) {
+	server := gitserver.New(t.TempDir())
+	defer server.Close()
+
+	_, err := server.InitRepository("owner/source")
+	require.NoError(t, err)
+	target, err := server.InitRepository("owner/target")
+	require.NoError(t, err)
+	pushInitialCommit(t, server.URL()+"/owner/source", "main")
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "auth-secret",
+			Namespace: "default",
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	c := NewClient(fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build())
+
+	obj := mpasv1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "target",
+			Namespace: "default",
+		},
+		Spec: mpasv1alpha1.RepositorySpec{
+			Provider: ProviderType,
+			Owner:    "owner",
+			Credentials: mpasv1alpha1.Credentials{
+				SecretRef: corev1.LocalObjectReference{
+					Name: secret.Name,
+				},
+			},
+			DefaultBranch:            "main",
+			Domain:                   strings.TrimPrefix(server.URL(), "http://"),
+			Insecure:                 true,
+			ExistingRepositoryPolicy: mpasv1alpha1.ExistingRepositoryPolicyAdopt,
+			Source: &mpasv1alpha1.RepositorySource{
+				URL: server.URL() + "/owner/source",
+			},
+		},
+	}
+
+	_, err = c.CreateRepository(context.Background(), obj)
+	require.NoError(t, err)
+
+	_, err = target.Reference(plumbing.NewBranchReferenceName("main"), true)
+	assert.NoError(t, err)
+
+	obj.Spec.Source.Mirror = true
+	_, err = c.CreateRepository(context.Background(), obj)
+	assert.NoError(t, err, "a repository which isn't empty is adopted as is")
+}
+
+func TestUnsupportedOperations(t *testing.T) {
+	c := NewClient(nil)
+
+	assert.ErrorIs(t, c.CreateBranchProtection(context.Background(), mpasv1alpha1.Repository{}), providers.ErrNotSupported)
+	assert.ErrorIs(t, c.ReconcilePermissions(context.Background(), mpasv1alpha1.Repository{}), providers.ErrNotSupported)
+	assert.ErrorIs(t, c.ReconcileWebhooks(context.Background(), mpasv1alpha1.Repository{}, nil), providers.ErrNotSupported)
+	_, err := c.AddDeployKey(context.Background(), mpasv1alpha1.Repository{}, providers.DeployKey{})
+	assert.ErrorIs(t, err, providers.ErrNotSupported)
+	assert.ErrorIs(t, c.DeleteDeployKey(context.Background(), mpasv1alpha1.Repository{}, "1"), providers.ErrNotSupported)
+}
+
+func pushInitialCommit(t *testing.T, url, branch string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	r, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("readme"), 0o600))
+
+	w, err := r.Worktree()
+	require.NoError(t, err)
+	_, err = w.Add("README.md")
+	require.NoError(t, err)
+
+	commit, err := w.Commit("initial commit", &git.CommitOptions{
+		Author: &object.Signature{
+			Name:  "test",
+			Email: "test@example.com",
+			When:  time.Now(),
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = r.CreateRemote(&config.RemoteConfig{
+		Name: git.DefaultRemoteName,
+		URLs: []string{url},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, r.Push(&git.PushOptions{
+		RefSpecs: []config.RefSpec{config.RefSpec(commit.String() + ":refs/heads/" + branch)},
+	}))
+}
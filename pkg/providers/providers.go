@@ -3,6 +3,8 @@ package providers
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
 
 	deliveryv1alpha1 "github.com/open-component-model/git-controller/apis/delivery/v1alpha1"
 	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
@@ -16,9 +18,83 @@ const (
 
 var ErrNotSupported = errors.New("functionality not supported by provider")
 
+// RepositoryInfo describes a remote repository after it has been created or adopted.
+type RepositoryInfo struct {
+	// ID is the provider's identifier of the repository.
+	ID            string
+	HTTPSURL      string
+	SSHURL        string
+	WebURL        string
+	DefaultBranch string
+	Visibility    string
+	// Created is false if an existing repository has been adopted.
+	Created bool
+}
+
+// PullRequest describes a pull request opened by a provider.
+type PullRequest struct {
+	// ID is the number of the pull request.
+	ID int
+	// URL links to the web page of the pull request.
+	URL string
+}
+
+// JoinWebURL appends the path elements to the web URL of the repository. It is empty if the web URL or any of the
+// elements is unknown.
+func JoinWebURL(repository mpasv1alpha1.Repository, elems ...string) string {
+	webURL := strings.TrimSuffix(repository.Status.WebURL, "/")
+	if webURL == "" || slices.Contains(elems, "") {
+		return ""
+	}
+
+	return webURL + "/" + strings.Join(elems, "/")
+}
+
+// File is a file of the initial commit of a new repository.
+type File struct {
+	Path    string
+	Content []byte
+}
+
+// DeployKey is a public key granting access to a single repository.
+type DeployKey struct {
+	Title string
+	// PublicKey is in authorized_keys format.
+	PublicKey []byte
+	ReadOnly  bool
+}
+
+// ContentLoader provides the files of the initial commit of a repository created by a provider.
+type ContentLoader interface {
+	Load(ctx context.Context, obj mpasv1alpha1.Repository) ([]File, error)
+}
+
 // Provider adds the ability to create repositories and pull requests.
 type Provider interface {
-	CreateRepository(ctx context.Context, obj mpasv1alpha1.Repository) error
-	CreatePullRequest(ctx context.Context, branch string, sync deliveryv1alpha1.Sync, repository mpasv1alpha1.Repository) (int, error)
+	// CreateRepository creates or adopts the remote repository and describes it.
+	CreateRepository(ctx context.Context, obj mpasv1alpha1.Repository) (RepositoryInfo, error)
+	CreatePullRequest(ctx context.Context, branch string, sync deliveryv1alpha1.Sync, repository mpasv1alpha1.Repository) (PullRequest, error)
 	CreateBranchProtection(ctx context.Context, obj mpasv1alpha1.Repository) error
+	// CreateCommitStatus reports the given status for the head commit of a pull request.
+	CreateCommitStatus(ctx context.Context, repository mpasv1alpha1.Repository, pullRequestID int, status deliveryv1alpha1.CommitStatus) error
+	// ArchiveRepository marks the remote repository as archived.
+	ArchiveRepository(ctx context.Context, obj mpasv1alpha1.Repository) error
+	// DeleteRepository removes the remote repository. A repository which no longer exists is not an error.
+	DeleteRepository(ctx context.Context, obj mpasv1alpha1.Repository) error
+	// GetRepositorySettings reads the current settings of the remote repository.
+	GetRepositorySettings(ctx context.Context, obj mpasv1alpha1.Repository) (RepositorySettings, error)
+	// UpdateRepositorySettings applies all non-empty settings to the remote repository.
+	UpdateRepositorySettings(ctx context.Context, obj mpasv1alpha1.Repository, settings RepositorySettings) error
+	// ReconcilePermissions grants the declared users and teams their role and, if pruning, revokes all other access.
+	ReconcilePermissions(ctx context.Context, obj mpasv1alpha1.Repository) error
+	// ReconcileWebhooks creates or updates the given webhooks and deletes those listed in the status which are no
+	// longer desired. Webhooks are matched by their URL.
+	ReconcileWebhooks(ctx context.Context, obj mpasv1alpha1.Repository, hooks []Webhook) error
+	// AddDeployKey registers a public key as deploy key and returns its ID.
+	AddDeployKey(ctx context.Context, obj mpasv1alpha1.Repository, key DeployKey) (string, error)
+	// DeleteDeployKey removes a deploy key. A key which no longer exists is not an error.
+	DeleteDeployKey(ctx context.Context, obj mpasv1alpha1.Repository, id string) error
+	// CommitURL links to a commit on the web page of the repository. It is empty if the web URL of the repository
+	// is unknown or the provider has no web interface.
+	CommitURL(repository mpasv1alpha1.Repository, commit string) string
 }
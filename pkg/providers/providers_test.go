@@ -0,0 +1,56 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+)
+
+func TestJoinWebURL(t *testing.T) {
+	testCases := []struct {
+		name     string
+		webURL   string
+		elems    []string
+		expected string
+	}{
+		{
+			name:     "commit",
+			webURL:   "https://github.com/open-component-model/podinfo",
+			elems:    []string{"commit", "2f0e0c1d"},
+			expected: "https://github.com/open-component-model/podinfo/commit/2f0e0c1d",
+		},
+		{
+			name:     "trailing slash",
+			webURL:   "https://gitea.example.com/open-component-model/podinfo/",
+			elems:    []string{"commit", "2f0e0c1d"},
+			expected: "https://gitea.example.com/open-component-model/podinfo/commit/2f0e0c1d",
+		},
+		{
+			name:     "several elements",
+			webURL:   "https://gitlab.com/open-component-model/podinfo",
+			elems:    []string{"-", "commit", "2f0e0c1d"},
+			expected: "https://gitlab.com/open-component-model/podinfo/-/commit/2f0e0c1d",
+		},
+		{
+			name:  "unknown web URL",
+			elems: []string{"commit", "2f0e0c1d"},
+		},
+		{
+			name:   "unknown commit",
+			webURL: "https://github.com/open-component-model/podinfo",
+			elems:  []string{"commit", ""},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := mpasv1alpha1.Repository{
+				Status: mpasv1alpha1.RepositoryStatus{WebURL: tt.webURL},
+			}
+
+			assert.Equal(t, tt.expected, JoinWebURL(repository, tt.elems...))
+		})
+	}
+}
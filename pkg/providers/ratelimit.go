@@ -0,0 +1,133 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/open-component-model/git-controller/pkg/metrics"
+)
+
+// defaultRateLimitRetry is used if a rate limited response doesn't say when the quota resets.
+const defaultRateLimitRetry = time.Minute
+
+// RateLimitError is returned when the API quota of a provider has been exhausted.
+type RateLimitError struct {
+	Provider string
+	// ResetAt is the time at which the provider accepts requests again.
+	ResetAt time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s API rate limit exceeded, quota resets at %s", e.Provider, e.ResetAt.Format(time.RFC3339))
+}
+
+// RetryAfter returns the duration until the quota resets. It is never less than a second.
+func (e *RateLimitError) RetryAfter() time.Duration {
+	if d := time.Until(e.ResetAt); d > time.Second {
+		return d
+	}
+
+	return time.Second
+}
+
+// RetryAfter returns how long to wait before retrying if err is caused by a RateLimitError.
+func RetryAfter(err error) (time.Duration, bool) {
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		return 0, false
+	}
+
+	return rateLimitErr.RetryAfter(), true
+}
+
+// rateLimitTransport records the remaining quota of every response and turns rate limited responses into a
+// RateLimitError.
+type rateLimitTransport struct {
+	provider string
+	next     http.RoundTripper
+	now      func() time.Time
+}
+
+// NewRateLimitTransport wraps next to detect rate limiting by the provider's API. GitHub and Gitea send
+// `X-RateLimit-*` headers, GitLab sends `RateLimit-*` headers. All of them may send `Retry-After`.
+func NewRateLimitTransport(provider string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &rateLimitTransport{
+		provider: provider,
+		next:     next,
+		now:      time.Now,
+	}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining, hasRemaining := rateLimitHeader(resp.Header, "Remaining")
+	if hasRemaining {
+		metrics.ProviderRateLimitRemaining.WithLabelValues(t.provider).Set(float64(remaining))
+	}
+
+	retryAfter := resp.Header.Get("Retry-After")
+
+	limited := resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode == http.StatusForbidden && ((hasRemaining && remaining == 0) || retryAfter != ""))
+	if !limited {
+		return resp, nil
+	}
+
+	// The body is not handed to the caller, so it has to be drained for the connection to be reused.
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	return nil, &RateLimitError{
+		Provider: t.provider,
+		ResetAt:  t.resetAt(resp.Header, retryAfter),
+	}
+}
+
+// resetAt determines when the quota resets, preferring Retry-After over the reset header.
+func (t *rateLimitTransport) resetAt(header http.Header, retryAfter string) time.Time {
+	now := t.now()
+
+	if retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			return now.Add(time.Duration(seconds) * time.Second)
+		}
+
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			return date
+		}
+	}
+
+	if reset, ok := rateLimitHeader(header, "Reset"); ok {
+		return time.Unix(reset, 0)
+	}
+
+	return now.Add(defaultRateLimitRetry)
+}
+
+// rateLimitHeader reads an integer rate limit header in either the `X-RateLimit-` or `RateLimit-` form.
+func rateLimitHeader(header http.Header, name string) (int64, bool) {
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		value := header.Get(prefix + name)
+		if value == "" {
+			continue
+		}
+
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return v, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,120 @@
+package providers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-component-model/git-controller/pkg/metrics"
+)
+
+func TestRateLimitTransport(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	reset := now.Add(30 * time.Minute)
+
+	testCases := []struct {
+		name      string
+		status    int
+		header    map[string]string
+		resetAt   time.Time
+		remaining float64
+	}{
+		{
+			name:   "successful responses pass through",
+			status: http.StatusOK,
+			header: map[string]string{
+				"X-RateLimit-Remaining": "42",
+			},
+			remaining: 42,
+		},
+		{
+			name:   "forbidden responses without exhausted quota pass through",
+			status: http.StatusForbidden,
+			header: map[string]string{
+				"X-RateLimit-Remaining": "10",
+			},
+			remaining: 10,
+		},
+		{
+			name:   "exhausted quota uses the reset header",
+			status: http.StatusForbidden,
+			header: map[string]string{
+				"X-RateLimit-Remaining": "0",
+				"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10),
+			},
+			resetAt: reset,
+		},
+		{
+			name:   "gitlab headers are understood",
+			status: http.StatusTooManyRequests,
+			header: map[string]string{
+				"RateLimit-Remaining": "0",
+				"RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10),
+			},
+			resetAt: reset,
+		},
+		{
+			name:   "retry after takes precedence",
+			status: http.StatusForbidden,
+			header: map[string]string{
+				"Retry-After":       "60",
+				"X-RateLimit-Reset": strconv.FormatInt(reset.Unix(), 10),
+			},
+			resetAt: now.Add(time.Minute),
+		},
+		{
+			name:    "too many requests without headers retries after the default",
+			status:  http.StatusTooManyRequests,
+			resetAt: now.Add(defaultRateLimitRetry),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tc.header {
+					w.Header().Set(k, v)
+				}
+
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			transport := NewRateLimitTransport("test", http.DefaultTransport)
+			transport.(*rateLimitTransport).now = func() time.Time { return now }
+
+			resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+
+			if tc.resetAt.IsZero() {
+				require.NoError(t, err)
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.status, resp.StatusCode)
+				assert.Equal(t, tc.remaining, testutil.ToFloat64(metrics.ProviderRateLimitRemaining.WithLabelValues("test")))
+
+				return
+			}
+
+			var rateLimitErr *RateLimitError
+			require.True(t, errors.As(err, &rateLimitErr), "expected a rate limit error, got %v", err)
+			assert.Equal(t, "test", rateLimitErr.Provider)
+			assert.True(t, tc.resetAt.Equal(rateLimitErr.ResetAt), "expected reset at %s, got %s", tc.resetAt, rateLimitErr.ResetAt)
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	_, ok := RetryAfter(errors.New("boom"))
+	assert.False(t, ok)
+
+	retryAfter, ok := RetryAfter(&RateLimitError{ResetAt: time.Now().Add(-time.Minute)})
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, retryAfter)
+}
@@ -0,0 +1,315 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	deliveryv1alpha1 "github.com/open-component-model/git-controller/apis/delivery/v1alpha1"
+	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+	"github.com/open-component-model/git-controller/pkg/metrics"
+	"github.com/open-component-model/git-controller/pkg/tracing"
+)
+
+// UnknownProviderError is returned when a Repository refers to a provider that is not registered.
+type UnknownProviderError struct {
+	Name      string
+	Supported []string
+}
+
+func (e *UnknownProviderError) Error() string {
+	return fmt.Sprintf("unknown provider '%s', supported providers are: %s", e.Name, strings.Join(e.Supported, ", "))
+}
+
+// Registry keeps track of providers by their name.
+type Registry struct {
+	mu        sync.RWMutex
+	providers map[string]Provider
+}
+
+// NewRegistry creates an empty provider registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		providers: make(map[string]Provider),
+	}
+}
+
+// Register adds a provider under the given name. Registering the same name twice is an error.
+func (r *Registry) Register(name string, provider Provider) error {
+	if name == "" {
+		return fmt.Errorf("provider name must not be empty")
+	}
+
+	if provider == nil {
+		return fmt.Errorf("provider '%s' must not be nil", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.providers[name]; ok {
+		return fmt.Errorf("provider '%s' is already registered", name)
+	}
+
+	r.providers[name] = provider
+
+	return nil
+}
+
+// Get returns the provider registered under name or an UnknownProviderError.
+func (r *Registry) Get(name string) (Provider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	provider, ok := r.providers[name]
+	if !ok {
+		return nil, &UnknownProviderError{
+			Name:      name,
+			Supported: r.names(),
+		}
+	}
+
+	return provider, nil
+}
+
+// Names returns the sorted list of registered provider names.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.names()
+}
+
+func (r *Registry) names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Dispatcher selects the provider configured in a Repository's spec and forwards calls to it.
+type Dispatcher struct {
+	registry *Registry
+}
+
+// NewDispatcher creates a Provider which dispatches calls to the providers found in registry.
+func NewDispatcher(registry *Registry) *Dispatcher {
+	return &Dispatcher{
+		registry: registry,
+	}
+}
+
+var _ Provider = &Dispatcher{}
+
+func (d *Dispatcher) CreateRepository(ctx context.Context, obj mpasv1alpha1.Repository) (RepositoryInfo, error) {
+	provider, err := d.registry.Get(obj.Spec.Provider)
+	if err != nil {
+		return RepositoryInfo{}, err
+	}
+
+	ctx, done := startCall(ctx, obj.Spec.Provider, "create_repository")
+	info, err := provider.CreateRepository(ctx, obj)
+	done(err)
+
+	return info, err
+}
+
+func (d *Dispatcher) CreatePullRequest(
+	ctx context.Context,
+	branch string,
+	sync deliveryv1alpha1.Sync,
+	repository mpasv1alpha1.Repository,
+) (PullRequest, error) {
+	provider, err := d.registry.Get(repository.Spec.Provider)
+	if err != nil {
+		return PullRequest{}, err
+	}
+
+	ctx, done := startCall(ctx, repository.Spec.Provider, "create_pull_request")
+	pr, err := provider.CreatePullRequest(ctx, branch, sync, repository)
+	done(err)
+
+	return pr, err
+}
+
+func (d *Dispatcher) CreateBranchProtection(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	provider, err := d.registry.Get(obj.Spec.Provider)
+	if err != nil {
+		return err
+	}
+
+	ctx, done := startCall(ctx, obj.Spec.Provider, "create_branch_protection")
+	err = provider.CreateBranchProtection(ctx, obj)
+	done(err)
+
+	return err
+}
+
+func (d *Dispatcher) CreateCommitStatus(
+	ctx context.Context,
+	repository mpasv1alpha1.Repository,
+	pullRequestID int,
+	status deliveryv1alpha1.CommitStatus,
+) error {
+	provider, err := d.registry.Get(repository.Spec.Provider)
+	if err != nil {
+		return err
+	}
+
+	ctx, done := startCall(ctx, repository.Spec.Provider, "create_commit_status")
+	err = provider.CreateCommitStatus(ctx, repository, pullRequestID, status)
+	done(err)
+
+	return err
+}
+
+func (d *Dispatcher) ArchiveRepository(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	provider, err := d.registry.Get(obj.Spec.Provider)
+	if err != nil {
+		return err
+	}
+
+	ctx, done := startCall(ctx, obj.Spec.Provider, "archive_repository")
+	err = provider.ArchiveRepository(ctx, obj)
+	done(err)
+
+	return err
+}
+
+func (d *Dispatcher) DeleteRepository(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	provider, err := d.registry.Get(obj.Spec.Provider)
+	if err != nil {
+		return err
+	}
+
+	ctx, done := startCall(ctx, obj.Spec.Provider, "delete_repository")
+	err = provider.DeleteRepository(ctx, obj)
+	done(err)
+
+	return err
+}
+
+func (d *Dispatcher) GetRepositorySettings(ctx context.Context, obj mpasv1alpha1.Repository) (RepositorySettings, error) {
+	provider, err := d.registry.Get(obj.Spec.Provider)
+	if err != nil {
+		return RepositorySettings{}, err
+	}
+
+	ctx, done := startCall(ctx, obj.Spec.Provider, "get_repository_settings")
+	settings, err := provider.GetRepositorySettings(ctx, obj)
+	done(err)
+
+	return settings, err
+}
+
+func (d *Dispatcher) UpdateRepositorySettings(ctx context.Context, obj mpasv1alpha1.Repository, settings RepositorySettings) error {
+	provider, err := d.registry.Get(obj.Spec.Provider)
+	if err != nil {
+		return err
+	}
+
+	ctx, done := startCall(ctx, obj.Spec.Provider, "update_repository_settings")
+	err = provider.UpdateRepositorySettings(ctx, obj, settings)
+	done(err)
+
+	return err
+}
+
+func (d *Dispatcher) ReconcilePermissions(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	provider, err := d.registry.Get(obj.Spec.Provider)
+	if err != nil {
+		return err
+	}
+
+	ctx, done := startCall(ctx, obj.Spec.Provider, "reconcile_permissions")
+	err = provider.ReconcilePermissions(ctx, obj)
+	done(err)
+
+	return err
+}
+
+func (d *Dispatcher) ReconcileWebhooks(ctx context.Context, obj mpasv1alpha1.Repository, hooks []Webhook) error {
+	provider, err := d.registry.Get(obj.Spec.Provider)
+	if err != nil {
+		return err
+	}
+
+	ctx, done := startCall(ctx, obj.Spec.Provider, "reconcile_webhooks")
+	err = provider.ReconcileWebhooks(ctx, obj, hooks)
+	done(err)
+
+	return err
+}
+
+func (d *Dispatcher) AddDeployKey(ctx context.Context, obj mpasv1alpha1.Repository, key DeployKey) (string, error) {
+	provider, err := d.registry.Get(obj.Spec.Provider)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, done := startCall(ctx, obj.Spec.Provider, "add_deploy_key")
+	id, err := provider.AddDeployKey(ctx, obj, key)
+	done(err)
+
+	return id, err
+}
+
+func (d *Dispatcher) DeleteDeployKey(ctx context.Context, obj mpasv1alpha1.Repository, id string) error {
+	provider, err := d.registry.Get(obj.Spec.Provider)
+	if err != nil {
+		return err
+	}
+
+	ctx, done := startCall(ctx, obj.Spec.Provider, "delete_deploy_key")
+	err = provider.DeleteDeployKey(ctx, obj, id)
+	done(err)
+
+	return err
+}
+
+func (d *Dispatcher) CommitURL(repository mpasv1alpha1.Repository, commit string) string {
+	provider, err := d.registry.Get(repository.Spec.Provider)
+	if err != nil {
+		return ""
+	}
+
+	return provider.CommitURL(repository, commit)
+}
+
+// startCall starts a span for a provider call. The returned function ends the span and records the call's metrics.
+func startCall(ctx context.Context, provider, operation string) (context.Context, func(error)) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "provider."+operation,
+		attribute.String("provider", provider),
+	)
+
+	return ctx, func(err error) {
+		observe(provider, operation, start, err)
+
+		if errors.Is(err, ErrNotSupported) {
+			span.SetAttributes(attribute.Bool("provider.unsupported", true))
+			err = nil
+		}
+
+		tracing.End(span, err)
+	}
+}
+
+// observe records a provider API call in the metrics. Operations a provider doesn't support are not errors.
+func observe(provider, operation string, start time.Time, err error) {
+	if errors.Is(err, ErrNotSupported) {
+		err = nil
+	}
+
+	metrics.ObserveProviderRequest(provider, operation, start, err)
+}
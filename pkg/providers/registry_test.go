@@ -0,0 +1,209 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	deliveryv1alpha1 "github.com/open-component-model/git-controller/apis/delivery/v1alpha1"
+	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+	"github.com/open-component-model/git-controller/pkg/metrics"
+)
+
+type recordingProvider struct {
+	called int
+}
+
+func (p *recordingProvider) CreateRepository(ctx context.Context, obj mpasv1alpha1.Repository) (RepositoryInfo, error) {
+	p.called++
+
+	return RepositoryInfo{}, nil
+}
+
+func (p *recordingProvider) CreatePullRequest(ctx context.Context, branch string, sync deliveryv1alpha1.Sync, repository mpasv1alpha1.Repository) (PullRequest, error) {
+	p.called++
+
+	return PullRequest{ID: 1}, nil
+}
+
+func (p *recordingProvider) CreateBranchProtection(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	p.called++
+
+	return nil
+}
+
+func (p *recordingProvider) CreateCommitStatus(
+	ctx context.Context,
+	repository mpasv1alpha1.Repository,
+	pullRequestID int,
+	status deliveryv1alpha1.CommitStatus,
+) error {
+	p.called++
+
+	return nil
+}
+
+func (p *recordingProvider) ArchiveRepository(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	p.called++
+
+	return nil
+}
+
+func (p *recordingProvider) DeleteRepository(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	p.called++
+
+	return nil
+}
+
+func (p *recordingProvider) GetRepositorySettings(ctx context.Context, obj mpasv1alpha1.Repository) (RepositorySettings, error) {
+	p.called++
+
+	return RepositorySettings{}, nil
+}
+
+func (p *recordingProvider) UpdateRepositorySettings(ctx context.Context, obj mpasv1alpha1.Repository, settings RepositorySettings) error {
+	p.called++
+
+	return nil
+}
+
+func (p *recordingProvider) ReconcilePermissions(ctx context.Context, obj mpasv1alpha1.Repository) error {
+	p.called++
+
+	return nil
+}
+
+func (p *recordingProvider) ReconcileWebhooks(ctx context.Context, obj mpasv1alpha1.Repository, hooks []Webhook) error {
+	p.called++
+
+	return nil
+}
+
+func (p *recordingProvider) AddDeployKey(ctx context.Context, obj mpasv1alpha1.Repository, key DeployKey) (string, error) {
+	p.called++
+
+	return "1", nil
+}
+
+func (p *recordingProvider) DeleteDeployKey(ctx context.Context, obj mpasv1alpha1.Repository, id string) error {
+	p.called++
+
+	return nil
+}
+
+func (p *recordingProvider) CommitURL(repository mpasv1alpha1.Repository, commit string) string {
+	p.called++
+
+	return "commit/" + commit
+}
+
+func TestRegistryRegister(t *testing.T) {
+	registry := NewRegistry()
+
+	require.NoError(t, registry.Register("github", &recordingProvider{}))
+	require.NoError(t, registry.Register("gitea", &recordingProvider{}))
+	assert.Error(t, registry.Register("github", &recordingProvider{}))
+	assert.Error(t, registry.Register("", &recordingProvider{}))
+	assert.Error(t, registry.Register("gitlab", nil))
+
+	assert.Equal(t, []string{"gitea", "github"}, registry.Names())
+}
+
+func TestDispatcher(t *testing.T) {
+	github := &recordingProvider{}
+	gitea := &recordingProvider{}
+
+	registry := NewRegistry()
+	require.NoError(t, registry.Register("github", github))
+	require.NoError(t, registry.Register("gitea", gitea))
+
+	dispatcher := NewDispatcher(registry)
+
+	repository := mpasv1alpha1.Repository{
+		Spec: mpasv1alpha1.RepositorySpec{
+			Provider: "gitea",
+		},
+	}
+
+	_, err := dispatcher.CreateRepository(context.Background(), repository)
+	require.NoError(t, err)
+	require.NoError(t, dispatcher.CreateBranchProtection(context.Background(), repository))
+	pr, err := dispatcher.CreatePullRequest(context.Background(), "branch", deliveryv1alpha1.Sync{}, repository)
+	require.NoError(t, err)
+	assert.Equal(t, 1, pr.ID)
+	require.NoError(t, dispatcher.CreateCommitStatus(context.Background(), repository, pr.ID, deliveryv1alpha1.CommitStatus{}))
+	require.NoError(t, dispatcher.ArchiveRepository(context.Background(), repository))
+	require.NoError(t, dispatcher.DeleteRepository(context.Background(), repository))
+	_, err = dispatcher.GetRepositorySettings(context.Background(), repository)
+	require.NoError(t, err)
+	require.NoError(t, dispatcher.UpdateRepositorySettings(context.Background(), repository, RepositorySettings{}))
+	require.NoError(t, dispatcher.ReconcilePermissions(context.Background(), repository))
+	require.NoError(t, dispatcher.ReconcileWebhooks(context.Background(), repository, nil))
+	keyID, err := dispatcher.AddDeployKey(context.Background(), repository, DeployKey{})
+	require.NoError(t, err)
+	require.NoError(t, dispatcher.DeleteDeployKey(context.Background(), repository, keyID))
+	assert.Equal(t, "commit/2f0e0c1d", dispatcher.CommitURL(repository, "2f0e0c1d"))
+
+	assert.Equal(t, 13, gitea.called)
+	assert.Zero(t, github.called)
+
+	repository.Spec.Provider = "bitbucket"
+	_, err = dispatcher.CreateRepository(context.Background(), repository)
+
+	var unknown *UnknownProviderError
+	require.True(t, errors.As(err, &unknown))
+	assert.Equal(t, "bitbucket", unknown.Name)
+	assert.Equal(t, []string{"gitea", "github"}, unknown.Supported)
+	assert.EqualError(t, err, "unknown provider 'bitbucket', supported providers are: gitea, github")
+	assert.Empty(t, dispatcher.CommitURL(repository, "2f0e0c1d"))
+}
+
+func TestObserveProviderRequests(t *testing.T) {
+	errorsFor := func(operation string) float64 {
+		return testutil.ToFloat64(metrics.ProviderRequestErrors.WithLabelValues("observed", operation))
+	}
+
+	observe("observed", "create_repository", time.Now(), errors.New("boom"))
+	observe("observed", "create_branch_protection", time.Now(), ErrNotSupported)
+	observe("observed", "delete_repository", time.Now(), nil)
+
+	assert.Equal(t, float64(1), errorsFor("create_repository"))
+	assert.Equal(t, float64(0), errorsFor("create_branch_protection"))
+	assert.Equal(t, float64(0), errorsFor("delete_repository"))
+}
+
+func TestStartCallRecordsSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	_, done := startCall(context.Background(), "traced", "create_repository")
+	done(errors.New("boom"))
+
+	_, done = startCall(context.Background(), "traced", "create_branch_protection")
+	done(ErrNotSupported)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, "provider.create_repository", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Contains(t, spans[0].Attributes(), attribute.String("provider", "traced"))
+
+	assert.Equal(t, "provider.create_branch_protection", spans[1].Name())
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+	assert.Contains(t, spans[1].Attributes(), attribute.Bool("provider.unsupported", true))
+}
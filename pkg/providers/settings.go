@@ -0,0 +1,162 @@
+package providers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+)
+
+// RepositorySettings contains the settings of a remote repository which are checked for drift.
+// Empty values are unknown or unmanaged and aren't compared.
+type RepositorySettings struct {
+	Visibility    string
+	DefaultBranch string
+	Description   string
+	Homepage      string
+	// Topics are nil if unmanaged or unknown.
+	Topics           []string
+	HasIssues        *bool
+	HasWiki          *bool
+	HasProjects      *bool
+	AllowMergeCommit *bool
+	AllowSquashMerge *bool
+	AllowRebaseMerge *bool
+	// BranchProtection maps the patterns of the Repository's rules to the protection of the matching branches.
+	// A nil rule means the branch isn't protected. Patterns the provider can't protect are left out and the map is
+	// nil if the provider doesn't support branch protection.
+	BranchProtection map[string]*mpasv1alpha1.BranchProtectionRule
+}
+
+// Empty returns true if none of the settings is set.
+func (s RepositorySettings) Empty() bool {
+	return s.Visibility == "" && s.DefaultBranch == "" && s.Description == "" && s.Homepage == "" &&
+		s.Topics == nil && s.HasIssues == nil && s.HasWiki == nil && s.HasProjects == nil &&
+		s.AllowMergeCommit == nil && s.AllowSquashMerge == nil && s.AllowRebaseMerge == nil &&
+		s.BranchProtection == nil
+}
+
+// DesiredMetadata returns the descriptive settings and feature toggles of the Repository's spec.
+func DesiredMetadata(obj mpasv1alpha1.Repository) RepositorySettings {
+	settings := RepositorySettings{
+		Description: obj.Spec.Description,
+		Homepage:    obj.Spec.Homepage,
+		Topics:      obj.Spec.Topics,
+	}
+
+	if features := obj.Spec.Features; features != nil {
+		settings.HasIssues = features.Issues
+		settings.HasWiki = features.Wiki
+		settings.HasProjects = features.Projects
+	}
+
+	if methods := obj.Spec.MergeMethods; methods != nil {
+		settings.AllowMergeCommit = methods.Merge
+		settings.AllowSquashMerge = methods.Squash
+		settings.AllowRebaseMerge = methods.Rebase
+	}
+
+	return settings
+}
+
+// DesiredSettings returns the settings a remote repository should have according to the Repository's spec.
+func DesiredSettings(obj mpasv1alpha1.Repository) RepositorySettings {
+	settings := DesiredMetadata(obj)
+	settings.Visibility = obj.Spec.Visibility
+	settings.DefaultBranch = obj.Spec.DefaultBranch
+	settings.BranchProtection = map[string]*mpasv1alpha1.BranchProtectionRule{}
+
+	for _, rule := range BranchProtectionRules(obj) {
+		rule := rule
+		settings.BranchProtection[rule.Pattern] = &rule
+	}
+
+	return settings
+}
+
+// Drift lists the differences between the desired and the actual settings. The returned settings contain
+// only the fields which need to be corrected.
+func Drift(desired, actual RepositorySettings) ([]string, RepositorySettings) {
+	var (
+		drift      []string
+		correction RepositorySettings
+	)
+
+	compare := func(name, want, got string, field *string) {
+		if want == "" || got == "" || want == got {
+			return
+		}
+
+		drift = append(drift, fmt.Sprintf("%s is '%s' instead of '%s'", name, got, want))
+		*field = want
+	}
+
+	compare("visibility", desired.Visibility, actual.Visibility, &correction.Visibility)
+	compare("default branch", desired.DefaultBranch, actual.DefaultBranch, &correction.DefaultBranch)
+	compare("description", desired.Description, actual.Description, &correction.Description)
+	compare("homepage", desired.Homepage, actual.Homepage, &correction.Homepage)
+
+	if desired.Topics != nil && actual.Topics != nil && !sameNames(desired.Topics, actual.Topics) {
+		drift = append(drift, fmt.Sprintf("topics are '%s' instead of '%s'", strings.Join(actual.Topics, ","), strings.Join(desired.Topics, ",")))
+		correction.Topics = desired.Topics
+	}
+
+	compareBool := func(name string, want, got *bool, field **bool) {
+		if want == nil || got == nil || *want == *got {
+			return
+		}
+
+		drift = append(drift, fmt.Sprintf("%s is %t instead of %t", name, *got, *want))
+		*field = want
+	}
+
+	compareBool("issues", desired.HasIssues, actual.HasIssues, &correction.HasIssues)
+	compareBool("wiki", desired.HasWiki, actual.HasWiki, &correction.HasWiki)
+	compareBool("projects", desired.HasProjects, actual.HasProjects, &correction.HasProjects)
+	compareBool("merge commits", desired.AllowMergeCommit, actual.AllowMergeCommit, &correction.AllowMergeCommit)
+	compareBool("squash merging", desired.AllowSquashMerge, actual.AllowSquashMerge, &correction.AllowSquashMerge)
+	compareBool("rebase merging", desired.AllowRebaseMerge, actual.AllowRebaseMerge, &correction.AllowRebaseMerge)
+
+	if protection := branchProtectionDrift(desired.BranchProtection, actual.BranchProtection); len(protection) > 0 {
+		drift = append(drift, protection...)
+		correction.BranchProtection = desired.BranchProtection
+	}
+
+	return drift, correction
+}
+
+// branchProtectionDrift compares the protection of every branch pattern reported by the provider with its rule.
+func branchProtectionDrift(desired, actual map[string]*mpasv1alpha1.BranchProtectionRule) []string {
+	if desired == nil || actual == nil {
+		return nil
+	}
+
+	patterns := make([]string, 0, len(desired))
+	for pattern := range desired {
+		patterns = append(patterns, pattern)
+	}
+
+	slices.Sort(patterns)
+
+	var drift []string
+
+	for _, pattern := range patterns {
+		got, ok := actual[pattern]
+		if !ok {
+			continue
+		}
+
+		if got == nil {
+			drift = append(drift, fmt.Sprintf("branch '%s' is not protected", pattern))
+
+			continue
+		}
+
+		if settings := DifferentSettings(*desired[pattern], *got); len(settings) > 0 {
+			drift = append(drift, fmt.Sprintf("branch protection of '%s' differs in %s", pattern, strings.Join(settings, ", ")))
+		}
+	}
+
+	return drift
+}
@@ -0,0 +1,151 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+)
+
+func TestDrift(t *testing.T) {
+	repository := mpasv1alpha1.Repository{
+		Spec: mpasv1alpha1.RepositorySpec{
+			Visibility:    "private",
+			DefaultBranch: "main",
+		},
+	}
+	desired := DesiredSettings(repository)
+
+	drift, correction := Drift(desired, RepositorySettings{
+		Visibility:       "public",
+		DefaultBranch:    "main",
+		Description:      "changed outside the controller",
+		BranchProtection: map[string]*mpasv1alpha1.BranchProtectionRule{"main": nil},
+	})
+
+	assert.Equal(t, []string{
+		"visibility is 'public' instead of 'private'",
+		"branch 'main' is not protected",
+	}, drift)
+	assert.Equal(t, RepositorySettings{
+		Visibility:       "private",
+		BranchProtection: desired.BranchProtection,
+	}, correction)
+
+	drift, _ = Drift(desired, RepositorySettings{Visibility: "private"})
+	assert.Empty(t, drift)
+}
+
+func TestDriftBranchProtection(t *testing.T) {
+	repository := mpasv1alpha1.Repository{
+		Spec: mpasv1alpha1.RepositorySpec{
+			BranchProtection: []mpasv1alpha1.BranchProtectionRule{
+				{
+					Pattern:                      "main",
+					RequiredStatusChecks:         []string{"mpas/validation-check", "lint"},
+					RequiredApprovingReviewCount: 2,
+				},
+				{
+					Pattern: "release",
+					RestrictPushes: &mpasv1alpha1.PushRestrictions{
+						Users: []string{"release-bot"},
+					},
+				},
+				{
+					Pattern:       "release/*",
+					EnforceAdmins: true,
+				},
+			},
+		},
+	}
+	desired := DesiredSettings(repository)
+
+	// Patterns the provider doesn't report, like the wildcard here, aren't compared.
+	drift, correction := Drift(desired, RepositorySettings{
+		BranchProtection: map[string]*mpasv1alpha1.BranchProtectionRule{
+			"main": {
+				Pattern:                      "main",
+				RequiredStatusChecks:         []string{"lint", "mpas/validation-check"},
+				RequiredApprovingReviewCount: 2,
+			},
+			"release": {
+				Pattern: "release",
+				RestrictPushes: &mpasv1alpha1.PushRestrictions{
+					Users: []string{"Release-Bot"},
+				},
+			},
+		},
+	})
+	assert.Empty(t, drift)
+	assert.True(t, correction.Empty())
+
+	drift, correction = Drift(desired, RepositorySettings{
+		BranchProtection: map[string]*mpasv1alpha1.BranchProtectionRule{
+			"main": {
+				Pattern:                      "main",
+				RequiredStatusChecks:         []string{"mpas/validation-check"},
+				RequiredApprovingReviewCount: 1,
+			},
+			"release": {
+				Pattern: "release",
+			},
+		},
+	})
+	assert.Equal(t, []string{
+		"branch protection of 'main' differs in requiredStatusChecks, requiredApprovingReviewCount",
+		"branch protection of 'release' differs in restrictPushes",
+	}, drift)
+	assert.Equal(t, desired.BranchProtection, correction.BranchProtection)
+}
+
+func TestDriftMetadata(t *testing.T) {
+	enabled, disabled := true, false
+
+	desired := DesiredMetadata(mpasv1alpha1.Repository{
+		Spec: mpasv1alpha1.RepositorySpec{
+			Description: "payment service",
+			Homepage:    "https://docs.example.com/payment",
+			Topics:      []string{"mpas", "payment"},
+			Features: &mpasv1alpha1.RepositoryFeatures{
+				Wiki: &disabled,
+			},
+			MergeMethods: &mpasv1alpha1.MergeMethods{
+				Squash: &enabled,
+			},
+		},
+	})
+
+	drift, correction := Drift(desired, RepositorySettings{
+		Description:      "payment service",
+		Homepage:         "https://docs.example.com/payment",
+		Topics:           []string{"Payment", "mpas"},
+		HasIssues:        &enabled,
+		HasWiki:          &disabled,
+		AllowSquashMerge: &enabled,
+	})
+	assert.Empty(t, drift)
+	assert.True(t, correction.Empty())
+
+	drift, correction = Drift(desired, RepositorySettings{
+		Description:      "payment service",
+		Homepage:         "https://example.com",
+		Topics:           []string{"mpas"},
+		HasWiki:          &enabled,
+		AllowSquashMerge: &enabled,
+	})
+	assert.Equal(t, []string{
+		"homepage is 'https://example.com' instead of 'https://docs.example.com/payment'",
+		"topics are 'mpas' instead of 'mpas,payment'",
+		"wiki is true instead of false",
+	}, drift)
+	assert.Equal(t, RepositorySettings{
+		Homepage: "https://docs.example.com/payment",
+		Topics:   []string{"mpas", "payment"},
+		HasWiki:  &disabled,
+	}, correction)
+
+	// Settings a provider doesn't report aren't compared.
+	drift, _ = Drift(desired, RepositorySettings{Description: "payment service"})
+	assert.Empty(t, drift)
+}
@@ -0,0 +1,65 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+	"github.com/open-component-model/git-controller/pkg"
+)
+
+const (
+	sourceIdentityKey = "identity"
+	sourceUsernameKey = "username"
+	sourcePasswordKey = "password"
+)
+
+// SourceAuth reads the credentials of the Repository's import source. It returns nil if the source doesn't
+// refer to a Secret.
+func SourceAuth(ctx context.Context, c client.Client, obj mpasv1alpha1.Repository) (*pkg.Auth, error) {
+	if obj.Spec.Source == nil || obj.Spec.Source.SecretRef == nil {
+		return nil, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{
+		Name:      obj.Spec.Source.SecretRef.Name,
+		Namespace: obj.Namespace,
+	}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get source secret: %w", err)
+	}
+
+	if identity, ok := secret.Data[sourceIdentityKey]; ok {
+		return &pkg.Auth{
+			SSH: &pkg.SSH{
+				PemBytes: identity,
+				User:     string(secret.Data[sourceUsernameKey]),
+				Password: string(secret.Data[sourcePasswordKey]),
+			},
+		}, nil
+	}
+
+	return &pkg.Auth{
+		BasicAuth: &pkg.BasicAuth{
+			Username: string(secret.Data[sourceUsernameKey]),
+			Password: string(secret.Data[sourcePasswordKey]),
+		},
+	}, nil
+}
+
+// MigrationCredentials returns the username and password for provider migration APIs, which can't use SSH keys.
+func MigrationCredentials(auth *pkg.Auth) (string, string, error) {
+	if auth == nil {
+		return "", "", nil
+	}
+
+	if auth.SSH != nil {
+		return "", "", fmt.Errorf("ssh credentials are not supported for importing repositories: %w", ErrNotSupported)
+	}
+
+	return auth.BasicAuth.Username, auth.BasicAuth.Password, nil
+}
@@ -0,0 +1,62 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+)
+
+func TestSourceAuth(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "source", Namespace: "default"},
+		Data: map[string][]byte{
+			"username": []byte("user"),
+			"password": []byte("token"),
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(secret).Build()
+
+	obj := mpasv1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "default"},
+		Spec: mpasv1alpha1.RepositorySpec{
+			Source: &mpasv1alpha1.RepositorySource{
+				URL:       "https://example.com/org/repo",
+				SecretRef: &corev1.LocalObjectReference{Name: "source"},
+			},
+		},
+	}
+
+	auth, err := SourceAuth(context.Background(), c, obj)
+	require.NoError(t, err)
+	require.NotNil(t, auth.BasicAuth)
+	assert.Equal(t, "user", auth.BasicAuth.Username)
+	assert.Equal(t, "token", auth.BasicAuth.Password)
+
+	username, password, err := MigrationCredentials(auth)
+	require.NoError(t, err)
+	assert.Equal(t, "user", username)
+	assert.Equal(t, "token", password)
+
+	secret.Data["identity"] = []byte("key")
+	require.NoError(t, c.Update(context.Background(), secret))
+
+	auth, err = SourceAuth(context.Background(), c, obj)
+	require.NoError(t, err)
+	require.NotNil(t, auth.SSH)
+
+	_, _, err = MigrationCredentials(auth)
+	assert.True(t, errors.Is(err, ErrNotSupported))
+
+	obj.Spec.Source.SecretRef = nil
+	auth, err = SourceAuth(context.Background(), c, obj)
+	require.NoError(t, err)
+	assert.Nil(t, auth)
+}
@@ -0,0 +1,39 @@
+package providers
+
+import (
+	"slices"
+
+	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+)
+
+// Webhook is a webhook of the spec with its signing secret resolved.
+type Webhook struct {
+	URL         string
+	Events      []mpasv1alpha1.WebhookEvent
+	ContentType string
+	Secret      string
+}
+
+// StaleWebhooks returns the URLs of previously managed webhooks which are no longer desired.
+func StaleWebhooks(desired []Webhook, managed []string) []string {
+	var stale []string
+
+	for _, url := range managed {
+		if !slices.ContainsFunc(desired, func(hook Webhook) bool {
+			return hook.URL == url
+		}) {
+			stale = append(stale, url)
+		}
+	}
+
+	return stale
+}
+
+// WebhookEvents returns the events of the hook, push if none are set.
+func WebhookEvents(hook Webhook) []mpasv1alpha1.WebhookEvent {
+	if len(hook.Events) == 0 {
+		return []mpasv1alpha1.WebhookEvent{mpasv1alpha1.WebhookEventPush}
+	}
+
+	return hook.Events
+}
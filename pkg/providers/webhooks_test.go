@@ -0,0 +1,30 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	mpasv1alpha1 "github.com/open-component-model/git-controller/apis/mpas/v1alpha1"
+)
+
+func TestStaleWebhooks(t *testing.T) {
+	desired := []Webhook{
+		{URL: "https://ci.example.com/hook"},
+		{URL: "https://chat.example.com/hook"},
+	}
+
+	assert.Empty(t, StaleWebhooks(desired, nil))
+	assert.Empty(t, StaleWebhooks(desired, []string{"https://ci.example.com/hook"}))
+	assert.Equal(t, []string{"https://old.example.com/hook"}, StaleWebhooks(desired, []string{
+		"https://ci.example.com/hook",
+		"https://old.example.com/hook",
+	}))
+}
+
+func TestWebhookEvents(t *testing.T) {
+	assert.Equal(t, []mpasv1alpha1.WebhookEvent{mpasv1alpha1.WebhookEventPush}, WebhookEvents(Webhook{}))
+	assert.Equal(t, []mpasv1alpha1.WebhookEvent{mpasv1alpha1.WebhookEventIssues}, WebhookEvents(Webhook{
+		Events: []mpasv1alpha1.WebhookEvent{mpasv1alpha1.WebhookEventIssues},
+	}))
+}
@@ -0,0 +1,134 @@
+// Package tracing sets up OpenTelemetry tracing for the git-controller and provides helpers to record spans.
+// Spans are no-ops until Setup installs an exporter.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/open-component-model/git-controller/pkg/version"
+)
+
+const (
+	// ExporterNone disables tracing.
+	ExporterNone = "none"
+	// ExporterOTLP sends spans to an OTLP endpoint over HTTP.
+	ExporterOTLP = "otlp"
+	// ExporterStdout writes spans to stdout. Meant for local debugging.
+	ExporterStdout = "stdout"
+
+	tracerName  = "github.com/open-component-model/git-controller"
+	serviceName = "git-controller"
+)
+
+// Options configures the exporter of the spans.
+type Options struct {
+	// Exporter is one of ExporterNone, ExporterOTLP or ExporterStdout.
+	Exporter string
+	// Endpoint is the host and port of the OTLP endpoint. If empty, the OTEL_EXPORTER_OTLP_* environment variables
+	// or the exporter's default are used.
+	Endpoint string
+	// Insecure disables TLS for the OTLP endpoint.
+	Insecure bool
+}
+
+// Setup installs the global tracer provider. The returned function flushes the remaining spans and has to be
+// called before the process exits.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+
+	switch opts.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		var clientOpts []otlptracehttp.Option
+		if opts.Endpoint != "" {
+			clientOpts = append(clientOpts, otlptracehttp.WithEndpoint(opts.Endpoint))
+		}
+
+		if opts.Insecure {
+			clientOpts = append(clientOpts, otlptracehttp.WithInsecure())
+		}
+
+		exporter, err = otlptracehttp.New(ctx, clientOpts...)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter '%s', supported exporters are: %s, %s, %s",
+			opts.Exporter, ExporterNone, ExporterOTLP, ExporterStdout)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", opts.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(version.ReleaseVersion),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider.Shutdown, nil
+}
+
+// Start starts a span as a child of the span in ctx. If ctx has no span yet, the trace ID is added to the logger
+// in the returned context, so log lines can be correlated with the trace.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	root := !trace.SpanContextFromContext(ctx).IsValid()
+
+	ctx, span := otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+
+	if root {
+		ctx = log.IntoContext(ctx, WithTraceID(ctx, log.FromContext(ctx)))
+	}
+
+	return ctx, span
+}
+
+// WithTraceID adds the ID of the trace in ctx to the logger. The logger is returned as is if ctx isn't traced.
+func WithTraceID(ctx context.Context, logger logr.Logger) logr.Logger {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.HasTraceID() {
+		return logger
+	}
+
+	return logger.WithValues("traceID", spanContext.TraceID().String())
+}
+
+// End records err on the span, if any, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
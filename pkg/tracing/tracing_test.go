@@ -0,0 +1,97 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestSetup(t *testing.T) {
+	testCases := []struct {
+		name     string
+		exporter string
+		err      string
+	}{
+		{name: "disabled by default", exporter: ""},
+		{name: "none", exporter: ExporterNone},
+		{name: "stdout", exporter: ExporterStdout},
+		{name: "unknown exporter", exporter: "jaeger", err: "unknown tracing exporter 'jaeger', supported exporters are: none, otlp, stdout"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			shutdown, err := Setup(context.Background(), Options{Exporter: tt.exporter})
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NoError(t, shutdown(context.Background()))
+		})
+	}
+}
+
+func TestStartAndEnd(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	var lines []string
+	logger := funcr.New(func(prefix, args string) {
+		lines = append(lines, args)
+	}, funcr.Options{})
+
+	ctx := log.IntoContext(context.Background(), logger)
+
+	ctx, root := Start(ctx, "Sync.Reconcile", attribute.String("name", "sync"))
+	_, child := Start(ctx, "git.Push")
+	End(child, errors.New("push rejected"))
+	End(root, nil)
+
+	log.FromContext(ctx).Info("reconciled")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	pushed, reconciled := spans[0], spans[1]
+	assert.Equal(t, "git.Push", pushed.Name())
+	assert.Equal(t, codes.Error, pushed.Status().Code)
+	assert.Equal(t, "push rejected", pushed.Status().Description)
+	assert.Len(t, pushed.Events(), 1)
+	assert.Equal(t, reconciled.SpanContext().SpanID(), pushed.Parent().SpanID())
+
+	assert.Equal(t, "Sync.Reconcile", reconciled.Name())
+	assert.Equal(t, codes.Unset, reconciled.Status().Code)
+	assert.Contains(t, reconciled.Attributes(), attribute.String("name", "sync"))
+
+	require.Len(t, lines, 1)
+	assert.True(t, strings.Contains(lines[0], `"traceID"="`+reconciled.SpanContext().TraceID().String()+`"`), lines[0])
+}
+
+func TestWithTraceIDWithoutSpan(t *testing.T) {
+	var lines []string
+	logger := funcr.New(func(prefix, args string) {
+		lines = append(lines, args)
+	}, funcr.Options{})
+
+	WithTraceID(context.Background(), logger).Info("untraced")
+
+	require.Len(t, lines, 1)
+	assert.NotContains(t, lines[0], "traceID")
+}